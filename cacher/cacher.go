@@ -2,14 +2,25 @@ package cacher
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// ErrFetchTimeout is returned by GetOrFetchTimeout when the fetch function does not
+// complete within the fetch timeout.
+var ErrFetchTimeout = errors.New("fetch timed out")
+
 // FetchFunc is a function that fetches a value from the source when a cache miss occurs.
 // It receives a context for cancellation and timeout control, and returns the value
 // of type T or an error if the fetch operation fails.
 type FetchFunc[T any] func(ctx context.Context) (T, error)
 
+// FetchManyFunc fetches the values for several keys at once, for example with a single
+// SQL IN query, when GetOrFetchMany finds them missing from the cache. It returns the
+// values it found keyed by cache key; keys it leaves out are treated as not found.
+type FetchManyFunc[T any] func(ctx context.Context, missingKeys []string) (map[string]T, error)
+
 // Cacher is an interface that defines methods for caching values with automatic
 // fetching on cache misses. Implementations should provide thread-safe caching
 // and handle cache stampede prevention when multiple concurrent requests occur
@@ -35,6 +46,51 @@ type Cacher[T any] interface {
 		fetchFn FetchFunc[T],
 	) (T, error)
 
+	// GetOrFetchTimeout behaves like GetOrFetch, but bounds the fetch call by fetchTimeout
+	// independently of ctx. fetchFn receives a child context of ctx that expires after
+	// fetchTimeout; if the fetch has not returned by then, an error wrapping ErrFetchTimeout
+	// is returned. A fetchTimeout <= 0 disables the bound.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//   - key: The cache key to retrieve or set
+	//   - ttl: Time-to-live duration for the cached value
+	//   - fetchTimeout: Maximum duration of the fetch call
+	//   - fetchFn: Function to fetch the value if not in cache
+	//
+	// Returns:
+	//   - The cached or fetched value of type T
+	//   - An error if retrieval or fetching fails, or one wrapping ErrFetchTimeout
+	GetOrFetchTimeout(
+		ctx context.Context,
+		key string,
+		ttl time.Duration,
+		fetchTimeout time.Duration,
+		fetchFn FetchFunc[T],
+	) (T, error)
+
+	// GetOrFetchMany retrieves several values at once. Keys found in the cache are served
+	// from it; fetchFn is called once with only the missing keys (deduplicated, in the order
+	// first requested), and the values it returns are cached with the specified TTL. fetchFn
+	// is not called when every key is cached. Keys fetchFn does not return are left out of
+	// the result and not cached.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//   - keys: The cache keys to retrieve
+	//   - ttl: Time-to-live duration for fetched values
+	//   - fetchFn: Function to fetch the missing keys in one call
+	//
+	// Returns:
+	//   - The cached and fetched values keyed by cache key
+	//   - An error if retrieval or fetching fails
+	GetOrFetchMany(
+		ctx context.Context,
+		keys []string,
+		ttl time.Duration,
+		fetchFn FetchManyFunc[T],
+	) (map[string]T, error)
+
 	// Delete removes a key from the cache.
 	//
 	// Parameters:
@@ -72,3 +128,98 @@ type Cacher[T any] interface {
 	//   - An error if the operation fails
 	DeleteByPrefix(ctx context.Context, prefix string) (int, error)
 }
+
+// TaggedCacher is a Cacher whose entries can carry tags, so that related entries can be
+// invalidated together regardless of the shape of their keys (e.g. every entry about
+// user 42). MemoryCacher and the Redis cacher implement it; type-assert the Cacher
+// returned by NewMemoryCacher or NewRedisCacher to use it.
+type TaggedCacher[T any] interface {
+	Cacher[T]
+
+	// SetWithTags stores value under key with the given TTL and adds key to each tag.
+	// Tags already attached to key are kept.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//   - key: The cache key to set
+	//   - value: The value to store
+	//   - ttl: Time-to-live duration for the cached value
+	//   - tags: Tags to attach to key
+	//
+	// Returns:
+	//   - An error if the operation fails
+	SetWithTags(ctx context.Context, key string, value T, ttl time.Duration, tags ...string) error
+
+	// InvalidateTag deletes every key carrying tag and forgets the tag.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//   - tag: The tag whose keys are deleted
+	//
+	// Returns:
+	//   - The number of keys deleted
+	//   - An error if the operation fails
+	InvalidateTag(ctx context.Context, tag string) (int, error)
+}
+
+// withFetchTimeout wraps fetchFn so that it runs with a child context bounded by
+// fetchTimeout. The wrapper returns as soon as the timeout elapses, even if fetchFn
+// ignores its context; in that case fetchFn keeps running in the background until it
+// returns and its result is discarded.
+func withFetchTimeout[T any](fetchTimeout time.Duration, fetchFn FetchFunc[T]) FetchFunc[T] {
+	if fetchTimeout <= 0 {
+		return fetchFn
+	}
+
+	return func(ctx context.Context) (T, error) {
+		var zero T
+
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		defer cancel()
+
+		type result struct {
+			val T
+			err error
+		}
+
+		done := make(chan result, 1)
+		go func() {
+			val, err := fetchFn(fetchCtx)
+			done <- result{val: val, err: err}
+		}()
+
+		select {
+		case res := <-done:
+			if res.err != nil && ctx.Err() == nil && errors.Is(fetchCtx.Err(), context.DeadlineExceeded) {
+				return zero, fmt.Errorf("%w after %s: %w", ErrFetchTimeout, fetchTimeout, res.err)
+			}
+
+			return res.val, res.err
+		case <-fetchCtx.Done():
+			if err := ctx.Err(); err != nil {
+				return zero, err
+			}
+
+			return zero, fmt.Errorf("%w after %s", ErrFetchTimeout, fetchTimeout)
+		}
+	}
+}
+
+// missingKeys returns the distinct keys, in the order first seen, for which lookup
+// reports false. lookup is called once per distinct key.
+func missingKeys(keys []string, lookup func(key string) bool) []string {
+	seen := make(map[string]struct{}, len(keys))
+	var missing []string
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if !lookup(key) {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,123 @@
+// Package cacherprom exports cacher metrics to Prometheus. It lives in its own
+// package so that users of cacher who do not use Prometheus do not import it.
+package cacherprom
+
+import (
+	"context"
+	"time"
+
+	"github.com/cyberinferno/go-utils/cacher"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// itemCountTimeout bounds the ItemCount call made on every scrape.
+const itemCountTimeout = 5 * time.Second
+
+// Collector is a prometheus.Collector for a single Cacher. It is also a
+// cacher.Observer: wrap the cacher with cacher.WithObserver(c, collector) so
+// that cache activity is counted. It exports, under the given namespace:
+//   - cache_hits_total: GetOrFetch calls served without calling fetchFn
+//   - cache_misses_total: GetOrFetch calls that called fetchFn
+//   - cache_fetch_errors_total: fetchFn calls that returned an error
+//   - cache_fetch_duration_seconds: histogram of fetchFn durations
+//   - cache_items: current number of items, read from the cacher on each scrape
+type Collector struct {
+	hits          prometheus.Counter
+	misses        prometheus.Counter
+	fetchErrors   prometheus.Counter
+	fetchDuration prometheus.Histogram
+	items         *prometheus.Desc
+	itemCount     func(ctx context.Context) (int, error)
+}
+
+// PrometheusCollector creates a Collector for c. Register it with a Prometheus
+// registry and use the cacher returned by cacher.WithObserver(c, collector).
+//
+// Parameters:
+//   - namespace: Metric namespace, e.g. the application name; may be empty
+//   - c: The cacher whose item count is reported
+//
+// Returns:
+//   - A new Collector
+func PrometheusCollector[T any](namespace string, c cacher.Cacher[T]) *Collector {
+	return &Collector{
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "hits_total",
+			Help:      "Number of cache lookups served without calling the fetch function.",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "misses_total",
+			Help:      "Number of cache lookups that called the fetch function.",
+		}),
+		fetchErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "fetch_errors_total",
+			Help:      "Number of fetch function calls that returned an error.",
+		}),
+		fetchDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "fetch_duration_seconds",
+			Help:      "Duration of fetch function calls.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		items: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "items"),
+			"Number of items in the cache.",
+			nil, nil,
+		),
+		itemCount: c.ItemCount,
+	}
+}
+
+// OnHit implements cacher.Observer.
+func (c *Collector) OnHit(key string) {
+	c.hits.Inc()
+}
+
+// OnMiss implements cacher.Observer.
+func (c *Collector) OnMiss(key string) {
+	c.misses.Inc()
+}
+
+// OnFetch implements cacher.Observer.
+func (c *Collector) OnFetch(key string, duration time.Duration, err error) {
+	c.fetchDuration.Observe(duration.Seconds())
+	if err != nil {
+		c.fetchErrors.Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.hits.Describe(ch)
+	c.misses.Describe(ch)
+	c.fetchErrors.Describe(ch)
+	c.fetchDuration.Describe(ch)
+	ch <- c.items
+}
+
+// Collect implements prometheus.Collector. If the item count cannot be read,
+// an invalid metric carrying the error is reported in its place.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.hits.Collect(ch)
+	c.misses.Collect(ch)
+	c.fetchErrors.Collect(ch)
+	c.fetchDuration.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), itemCountTimeout)
+	defer cancel()
+
+	count, err := c.itemCount(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.items, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.items, prometheus.GaugeValue, float64(count))
+}
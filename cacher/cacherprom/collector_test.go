@@ -0,0 +1,63 @@
+package cacherprom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyberinferno/go-utils/cacher"
+	"github.com/patrickmn/go-cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusCollector(t *testing.T) {
+	base := cacher.NewMemoryCacher[string](cache.NoExpiration, time.Minute)
+	collector := PrometheusCollector("app", base)
+	c := cacher.WithObserver(base, collector)
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	ctx := context.Background()
+	fetch := func(ctx context.Context) (string, error) { return "value", nil }
+
+	// One miss, then two hits
+	for i := 0; i < 3; i++ {
+		val, err := c.GetOrFetch(ctx, "key", time.Minute, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "value", val)
+	}
+
+	// One failed fetch
+	_, err := c.GetOrFetch(ctx, "broken", time.Minute, func(ctx context.Context) (string, error) {
+		return "", errors.New("source down")
+	})
+	require.Error(t, err)
+
+	expected := `
+# HELP app_cache_fetch_errors_total Number of fetch function calls that returned an error.
+# TYPE app_cache_fetch_errors_total counter
+app_cache_fetch_errors_total 1
+# HELP app_cache_hits_total Number of cache lookups served without calling the fetch function.
+# TYPE app_cache_hits_total counter
+app_cache_hits_total 2
+# HELP app_cache_items Number of items in the cache.
+# TYPE app_cache_items gauge
+app_cache_items 1
+# HELP app_cache_misses_total Number of cache lookups that called the fetch function.
+# TYPE app_cache_misses_total counter
+app_cache_misses_total 2
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"app_cache_hits_total", "app_cache_misses_total", "app_cache_fetch_errors_total", "app_cache_items")
+	assert.NoError(t, err)
+
+	count, err := testutil.GatherAndCount(reg, "app_cache_fetch_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,83 @@
+package cacher
+
+import "sync"
+
+// KeyedMutex serializes work per key within a process: callers locking the same
+// key run one at a time, while different keys proceed in parallel. It is the
+// in-process counterpart of RedisLock. Entries are reference counted and
+// removed once no goroutine holds or waits for the key, so memory does not grow
+// with the number of distinct keys ever locked.
+//
+// The zero value is ready to use. A KeyedMutex must not be copied after first use.
+type KeyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock is the mutex for one key and the number of goroutines holding or
+// waiting for it.
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewKeyedMutex creates an empty KeyedMutex.
+//
+// Returns:
+//   - A new KeyedMutex
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{locks: make(map[string]*keyedLock)}
+}
+
+// Lock blocks until the lock for key is held and returns the function that
+// releases it.
+//
+// Example:
+//
+//	unlock := km.Lock("user:42")
+//	defer unlock()
+//
+// Parameters:
+//   - key: The key to lock
+//
+// Returns:
+//   - unlock: Releases the lock; safe to call more than once
+func (k *KeyedMutex) Lock(key string) (unlock func()) {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Unlock()
+
+			k.mu.Lock()
+			l.refs--
+			if l.refs == 0 {
+				delete(k.locks, key)
+			}
+			k.mu.Unlock()
+		})
+	}
+}
+
+// Len returns the number of keys currently held or waited for.
+//
+// Returns:
+//   - The number of tracked keys
+func (k *KeyedMutex) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.locks)
+}
@@ -0,0 +1,106 @@
+package cacher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrencyGauge records the most goroutines seen inside a section at once.
+type concurrencyGauge struct {
+	mu     sync.Mutex
+	active int
+	peak   int
+}
+
+func (g *concurrencyGauge) enter() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active++
+	g.peak = max(g.peak, g.active)
+}
+
+func (g *concurrencyGauge) leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+}
+
+func (g *concurrencyGauge) highest() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.peak
+}
+
+func TestKeyedMutex(t *testing.T) {
+	t.Run("same key serializes", func(t *testing.T) {
+		km := NewKeyedMutex()
+		var gauge concurrencyGauge
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock := km.Lock("shared")
+				defer unlock()
+
+				gauge.enter()
+				time.Sleep(2 * time.Millisecond)
+				gauge.leave()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, gauge.highest())
+		assert.Equal(t, 0, km.Len(), "entry not cleaned up")
+	})
+
+	t.Run("different keys run in parallel", func(t *testing.T) {
+		km := NewKeyedMutex()
+		unlockA := km.Lock("a")
+		defer unlockA()
+
+		done := make(chan struct{})
+		go func() {
+			unlock := km.Lock("b")
+			unlock()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("lock on b blocked while a was held")
+		}
+	})
+
+	t.Run("waiter proceeds after unlock", func(t *testing.T) {
+		var km KeyedMutex // zero value is usable
+		unlock := km.Lock("k")
+
+		acquired := make(chan struct{})
+		go func() {
+			defer close(acquired)
+			km.Lock("k")()
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("second Lock acquired while the key was held")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		unlock()
+		unlock() // second call is a no-op
+
+		select {
+		case <-acquired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("waiter did not acquire after unlock")
+		}
+		assert.Eventually(t, func() bool { return km.Len() == 0 }, time.Second, 5*time.Millisecond)
+	})
+}
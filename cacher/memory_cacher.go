@@ -15,8 +15,64 @@ import (
 // (thundering herd problem) when multiple concurrent requests occur for the
 // same cache key.
 type MemoryCacher[T any] struct {
-	cache *cache.Cache
-	group singleflight.Group
+	cache             *cache.Cache
+	group             singleflight.Group
+	defaultExpiration time.Duration
+
+	// stale retains the last successfully fetched value for each key for
+	// staleGracePeriod beyond its TTL; nil when stale fallback is disabled.
+	stale            *cache.Cache
+	staleGracePeriod time.Duration
+	onStale          StaleHandler
+
+	// index tracks keys by prefix; nil unless WithPrefixIndex is used.
+	index          *prefixIndex
+	indexSeparator string
+
+	// tags tracks the keys attached to each tag by SetWithTags.
+	tags *tagIndex
+}
+
+// MemoryCacherOption configures optional behavior of a MemoryCacher.
+type MemoryCacherOption[T any] func(*MemoryCacher[T])
+
+// StaleHandler is called when GetOrFetch serves a stale value because the
+// fetch function failed. It receives the cache key and the fetch error.
+type StaleHandler func(key string, fetchErr error)
+
+// WithStaleFallback enables serving stale values when a fetch fails. Fetched
+// values are retained for gracePeriod after their TTL expires; if fetchFn
+// returns an error and a retained value exists, GetOrFetch returns that value
+// with a nil error and calls onStale (if non-nil) so callers can observe it.
+//
+// Parameters:
+//   - gracePeriod: How long values are retained after expiry for stale fallback
+//   - onStale: Optional function called whenever a stale value is served
+//
+// Returns:
+//   - A MemoryCacherOption to pass to NewMemoryCacher
+func WithStaleFallback[T any](gracePeriod time.Duration, onStale StaleHandler) MemoryCacherOption[T] {
+	return func(c *MemoryCacher[T]) {
+		c.staleGracePeriod = gracePeriod
+		c.onStale = onStale
+	}
+}
+
+// WithPrefixIndex maintains a secondary index from every separator-terminated key prefix
+// to the keys sharing it, so that DeleteByPrefix and KeysByPrefix cost O(matching keys)
+// instead of scanning the whole cache. Only prefixes ending in separator (e.g. "user:" or
+// "user:42:" for ":") use the index; other prefixes fall back to a full scan. The index
+// costs extra memory per key and per prefix, so it is opt-in.
+//
+// Parameters:
+//   - separator: The separator between key segments (e.g. ":"); must not be empty
+//
+// Returns:
+//   - A MemoryCacherOption to pass to NewMemoryCacher
+func WithPrefixIndex[T any](separator string) MemoryCacherOption[T] {
+	return func(c *MemoryCacher[T]) {
+		c.indexSeparator = separator
+	}
 }
 
 // NewMemoryCacher creates a new in-memory cache instance with the specified
@@ -25,14 +81,35 @@ type MemoryCacher[T any] struct {
 // Parameters:
 //   - defaultExpiration: Default TTL for cached items (use cache.NoExpiration for no default)
 //   - cleanupInterval: Interval at which expired items are removed from the cache
+//   - opts: Optional settings such as WithStaleFallback and WithPrefixIndex
 //
 // Returns:
 //   - A new InMemoryCacher instance
-func NewMemoryCacher[T any](defaultExpiration, cleanupInterval time.Duration) Cacher[T] {
-	return &MemoryCacher[T]{
-		cache: cache.New(defaultExpiration, cleanupInterval),
-		group: singleflight.Group{},
+func NewMemoryCacher[T any](defaultExpiration, cleanupInterval time.Duration, opts ...MemoryCacherOption[T]) Cacher[T] {
+	c := &MemoryCacher[T]{
+		cache:             cache.New(defaultExpiration, cleanupInterval),
+		group:             singleflight.Group{},
+		defaultExpiration: defaultExpiration,
+		tags:              newTagIndex(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.staleGracePeriod > 0 {
+		c.stale = cache.New(cache.NoExpiration, cleanupInterval)
+	}
+
+	if c.indexSeparator != "" {
+		c.index = newPrefixIndex(c.indexSeparator)
+		if c.stale != nil {
+			c.stale.OnEvicted(func(key string, _ interface{}) { c.unindex(key) })
+		}
 	}
+	c.cache.OnEvicted(c.evicted)
+
+	return c
 }
 
 // GetOrFetch retrieves a value from the cache, or fetches it using the provided
@@ -63,6 +140,9 @@ func (c *MemoryCacher[T]) GetOrFetch(
 		}
 	}
 
+	// Without a cleanup interval expired keys are never evicted, so drop their tags here
+	c.untag(key)
+
 	// Use singleflight to prevent thundering herd
 	// Only one fetch will be executed for concurrent requests with the same key
 	val, err, _ := c.group.Do(key, func() (interface{}, error) {
@@ -77,11 +157,19 @@ func (c *MemoryCacher[T]) GetOrFetch(
 		// Fetch the value
 		fetchedVal, err := fetchFn(ctx)
 		if err != nil {
+			if staleVal, ok := c.getStale(key); ok {
+				if c.onStale != nil {
+					c.onStale(key, err)
+				}
+
+				return staleVal, nil
+			}
+
 			return zero, err
 		}
 
 		// Store in cache with specified TTL
-		c.cache.Set(key, fetchedVal, ttl)
+		c.store(key, fetchedVal, ttl)
 
 		return fetchedVal, nil
 	})
@@ -99,6 +187,171 @@ func (c *MemoryCacher[T]) GetOrFetch(
 	return typedVal, nil
 }
 
+// GetOrFetchTimeout behaves like GetOrFetch, but bounds the fetch call by fetchTimeout
+// independently of ctx. A fetch that times out is treated like any other fetch error,
+// so a stale value is served when stale fallback is enabled.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - key: The cache key to retrieve or set
+//   - ttl: Time-to-live duration for the cached value
+//   - fetchTimeout: Maximum duration of the fetch call; <= 0 disables the bound
+//   - fetchFn: Function to fetch the value if not in cache
+//
+// Returns:
+//   - The cached or fetched value of type T
+//   - An error if retrieval or fetching fails, or one wrapping ErrFetchTimeout
+func (c *MemoryCacher[T]) GetOrFetchTimeout(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	fetchTimeout time.Duration,
+	fetchFn FetchFunc[T],
+) (T, error) {
+	return c.GetOrFetch(ctx, key, ttl, withFetchTimeout(fetchTimeout, fetchFn))
+}
+
+// GetOrFetchMany retrieves several values at once, calling fetchFn once for the keys
+// that are not cached. Unlike GetOrFetch, concurrent calls are not coalesced, and a
+// failed fetch returns its error without falling back to stale values.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - keys: The cache keys to retrieve
+//   - ttl: Time-to-live duration for fetched values
+//   - fetchFn: Function to fetch the missing keys in one call
+//
+// Returns:
+//   - The cached and fetched values keyed by cache key
+//   - An error if fetching fails
+func (c *MemoryCacher[T]) GetOrFetchMany(
+	ctx context.Context,
+	keys []string,
+	ttl time.Duration,
+	fetchFn FetchManyFunc[T],
+) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+	missing := missingKeys(keys, func(key string) bool {
+		if val, found := c.cache.Get(key); found {
+			if typedVal, ok := val.(T); ok {
+				result[key] = typedVal
+				return true
+			}
+		}
+
+		return false
+	})
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	for _, key := range missing {
+		c.untag(key)
+	}
+
+	fetched, err := fetchFn(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range missing {
+		if val, ok := fetched[key]; ok {
+			c.store(key, val, ttl)
+			result[key] = val
+		}
+	}
+
+	return result, nil
+}
+
+// store caches value under key and records it in the prefix index. The index lock is
+// held across the cache writes so that a concurrent eviction cannot unindex a key that
+// has just been stored again.
+func (c *MemoryCacher[T]) store(key string, value T, ttl time.Duration) {
+	if c.index != nil {
+		c.index.mu.Lock()
+		defer c.index.mu.Unlock()
+		c.index.add(key)
+	}
+
+	c.cache.Set(key, value, ttl)
+	c.setStale(key, value, ttl)
+}
+
+// evicted is called by the cache whenever key is deleted or expires.
+func (c *MemoryCacher[T]) evicted(key string, _ interface{}) {
+	if c.index != nil {
+		c.unindex(key)
+	}
+	c.untag(key)
+}
+
+// untag removes key from all of its tags unless it has been stored again. It is called
+// from the eviction callback and on cache misses.
+func (c *MemoryCacher[T]) untag(key string) {
+	c.tags.mu.Lock()
+	defer c.tags.mu.Unlock()
+
+	if _, found := c.cache.Get(key); found {
+		return
+	}
+
+	c.tags.remove(key)
+}
+
+// unindex removes key from the prefix index once it is held by neither the cache
+// nor the stale store. It is called from the caches' eviction callbacks.
+func (c *MemoryCacher[T]) unindex(key string) {
+	c.index.mu.Lock()
+	defer c.index.mu.Unlock()
+
+	if _, found := c.cache.Get(key); found {
+		return
+	}
+	if c.stale != nil {
+		if _, found := c.stale.Get(key); found {
+			return
+		}
+	}
+
+	c.index.remove(key)
+}
+
+// getStale returns the retained value for key when stale fallback is enabled.
+func (c *MemoryCacher[T]) getStale(key string) (T, bool) {
+	var zero T
+	if c.stale == nil {
+		return zero, false
+	}
+
+	val, found := c.stale.Get(key)
+	if !found {
+		return zero, false
+	}
+
+	typedVal, ok := val.(T)
+	return typedVal, ok
+}
+
+// setStale retains value for the stale fallback until ttl plus the grace period elapses.
+func (c *MemoryCacher[T]) setStale(key string, value T, ttl time.Duration) {
+	if c.stale == nil {
+		return
+	}
+
+	if ttl == cache.DefaultExpiration {
+		ttl = c.defaultExpiration
+	}
+
+	if ttl <= 0 {
+		c.stale.Set(key, value, cache.NoExpiration)
+		return
+	}
+
+	c.stale.Set(key, value, ttl+c.staleGracePeriod)
+}
+
 // Delete removes a key from the cache.
 func (c *MemoryCacher[T]) Delete(ctx context.Context, key string) error {
 	select {
@@ -107,6 +360,9 @@ func (c *MemoryCacher[T]) Delete(ctx context.Context, key string) error {
 	default:
 	}
 	c.cache.Delete(key)
+	if c.stale != nil {
+		c.stale.Delete(key)
+	}
 	return nil
 }
 
@@ -118,6 +374,13 @@ func (c *MemoryCacher[T]) Clear(ctx context.Context) error {
 	default:
 	}
 	c.cache.Flush()
+	if c.stale != nil {
+		c.stale.Flush()
+	}
+	if c.index != nil {
+		c.index.reset()
+	}
+	c.tags.reset()
 	return nil
 }
 
@@ -139,6 +402,10 @@ func (c *MemoryCacher[T]) DeleteByPrefix(ctx context.Context, prefix string) (in
 	default:
 	}
 
+	if c.index != nil && c.index.indexable(prefix) {
+		return c.deleteIndexed(ctx, prefix)
+	}
+
 	items := c.cache.Items()
 	deletedCount := 0
 
@@ -156,5 +423,138 @@ func (c *MemoryCacher[T]) DeleteByPrefix(ctx context.Context, prefix string) (in
 		}
 	}
 
+	if c.stale != nil {
+		for key := range c.stale.Items() {
+			if strings.HasPrefix(key, prefix) {
+				c.stale.Delete(key)
+			}
+		}
+	}
+
+	return deletedCount, nil
+}
+
+// deleteIndexed deletes the keys indexed under prefix. Eviction callbacks remove the
+// deleted keys from the index.
+func (c *MemoryCacher[T]) deleteIndexed(ctx context.Context, prefix string) (int, error) {
+	deletedCount := 0
+	for _, key := range c.index.keys(prefix) {
+		select {
+		case <-ctx.Done():
+			return deletedCount, ctx.Err()
+		default:
+		}
+
+		if _, found := c.cache.Get(key); found {
+			deletedCount++
+		}
+
+		c.cache.Delete(key)
+		if c.stale != nil {
+			c.stale.Delete(key)
+		}
+	}
+
+	return deletedCount, nil
+}
+
+// KeysByPrefix returns the keys of unexpired items that start with prefix, in no
+// particular order. With WithPrefixIndex and a separator-terminated prefix, it only
+// visits matching keys; otherwise it scans the whole cache.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - prefix: The prefix to match keys against
+//
+// Returns:
+//   - The matching keys
+//   - An error if the context is done
+func (c *MemoryCacher[T]) KeysByPrefix(ctx context.Context, prefix string) ([]string, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	var keys []string
+	if c.index != nil && c.index.indexable(prefix) {
+		for _, key := range c.index.keys(prefix) {
+			if _, found := c.cache.Get(key); found {
+				keys = append(keys, key)
+			}
+		}
+
+		return keys, nil
+	}
+
+	for key := range c.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// SetWithTags stores value under key with the given TTL and attaches tags to it, for
+// later removal with InvalidateTag. Tags already attached to key are kept. A key's tags
+// are forgotten once it is deleted or expires.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - key: The cache key to set
+//   - value: The value to store
+//   - ttl: Time-to-live duration for the cached value
+//   - tags: Tags to attach to key
+//
+// Returns:
+//   - An error if the context is done
+func (c *MemoryCacher[T]) SetWithTags(ctx context.Context, key string, value T, ttl time.Duration, tags ...string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// Held across the store so that a concurrent eviction cannot untag the new entry
+	c.tags.mu.Lock()
+	defer c.tags.mu.Unlock()
+
+	c.tags.add(key, tags)
+	c.store(key, value, ttl)
+
+	return nil
+}
+
+// InvalidateTag deletes every key tagged with tag by SetWithTags, including retained
+// stale values, and forgets the tag. It costs O(tagged keys).
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - tag: The tag whose keys are deleted
+//
+// Returns:
+//   - The number of unexpired keys deleted
+//   - An error if the context is done
+func (c *MemoryCacher[T]) InvalidateTag(ctx context.Context, tag string) (int, error) {
+	deletedCount := 0
+	for _, key := range c.tags.keysOf(tag) {
+		select {
+		case <-ctx.Done():
+			return deletedCount, ctx.Err()
+		default:
+		}
+
+		if _, found := c.cache.Get(key); found {
+			deletedCount++
+		}
+
+		// The eviction callback removes key from its tags
+		c.cache.Delete(key)
+		if c.stale != nil {
+			c.stale.Delete(key)
+		}
+	}
+
 	return deletedCount, nil
 }
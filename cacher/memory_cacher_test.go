@@ -2,6 +2,7 @@ package cacher
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -377,3 +378,404 @@ func TestMemoryCacher_Interface(t *testing.T) {
 	// Ensure MemoryCacher implements Cacher
 	var _ Cacher[string] = (*MemoryCacher[string])(nil)
 }
+
+func TestMemoryCacher_GetOrFetch_StaleFallback(t *testing.T) {
+	ctx := context.Background()
+
+	var staleKey string
+	var staleErr error
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute,
+		WithStaleFallback[string](time.Minute, func(key string, fetchErr error) {
+			staleKey = key
+			staleErr = fetchErr
+		}),
+	)
+
+	val, err := c.GetOrFetch(ctx, "key", 20*time.Millisecond, func(ctx context.Context) (string, error) {
+		return "fresh", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", val)
+
+	time.Sleep(40 * time.Millisecond)
+
+	// Entry has expired and the source is down - the stale value is served
+	val, err = c.GetOrFetch(ctx, "key", 20*time.Millisecond, func(ctx context.Context) (string, error) {
+		return "", assert.AnError
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", val)
+	assert.Equal(t, "key", staleKey)
+	assert.ErrorIs(t, staleErr, assert.AnError)
+}
+
+func TestMemoryCacher_GetOrFetch_StaleFallbackRefreshesOnSuccess(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithStaleFallback[string](time.Minute, nil))
+
+	_, err := c.GetOrFetch(ctx, "key", 20*time.Millisecond, func(ctx context.Context) (string, error) {
+		return "v1", nil
+	})
+	require.NoError(t, err)
+
+	time.Sleep(40 * time.Millisecond)
+
+	val, err := c.GetOrFetch(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		return "v2", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", val)
+}
+
+func TestMemoryCacher_GetOrFetch_StaleFallbackWithoutStaleValue(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithStaleFallback[string](time.Minute, nil))
+
+	_, err := c.GetOrFetch(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		return "", assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestMemoryCacher_GetOrFetch_StaleFallbackAfterGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithStaleFallback[string](20*time.Millisecond, nil))
+
+	_, err := c.GetOrFetch(ctx, "key", 20*time.Millisecond, func(ctx context.Context) (string, error) {
+		return "fresh", nil
+	})
+	require.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = c.GetOrFetch(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		return "", assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestMemoryCacher_Delete_RemovesStaleValue(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithStaleFallback[string](time.Minute, nil))
+
+	_, err := c.GetOrFetch(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		return "fresh", nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Delete(ctx, "key"))
+
+	_, err = c.GetOrFetch(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		return "", assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestMemoryCacher_GetOrFetchTimeout(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute).(*MemoryCacher[string])
+	ctx := context.Background()
+
+	val, err := c.GetOrFetchTimeout(ctx, "key", time.Minute, time.Second, func(ctx context.Context) (string, error) {
+		return "value", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "value", val)
+
+	cached, found := c.cache.Get("key")
+	require.True(t, found)
+	assert.Equal(t, "value", cached)
+}
+
+func TestMemoryCacher_GetOrFetchTimeout_SlowFetch(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute).(*MemoryCacher[string])
+
+	// The caller's context has a long deadline; the fetch timeout must still fire
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	slowFetch := func(ctx context.Context) (string, error) {
+		<-release // ignores ctx on purpose
+		return "late", nil
+	}
+
+	start := time.Now()
+	val, err := c.GetOrFetchTimeout(ctx, "key", time.Minute, 20*time.Millisecond, slowFetch)
+	assert.ErrorIs(t, err, ErrFetchTimeout)
+	assert.Empty(t, val)
+	assert.Less(t, time.Since(start), time.Second)
+
+	_, found := c.cache.Get("key")
+	assert.False(t, found)
+}
+
+func TestMemoryCacher_GetOrFetchTimeout_ContextAwareFetch(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute).(*MemoryCacher[string])
+
+	fetchFn := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	_, err := c.GetOrFetchTimeout(context.Background(), "key", time.Minute, 20*time.Millisecond, fetchFn)
+	assert.ErrorIs(t, err, ErrFetchTimeout)
+}
+
+// seed stores value under key through GetOrFetch, since Cacher has no Set method.
+func seed[T any](t *testing.T, c Cacher[T], key string, value T) {
+	t.Helper()
+	_, err := c.GetOrFetch(context.Background(), key, time.Minute, func(ctx context.Context) (T, error) {
+		return value, nil
+	})
+	require.NoError(t, err)
+}
+
+func TestMemoryCacher_GetOrFetchMany(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fetches only the missing keys", func(t *testing.T) {
+		c := NewMemoryCacher[string](cache.NoExpiration, time.Minute).(*MemoryCacher[string])
+		seed[string](t, c, "a", "cached-a")
+		seed[string](t, c, "c", "cached-c")
+
+		var requested []string
+		vals, err := c.GetOrFetchMany(ctx, []string{"a", "b", "c", "d", "b"}, time.Minute,
+			func(ctx context.Context, missingKeys []string) (map[string]string, error) {
+				requested = missingKeys
+				return map[string]string{"b": "fetched-b", "d": "fetched-d"}, nil
+			})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "d"}, requested)
+		assert.Equal(t, map[string]string{
+			"a": "cached-a",
+			"b": "fetched-b",
+			"c": "cached-c",
+			"d": "fetched-d",
+		}, vals)
+
+		cached, found := c.cache.Get("d")
+		require.True(t, found)
+		assert.Equal(t, "fetched-d", cached)
+	})
+
+	t.Run("skips the fetch when every key is cached", func(t *testing.T) {
+		c := NewMemoryCacher[string](cache.NoExpiration, time.Minute)
+		seed[string](t, c, "a", "cached-a")
+
+		vals, err := c.GetOrFetchMany(ctx, []string{"a"}, time.Minute,
+			func(ctx context.Context, missingKeys []string) (map[string]string, error) {
+				t.Fatal("fetchFn should not be called")
+				return nil, nil
+			})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "cached-a"}, vals)
+	})
+
+	t.Run("keys the fetch does not return are left out", func(t *testing.T) {
+		c := NewMemoryCacher[string](cache.NoExpiration, time.Minute).(*MemoryCacher[string])
+
+		vals, err := c.GetOrFetchMany(ctx, []string{"a", "b"}, time.Minute,
+			func(ctx context.Context, missingKeys []string) (map[string]string, error) {
+				return map[string]string{"a": "fetched-a", "x": "unrequested"}, nil
+			})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "fetched-a"}, vals)
+
+		_, found := c.cache.Get("b")
+		assert.False(t, found)
+		_, found = c.cache.Get("x")
+		assert.False(t, found, "keys that were not requested are not cached")
+	})
+
+	t.Run("returns the fetch error", func(t *testing.T) {
+		c := NewMemoryCacher[string](cache.NoExpiration, time.Minute)
+		fetchErr := errors.New("source down")
+
+		vals, err := c.GetOrFetchMany(ctx, []string{"a"}, time.Minute,
+			func(ctx context.Context, missingKeys []string) (map[string]string, error) {
+				return nil, fetchErr
+			})
+		assert.ErrorIs(t, err, fetchErr)
+		assert.Nil(t, vals)
+	})
+}
+
+func TestMemoryCacher_PrefixIndex_DeleteByPrefix(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithPrefixIndex[string](":")).(*MemoryCacher[string])
+	ctx := context.Background()
+
+	fetchFn := func(ctx context.Context) (string, error) { return "v", nil }
+	for _, key := range []string{"user:1", "user:1:posts", "user:2", "order:1", "userless"} {
+		_, _ = c.GetOrFetch(ctx, key, time.Minute, fetchFn)
+	}
+
+	n, err := c.DeleteByPrefix(ctx, "user:1:")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = c.DeleteByPrefix(ctx, "user:")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	keys, err := c.KeysByPrefix(ctx, "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"order:1", "userless"}, keys)
+
+	assert.Empty(t, c.index.keys("user:"))
+	assert.Empty(t, c.index.keys("user:1:"))
+}
+
+func TestMemoryCacher_PrefixIndex_NonSeparatorPrefixFallsBack(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithPrefixIndex[string](":")).(*MemoryCacher[string])
+	ctx := context.Background()
+
+	fetchFn := func(ctx context.Context) (string, error) { return "v", nil }
+	for _, key := range []string{"user:1", "userless", "order:1"} {
+		_, _ = c.GetOrFetch(ctx, key, time.Minute, fetchFn)
+	}
+
+	n, err := c.DeleteByPrefix(ctx, "user")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	count, _ := c.ItemCount(ctx)
+	assert.Equal(t, 1, count)
+	assert.Empty(t, c.index.keys("user:"))
+}
+
+func TestMemoryCacher_PrefixIndex_KeysByPrefix(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithPrefixIndex[string](":")).(*MemoryCacher[string])
+	ctx := context.Background()
+
+	fetchFn := func(ctx context.Context) (string, error) { return "v", nil }
+	for _, key := range []string{"user:1", "user:2", "order:1"} {
+		_, _ = c.GetOrFetch(ctx, key, time.Minute, fetchFn)
+	}
+	require.NoError(t, c.Delete(ctx, "user:2"))
+
+	keys, err := c.KeysByPrefix(ctx, "user:")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user:1"}, keys)
+}
+
+func TestMemoryCacher_PrefixIndex_ExpiryAndClear(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, 10*time.Millisecond, WithPrefixIndex[string](":")).(*MemoryCacher[string])
+	ctx := context.Background()
+
+	fetchFn := func(ctx context.Context) (string, error) { return "v", nil }
+	_, _ = c.GetOrFetch(ctx, "user:1", 20*time.Millisecond, fetchFn)
+	_, _ = c.GetOrFetch(ctx, "user:2", time.Minute, fetchFn)
+
+	// The janitor evicts the expired key, which removes it from the index
+	assert.Eventually(t, func() bool {
+		return len(c.index.keys("user:")) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	n, err := c.DeleteByPrefix(ctx, "user:")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, _ = c.GetOrFetch(ctx, "user:3", time.Minute, fetchFn)
+	require.NoError(t, c.Clear(ctx))
+	assert.Empty(t, c.index.keys("user:"))
+}
+
+func TestMemoryCacher_PrefixIndex_WithStaleFallback(t *testing.T) {
+	c := NewMemoryCacher[string](cache.NoExpiration, time.Minute,
+		WithStaleFallback[string](time.Minute, nil),
+		WithPrefixIndex[string](":"),
+	).(*MemoryCacher[string])
+	ctx := context.Background()
+
+	_, _ = c.GetOrFetch(ctx, "user:1", time.Minute, func(ctx context.Context) (string, error) { return "v", nil })
+
+	n, err := c.DeleteByPrefix(ctx, "user:")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Empty(t, c.index.keys("user:"))
+
+	// The stale value was discarded along with the cached one
+	_, err = c.GetOrFetch(ctx, "user:1", time.Minute, func(ctx context.Context) (string, error) {
+		return "", assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestMemoryCacher_Tags(t *testing.T) {
+	ctx := context.Background()
+
+	newTagged := func(t *testing.T) *MemoryCacher[string] {
+		c := NewMemoryCacher[string](cache.NoExpiration, time.Minute, WithStaleFallback[string](time.Minute, nil))
+		tc, ok := c.(TaggedCacher[string])
+		require.True(t, ok, "MemoryCacher implements TaggedCacher")
+		require.NoError(t, tc.SetWithTags(ctx, "profile:42", "alice", time.Minute, "user:42"))
+		require.NoError(t, tc.SetWithTags(ctx, "orders?user=42&page=1", "[...]", time.Minute, "user:42", "orders"))
+		require.NoError(t, tc.SetWithTags(ctx, "profile:7", "bob", time.Minute, "user:7"))
+		_, err := c.GetOrFetch(ctx, "untagged", time.Minute, func(ctx context.Context) (string, error) { return "v", nil })
+		require.NoError(t, err)
+		return c.(*MemoryCacher[string])
+	}
+
+	t.Run("invalidating a tag removes exactly the tagged keys", func(t *testing.T) {
+		c := newTagged(t)
+
+		deleted, err := c.InvalidateTag(ctx, "user:42")
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+
+		keys, err := c.KeysByPrefix(ctx, "")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"profile:7", "untagged"}, keys)
+
+		_, stale := c.getStale("profile:42")
+		assert.False(t, stale, "stale copy is removed too")
+
+		deleted, err = c.InvalidateTag(ctx, "orders")
+		require.NoError(t, err)
+		assert.Zero(t, deleted, "keys already gone are not counted again")
+	})
+
+	t.Run("deleted keys leave their tags", func(t *testing.T) {
+		c := newTagged(t)
+
+		require.NoError(t, c.Delete(ctx, "profile:7"))
+		assert.Empty(t, c.tags.keysOf("user:7"))
+
+		_, err := c.GetOrFetch(ctx, "profile:7", time.Minute, func(ctx context.Context) (string, error) { return "bob", nil })
+		require.NoError(t, err)
+		deleted, err := c.InvalidateTag(ctx, "user:7")
+		require.NoError(t, err)
+		assert.Zero(t, deleted, "a key stored again without tags is not invalidated")
+	})
+
+	t.Run("expired keys leave their tags", func(t *testing.T) {
+		c := NewMemoryCacher[string](cache.NoExpiration, 10*time.Millisecond).(*MemoryCacher[string])
+		require.NoError(t, c.SetWithTags(ctx, "short", "v", 20*time.Millisecond, "tmp"))
+
+		assert.Eventually(t, func() bool { return len(c.tags.keysOf("tmp")) == 0 }, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("expired keys leave their tags on a miss without cleanup", func(t *testing.T) {
+		c := NewMemoryCacher[string](cache.NoExpiration, 0).(*MemoryCacher[string])
+		require.NoError(t, c.SetWithTags(ctx, "short", "v", 10*time.Millisecond, "tmp"))
+		require.NoError(t, c.SetWithTags(ctx, "batch", "v", 10*time.Millisecond, "tmp"))
+		time.Sleep(20 * time.Millisecond)
+
+		_, err := c.GetOrFetch(ctx, "short", time.Minute, func(ctx context.Context) (string, error) { return "new", nil })
+		require.NoError(t, err)
+		_, err = c.GetOrFetchMany(ctx, []string{"batch"}, time.Minute, func(ctx context.Context, keys []string) (map[string]string, error) {
+			return map[string]string{"batch": "new"}, nil
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, c.tags.keysOf("tmp"))
+		assert.Empty(t, c.tags.keys)
+	})
+
+	t.Run("Clear forgets all tags", func(t *testing.T) {
+		c := newTagged(t)
+
+		require.NoError(t, c.Clear(ctx))
+		assert.Empty(t, c.tags.keysOf("user:42"))
+	})
+}
@@ -292,6 +292,172 @@ func (_c *MockCacher_GetOrFetch_Call[T]) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
+// GetOrFetchMany provides a mock function for the type MockCacher
+func (_mock *MockCacher[T]) GetOrFetchMany(ctx context.Context, keys []string, ttl time.Duration, fetchFn FetchManyFunc[T]) (map[string]T, error) {
+	ret := _mock.Called(ctx, keys, ttl, fetchFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrFetchMany")
+	}
+
+	var r0 map[string]T
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Duration, FetchManyFunc[T]) (map[string]T, error)); ok {
+		return returnFunc(ctx, keys, ttl, fetchFn)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Duration, FetchManyFunc[T]) map[string]T); ok {
+		r0 = returnFunc(ctx, keys, ttl, fetchFn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]T)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, time.Duration, FetchManyFunc[T]) error); ok {
+		r1 = returnFunc(ctx, keys, ttl, fetchFn)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCacher_GetOrFetchMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrFetchMany'
+type MockCacher_GetOrFetchMany_Call[T any] struct {
+	*mock.Call
+}
+
+// GetOrFetchMany is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []string
+//   - ttl time.Duration
+//   - fetchFn FetchManyFunc[T]
+func (_e *MockCacher_Expecter[T]) GetOrFetchMany(ctx interface{}, keys interface{}, ttl interface{}, fetchFn interface{}) *MockCacher_GetOrFetchMany_Call[T] {
+	return &MockCacher_GetOrFetchMany_Call[T]{Call: _e.mock.On("GetOrFetchMany", ctx, keys, ttl, fetchFn)}
+}
+
+func (_c *MockCacher_GetOrFetchMany_Call[T]) Run(run func(ctx context.Context, keys []string, ttl time.Duration, fetchFn FetchManyFunc[T])) *MockCacher_GetOrFetchMany_Call[T] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 FetchManyFunc[T]
+		if args[3] != nil {
+			arg3 = args[3].(FetchManyFunc[T])
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCacher_GetOrFetchMany_Call[T]) Return(stringToV map[string]T, err error) *MockCacher_GetOrFetchMany_Call[T] {
+	_c.Call.Return(stringToV, err)
+	return _c
+}
+
+func (_c *MockCacher_GetOrFetchMany_Call[T]) RunAndReturn(run func(ctx context.Context, keys []string, ttl time.Duration, fetchFn FetchManyFunc[T]) (map[string]T, error)) *MockCacher_GetOrFetchMany_Call[T] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrFetchTimeout provides a mock function for the type MockCacher
+func (_mock *MockCacher[T]) GetOrFetchTimeout(ctx context.Context, key string, ttl time.Duration, fetchTimeout time.Duration, fetchFn FetchFunc[T]) (T, error) {
+	ret := _mock.Called(ctx, key, ttl, fetchTimeout, fetchFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrFetchTimeout")
+	}
+
+	var r0 T
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Duration, time.Duration, FetchFunc[T]) (T, error)); ok {
+		return returnFunc(ctx, key, ttl, fetchTimeout, fetchFn)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Duration, time.Duration, FetchFunc[T]) T); ok {
+		r0 = returnFunc(ctx, key, ttl, fetchTimeout, fetchFn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(T)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Duration, time.Duration, FetchFunc[T]) error); ok {
+		r1 = returnFunc(ctx, key, ttl, fetchTimeout, fetchFn)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCacher_GetOrFetchTimeout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrFetchTimeout'
+type MockCacher_GetOrFetchTimeout_Call[T any] struct {
+	*mock.Call
+}
+
+// GetOrFetchTimeout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - ttl time.Duration
+//   - fetchTimeout time.Duration
+//   - fetchFn FetchFunc[T]
+func (_e *MockCacher_Expecter[T]) GetOrFetchTimeout(ctx interface{}, key interface{}, ttl interface{}, fetchTimeout interface{}, fetchFn interface{}) *MockCacher_GetOrFetchTimeout_Call[T] {
+	return &MockCacher_GetOrFetchTimeout_Call[T]{Call: _e.mock.On("GetOrFetchTimeout", ctx, key, ttl, fetchTimeout, fetchFn)}
+}
+
+func (_c *MockCacher_GetOrFetchTimeout_Call[T]) Run(run func(ctx context.Context, key string, ttl time.Duration, fetchTimeout time.Duration, fetchFn FetchFunc[T])) *MockCacher_GetOrFetchTimeout_Call[T] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		var arg4 FetchFunc[T]
+		if args[4] != nil {
+			arg4 = args[4].(FetchFunc[T])
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCacher_GetOrFetchTimeout_Call[T]) Return(v T, err error) *MockCacher_GetOrFetchTimeout_Call[T] {
+	_c.Call.Return(v, err)
+	return _c
+}
+
+func (_c *MockCacher_GetOrFetchTimeout_Call[T]) RunAndReturn(run func(ctx context.Context, key string, ttl time.Duration, fetchTimeout time.Duration, fetchFn FetchFunc[T]) (T, error)) *MockCacher_GetOrFetchTimeout_Call[T] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ItemCount provides a mock function for the type MockCacher
 func (_mock *MockCacher[T]) ItemCount(ctx context.Context) (int, error) {
 	ret := _mock.Called(ctx)
@@ -0,0 +1,131 @@
+package cacher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Observer receives cache events from a Cacher wrapped with WithObserver, for
+// example to export metrics. Implementations must be safe for concurrent use
+// and should return quickly, since they run on the caller's goroutine.
+type Observer interface {
+	// OnHit is called when GetOrFetch returns without calling fetchFn.
+	OnHit(key string)
+
+	// OnMiss is called when GetOrFetch has to call fetchFn.
+	OnMiss(key string)
+
+	// OnFetch is called after fetchFn returns, with how long it took and its error.
+	OnFetch(key string, duration time.Duration, err error)
+}
+
+// observedCacher is a Cacher that reports hits, misses, and fetches to an Observer.
+type observedCacher[T any] struct {
+	Cacher[T]
+	observer Observer
+}
+
+// WithObserver wraps c so that every GetOrFetch and GetOrFetchTimeout call is
+// reported to observer. A call counts as a hit when fetchFn is not called, so a
+// caller served by another caller's in-flight fetch also counts as a hit.
+// Errors from the cache itself, rather than from fetchFn, are not reported.
+//
+// Parameters:
+//   - c: The cacher to observe
+//   - observer: Receives the cache events
+//
+// Returns:
+//   - A Cacher that behaves like c and reports to observer
+func WithObserver[T any](c Cacher[T], observer Observer) Cacher[T] {
+	return &observedCacher[T]{Cacher: c, observer: observer}
+}
+
+// GetOrFetch calls the wrapped cacher's GetOrFetch and reports the outcome.
+func (c *observedCacher[T]) GetOrFetch(ctx context.Context, key string, ttl time.Duration, fetchFn FetchFunc[T]) (T, error) {
+	var fetched atomic.Bool
+	val, err := c.Cacher.GetOrFetch(ctx, key, ttl, c.observe(key, &fetched, fetchFn))
+	if !fetched.Load() && err == nil {
+		c.observer.OnHit(key)
+	}
+
+	return val, err
+}
+
+// GetOrFetchTimeout calls the wrapped cacher's GetOrFetchTimeout and reports the outcome.
+func (c *observedCacher[T]) GetOrFetchTimeout(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	fetchTimeout time.Duration,
+	fetchFn FetchFunc[T],
+) (T, error) {
+	// fetchFn may still be running in the background after a fetch timeout
+	var fetched atomic.Bool
+	val, err := c.Cacher.GetOrFetchTimeout(ctx, key, ttl, fetchTimeout, c.observe(key, &fetched, fetchFn))
+	if !fetched.Load() && err == nil {
+		c.observer.OnHit(key)
+	}
+
+	return val, err
+}
+
+// GetOrFetchMany calls the wrapped cacher's GetOrFetchMany and reports each key: a miss
+// and a fetch, with the duration and error of the shared fetch call, for every key passed
+// to fetchFn, and a hit for every other key once the call succeeds.
+func (c *observedCacher[T]) GetOrFetchMany(
+	ctx context.Context,
+	keys []string,
+	ttl time.Duration,
+	fetchFn FetchManyFunc[T],
+) (map[string]T, error) {
+	var mu sync.Mutex
+	fetched := make(map[string]struct{})
+
+	vals, err := c.Cacher.GetOrFetchMany(ctx, keys, ttl, func(ctx context.Context, missing []string) (map[string]T, error) {
+		mu.Lock()
+		for _, key := range missing {
+			fetched[key] = struct{}{}
+			c.observer.OnMiss(key)
+		}
+		mu.Unlock()
+
+		start := time.Now()
+		vals, err := fetchFn(ctx, missing)
+		duration := time.Since(start)
+		for _, key := range missing {
+			c.observer.OnFetch(key, duration, err)
+		}
+
+		return vals, err
+	})
+	if err != nil {
+		return vals, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for key := range vals {
+		if _, ok := fetched[key]; !ok {
+			c.observer.OnHit(key)
+		}
+	}
+
+	return vals, nil
+}
+
+// observe wraps fetchFn so that calling it reports a miss and the fetch result,
+// and sets fetched.
+func (c *observedCacher[T]) observe(key string, fetched *atomic.Bool, fetchFn FetchFunc[T]) FetchFunc[T] {
+	return func(ctx context.Context) (T, error) {
+		fetched.Store(true)
+		c.observer.OnMiss(key)
+
+		start := time.Now()
+		val, err := fetchFn(ctx)
+		c.observer.OnFetch(key, time.Since(start), err)
+
+		return val, err
+	}
+}
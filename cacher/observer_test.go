@@ -0,0 +1,96 @@
+package cacher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver is an Observer that records the events it receives.
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *recordingObserver) record(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) OnHit(key string)  { o.record("hit:" + key) }
+func (o *recordingObserver) OnMiss(key string) { o.record("miss:" + key) }
+
+func (o *recordingObserver) OnFetch(key string, duration time.Duration, err error) {
+	if err != nil {
+		o.record("fetch-error:" + key)
+		return
+	}
+	o.record("fetch:" + key)
+}
+
+func (o *recordingObserver) recorded() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.events...)
+}
+
+func TestWithObserver(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports misses, fetches and hits", func(t *testing.T) {
+		obs := &recordingObserver{}
+		c := WithObserver(NewMemoryCacher[string](cache.NoExpiration, time.Minute), obs)
+		fetch := func(ctx context.Context) (string, error) { return "v", nil }
+
+		_, err := c.GetOrFetch(ctx, "a", time.Minute, fetch)
+		require.NoError(t, err)
+		_, err = c.GetOrFetch(ctx, "a", time.Minute, fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"miss:a", "fetch:a", "hit:a"}, obs.recorded())
+	})
+
+	t.Run("reports fetch errors without a hit", func(t *testing.T) {
+		obs := &recordingObserver{}
+		c := WithObserver(NewMemoryCacher[string](cache.NoExpiration, time.Minute), obs)
+
+		_, err := c.GetOrFetchTimeout(ctx, "b", time.Minute, time.Second, func(ctx context.Context) (string, error) {
+			return "", errors.New("source down")
+		})
+		require.Error(t, err)
+
+		assert.Equal(t, []string{"miss:b", "fetch-error:b"}, obs.recorded())
+	})
+
+	t.Run("reports each key of a batch fetch", func(t *testing.T) {
+		obs := &recordingObserver{}
+		base := NewMemoryCacher[string](cache.NoExpiration, time.Minute)
+		seed[string](t, base, "a", "cached")
+		c := WithObserver(base, obs)
+
+		_, err := c.GetOrFetchMany(ctx, []string{"a", "b"}, time.Minute,
+			func(ctx context.Context, missingKeys []string) (map[string]string, error) {
+				return map[string]string{"b": "fetched"}, nil
+			})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"miss:b", "fetch:b", "hit:a"}, obs.recorded())
+	})
+
+	t.Run("other methods pass through", func(t *testing.T) {
+		c := WithObserver(NewMemoryCacher[int](cache.NoExpiration, time.Minute), &recordingObserver{})
+		_, err := c.GetOrFetch(ctx, "k", time.Minute, func(ctx context.Context) (int, error) { return 1, nil })
+		require.NoError(t, err)
+
+		count, err := c.ItemCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
@@ -0,0 +1,91 @@
+package cacher
+
+import (
+	"strings"
+	"sync"
+)
+
+// prefixIndex maps every separator-terminated prefix of a key to the set of keys
+// sharing it, so prefix lookups cost O(matching keys) instead of O(all keys).
+// For the separator ":" the key "user:42:posts" is indexed under "user:" and "user:42:".
+type prefixIndex struct {
+	separator string
+
+	mu       sync.Mutex
+	prefixes map[string]map[string]struct{}
+}
+
+// newPrefixIndex creates an empty index for keys using separator.
+func newPrefixIndex(separator string) *prefixIndex {
+	return &prefixIndex{
+		separator: separator,
+		prefixes:  make(map[string]map[string]struct{}),
+	}
+}
+
+// indexable reports whether prefix can be answered from the index.
+func (p *prefixIndex) indexable(prefix string) bool {
+	return prefix != "" && strings.HasSuffix(prefix, p.separator)
+}
+
+// eachPrefix calls f for every separator-terminated prefix of key.
+func (p *prefixIndex) eachPrefix(key string, f func(prefix string)) {
+	offset := 0
+	for {
+		i := strings.Index(key[offset:], p.separator)
+		if i < 0 {
+			return
+		}
+
+		offset += i + len(p.separator)
+		f(key[:offset])
+	}
+}
+
+// add indexes key; callers must hold mu.
+func (p *prefixIndex) add(key string) {
+	p.eachPrefix(key, func(prefix string) {
+		keys, ok := p.prefixes[prefix]
+		if !ok {
+			keys = make(map[string]struct{})
+			p.prefixes[prefix] = keys
+		}
+		keys[key] = struct{}{}
+	})
+}
+
+// remove drops key from the index; callers must hold mu.
+func (p *prefixIndex) remove(key string) {
+	p.eachPrefix(key, func(prefix string) {
+		keys, ok := p.prefixes[prefix]
+		if !ok {
+			return
+		}
+
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(p.prefixes, prefix)
+		}
+	})
+}
+
+// keys returns a snapshot of the keys indexed under prefix.
+func (p *prefixIndex) keys(prefix string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	matching := p.prefixes[prefix]
+	result := make([]string, 0, len(matching))
+	for key := range matching {
+		result = append(result, key)
+	}
+
+	return result
+}
+
+// reset empties the index.
+func (p *prefixIndex) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prefixes = make(map[string]map[string]struct{})
+}
@@ -11,12 +11,16 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// tagKeyPrefix namespaces the Redis sets holding the keys attached to each tag.
+const tagKeyPrefix = "cacher:tag:"
+
 // redisCacher is a Redis-based implementation of the Cacher interface.
 // It provides thread-safe caching with distributed locking to prevent
 // cache stampede (thundering herd) problems when multiple goroutines
 // try to fetch the same missing cache entry simultaneously.
 type redisCacher[T any] struct {
 	client *redis.Client
+	lock   *RedisLock
 }
 
 // NewRedisCacher creates a new Redis-based cacher instance.
@@ -30,6 +34,21 @@ type redisCacher[T any] struct {
 func NewRedisCacher[T any](client *redis.Client) Cacher[T] {
 	return &redisCacher[T]{
 		client: client,
+		lock:   NewRedisLock(client),
+	}
+}
+
+// NewRedisStore creates a BackingStore that keeps JSON-encoded values in Redis,
+// for use as the L2 of a WriteBehindCacher.
+//
+// Example:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	c := NewWriteBehindCacher(NewRedisStore[User](client), 5*time.Minute, 10*time.Minute)
+func NewRedisStore[T any](client *redis.Client) BackingStore[T] {
+	return &redisCacher[T]{
+		client: client,
+		lock:   NewRedisLock(client),
 	}
 }
 
@@ -76,34 +95,15 @@ func (c *redisCacher[T]) GetOrFetch(ctx context.Context, key string, ttl time.Du
 
 	// Cache miss - try to acquire lock
 	lockKey := fmt.Sprintf("%s:lock", key)
-	lockTTL := 30 * time.Second
-	lockValue := fmt.Sprintf("%d", time.Now().UnixNano()) // Unique lock value
-
-	acquired, err := c.client.SetNX(ctx, lockKey, lockValue, lockTTL).Result()
+	unlock, acquired, err := c.lock.Acquire(ctx, lockKey, 30*time.Second)
 	if err != nil {
-		return zero, fmt.Errorf("failed to acquire lock: %w", err)
+		return zero, err
 	}
 
 	if acquired {
-		// Use background context for cleanup to ensure lock is released
+		// The lock is extended while fetching and released with a background context
+		defer unlock()
 		bgCtx := context.Background()
-		defer func() {
-			// Only delete if we still own the lock
-			script := `
-				if redis.call("get", KEYS[1]) == ARGV[1] then
-					return redis.call("del", KEYS[1])
-				else
-					return 0
-				end
-			`
-			c.client.Eval(bgCtx, script, []string{lockKey}, lockValue)
-		}()
-
-		// Extend lock if fetch takes longer
-		extendCtx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-
-		go c.extendLock(extendCtx, lockKey, lockValue, lockTTL)
 
 		result, err := fetchFn(ctx)
 		if err != nil {
@@ -127,38 +127,116 @@ func (c *redisCacher[T]) GetOrFetch(ctx context.Context, key string, ttl time.Du
 	return c.waitForCache(ctx, key, lockKey, 30*time.Second)
 }
 
-// extendLock periodically extends the lock TTL to prevent expiration
-// during long-running fetch operations. It runs in a separate goroutine
-// and extends the lock at intervals of ttl/3 until the context is cancelled.
+// GetOrFetchTimeout behaves like GetOrFetch, but bounds the fetch call by fetchTimeout
+// independently of ctx. Only the fetch is bounded; Redis operations and waiting for
+// another holder of the lock are still governed by ctx.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - key: The cache key to retrieve or set
+//   - ttl: Time-to-live duration for the cached value
+//   - fetchTimeout: Maximum duration of the fetch call; <= 0 disables the bound
+//   - fetchFn: Function to fetch the value if not in cache
 //
-// The extension uses a Lua script to atomically verify lock ownership
-// before extending, ensuring only the lock owner can extend it.
+// Returns:
+//   - The cached or fetched value of type T
+//   - An error if retrieval or fetching fails, or one wrapping ErrFetchTimeout
+func (c *redisCacher[T]) GetOrFetchTimeout(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	fetchTimeout time.Duration,
+	fetchFn FetchFunc[T],
+) (T, error) {
+	return c.GetOrFetch(ctx, key, ttl, withFetchTimeout(fetchTimeout, fetchFn))
+}
+
+// GetOrFetchMany retrieves several values with a single MGET, calls fetchFn once for
+// the missing keys, and stores the fetched values in one pipeline. Unlike GetOrFetch,
+// no distributed lock is taken, so concurrent callers may fetch the same keys.
 //
 // Parameters:
-//   - ctx: Context for cancellation (when cancelled, extension stops)
-//   - lockKey: The Redis key for the lock
-//   - lockValue: The unique value identifying this lock instance
-//   - ttl: The time-to-live duration to extend the lock to
-func (c *redisCacher[T]) extendLock(ctx context.Context, lockKey, lockValue string, ttl time.Duration) {
-	ticker := time.NewTicker(ttl / 3) // Extend at 1/3 of TTL
-	defer ticker.Stop()
-
-	script := `
-		if redis.call("get", KEYS[1]) == ARGV[1] then
-			return redis.call("pexpire", KEYS[1], ARGV[2])
-		else
-			return 0
-		end
-	`
+//   - ctx: Context for cancellation and timeout control
+//   - keys: The cache keys to retrieve
+//   - ttl: Time-to-live duration for fetched values
+//   - fetchFn: Function to fetch the missing keys in one call
+//
+// Returns:
+//   - The cached and fetched values keyed by cache key
+//   - An error if a Redis operation, decoding, or fetching fails
+func (c *redisCacher[T]) GetOrFetchMany(
+	ctx context.Context,
+	keys []string,
+	ttl time.Duration,
+	fetchFn FetchManyFunc[T],
+) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			c.client.Eval(ctx, script, []string{lockKey}, lockValue, ttl.Milliseconds())
+	vals, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis mget error: %w", err)
+	}
+
+	for i, key := range keys {
+		val, ok := vals[i].(string)
+		if !ok {
+			continue
+		}
+
+		var typedVal T
+		if err := json.Unmarshal([]byte(val), &typedVal); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cached value: %w", err)
+		}
+
+		result[key] = typedVal
+	}
+
+	missing := missingKeys(keys, func(key string) bool {
+		_, ok := result[key]
+		return ok
+	})
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	fetched, err := fetchFn(ctx, missing)
+	if err != nil {
+		return nil, fmt.Errorf("fetch function failed: %w", err)
+	}
+
+	encoded := make(map[string][]byte, len(fetched))
+	for _, key := range missing {
+		val, ok := fetched[key]
+		if !ok {
+			continue
+		}
+
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
+		}
+
+		encoded[key] = data
+		result[key] = val
+	}
+
+	// Like GetOrFetch, store the results even if ctx was cancelled during the fetch
+	bgCtx := context.Background()
+	_, err = c.client.Pipelined(bgCtx, func(pipe redis.Pipeliner) error {
+		for key, data := range encoded {
+			pipe.Set(bgCtx, key, data, ttl)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cache results: %w", err)
 	}
+
+	return result, nil
 }
 
 // waitForCache waits for another goroutine to populate the cache after
@@ -250,6 +328,39 @@ func (c *redisCacher[T]) waitForCache(
 	}
 }
 
+// Get returns the value stored under key. It implements BackingStore.
+func (c *redisCacher[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	val, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, fmt.Errorf("redis get error: %w", err)
+	}
+
+	var result T
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		return zero, false, fmt.Errorf("failed to unmarshal cached value: %w", err)
+	}
+
+	return result, true, nil
+}
+
+// Set stores value under key with the given TTL. It implements BackingStore.
+func (c *redisCacher[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set key: %w", err)
+	}
+	return nil
+}
+
 // Delete removes a key from the cache.
 func (c *redisCacher[T]) Delete(ctx context.Context, key string) error {
 	if err := c.client.Del(ctx, key).Err(); err != nil {
@@ -258,7 +369,8 @@ func (c *redisCacher[T]) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
-// Clear removes all items from the cache.
+// Clear removes all items from the cache. It implements BackingStore, and
+// flushes the whole Redis database.
 func (c *redisCacher[T]) Clear(ctx context.Context) error {
 	if err := c.client.FlushDB(ctx).Err(); err != nil {
 		return fmt.Errorf("failed to clear cache: %w", err)
@@ -275,7 +387,7 @@ func (c *redisCacher[T]) ItemCount(ctx context.Context) (int, error) {
 	return int(count), nil
 }
 
-// DeleteByPrefix deletes all keys with the given prefix.
+// DeleteByPrefix deletes all keys with the given prefix. It implements BackingStore.
 func (c *redisCacher[T]) DeleteByPrefix(ctx context.Context, prefix string) (int, error) {
 	deletedCount := 0
 
@@ -313,3 +425,61 @@ func (c *redisCacher[T]) DeleteByPrefix(ctx context.Context, prefix string) (int
 
 	return deletedCount, nil
 }
+
+// SetWithTags stores value under key with the given TTL and adds key to a Redis set per
+// tag, named "cacher:tag:{tag}". The value and the tag sets are written in one transaction.
+// Tag sets do not expire; members whose key has expired are harmless and are removed when
+// the tag is invalidated.
+func (c *redisCacher[T]) SetWithTags(ctx context.Context, key string, value T, ttl time.Duration, tags ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl)
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagKeyPrefix+tag, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set tagged key: %w", err)
+	}
+	return nil
+}
+
+// InvalidateTag reads the tag's set, then deletes those keys and removes them from the
+// set in one pipeline. Every command names the keys it touches, so it also works through
+// proxies and Redis Cluster. Only the keys that were read are removed from the set, so a
+// key tagged concurrently stays in it; the set disappears once it is empty.
+func (c *redisCacher[T]) InvalidateTag(ctx context.Context, tag string) (int, error) {
+	tagKey := tagKeyPrefix + tag
+	keys, err := c.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read tag: %w", err)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	dels := make([]*redis.IntCmd, len(keys))
+	members := make([]interface{}, len(keys))
+	_, err = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			dels[i] = pipe.Del(ctx, key)
+			members[i] = key
+		}
+		pipe.SRem(ctx, tagKey, members...)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to invalidate tag: %w", err)
+	}
+
+	deleted := 0
+	for _, del := range dels {
+		deleted += int(del.Val())
+	}
+	return deleted, nil
+}
@@ -0,0 +1,78 @@
+package cacher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisCacher_Tags(t *testing.T) {
+	client := newTestRedisClient(t)
+	ctx := context.Background()
+	prefix := "test:redis-tags:" + t.Name() + ":"
+	tag := prefix + "user:42"
+	t.Cleanup(func() {
+		keys, _ := client.Keys(context.Background(), prefix+"*").Result()
+		keys = append(keys, tagKeyPrefix+tag, tagKeyPrefix+prefix+"user:7")
+		client.Del(context.Background(), keys...)
+	})
+
+	c, ok := NewRedisCacher[string](client).(TaggedCacher[string])
+	require.True(t, ok, "the Redis cacher implements TaggedCacher")
+
+	require.NoError(t, c.SetWithTags(ctx, prefix+"profile:42", "alice", time.Minute, tag))
+	require.NoError(t, c.SetWithTags(ctx, prefix+"orders:42", "[...]", time.Minute, tag))
+	require.NoError(t, c.SetWithTags(ctx, prefix+"profile:7", "bob", time.Minute, prefix+"user:7"))
+
+	deleted, err := c.InvalidateTag(ctx, tag)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	n, err := client.Exists(ctx, prefix+"profile:42", prefix+"orders:42", tagKeyPrefix+tag).Result()
+	require.NoError(t, err)
+	assert.Zero(t, n, "tagged keys and the tag set are removed")
+
+	val, err := client.Get(ctx, prefix+"profile:7").Result()
+	require.NoError(t, err)
+	assert.Equal(t, `"bob"`, val)
+
+	deleted, err = c.InvalidateTag(ctx, tag)
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "an invalidated tag is empty")
+}
+
+func TestRedisCacher_GetOrFetchMany(t *testing.T) {
+	client := newTestRedisClient(t)
+	ctx := context.Background()
+	prefix := "test:redis-many:" + t.Name() + ":"
+	t.Cleanup(func() {
+		keys, _ := client.Keys(context.Background(), prefix+"*").Result()
+		if len(keys) > 0 {
+			client.Del(context.Background(), keys...)
+		}
+	})
+
+	c := NewRedisCacher[string](client)
+	seed[string](t, c, prefix+"a", "cached-a")
+
+	var requested []string
+	vals, err := c.GetOrFetchMany(ctx, []string{prefix + "a", prefix + "b", prefix + "c"}, time.Minute,
+		func(ctx context.Context, missingKeys []string) (map[string]string, error) {
+			requested = missingKeys
+			return map[string]string{prefix + "b": "fetched-b"}, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{prefix + "b", prefix + "c"}, requested)
+	assert.Equal(t, map[string]string{prefix + "a": "cached-a", prefix + "b": "fetched-b"}, vals)
+
+	ttl, err := client.TTL(ctx, prefix+"b").Result()
+	require.NoError(t, err)
+	assert.Positive(t, ttl, "fetched values are stored with the TTL")
+
+	exists, err := client.Exists(ctx, prefix+"c").Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+}
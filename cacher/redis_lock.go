@@ -0,0 +1,138 @@
+package cacher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseLockScript deletes the lock only if it is still held by the caller.
+var releaseLockScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("del", KEYS[1])
+	else
+		return 0
+	end
+`)
+
+// extendLockScript resets the lock TTL only if it is still held by the caller.
+var extendLockScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("pexpire", KEYS[1], ARGV[2])
+	else
+		return 0
+	end
+`)
+
+// RedisLock is a distributed mutual-exclusion lock backed by Redis. It is the
+// lock the Redis cacher uses to prevent cache stampede, exposed for guarding
+// other critical sections such as "only one worker runs this job".
+//
+// A lock is taken with SET NX under a random token, extended automatically
+// while held, and released with a Lua script that only deletes the key if the
+// token still matches, so one holder can never release another's lock.
+// A RedisLock is safe for concurrent use.
+type RedisLock struct {
+	client *redis.Client
+}
+
+// NewRedisLock creates a RedisLock that stores lock keys in the given client.
+//
+// Example:
+//
+//	lock := NewRedisLock(redis.NewClient(&redis.Options{Addr: "localhost:6379"}))
+//	unlock, ok, err := lock.Acquire(ctx, "cron:cleanup", 30*time.Second)
+//	if err != nil || !ok {
+//		return err
+//	}
+//	defer unlock()
+func NewRedisLock(client *redis.Client) *RedisLock {
+	return &RedisLock{
+		client: client,
+	}
+}
+
+// Acquire tries once to take the lock on key without waiting. On success the
+// lock's TTL is extended every ttl/3 until unlock is called, so a holder that
+// runs longer than ttl keeps the lock; if the process dies, the lock expires
+// after ttl.
+//
+// Parameters:
+//   - ctx: Context for the acquire call
+//   - key: The Redis key to lock
+//   - ttl: Lock lifetime without extension; must be positive
+//
+// Returns:
+//   - unlock: Releases the lock and stops extension; safe to call more than once.
+//     Nil when the lock was not acquired.
+//   - ok: true if the lock was acquired, false if another holder has it
+//   - err: A non-nil error if Redis could not be reached
+func (l *RedisLock) Acquire(ctx context.Context, key string, ttl time.Duration) (unlock func(), ok bool, err error) {
+	if ttl <= 0 {
+		return nil, false, fmt.Errorf("lock ttl must be positive, got %s", ttl)
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+
+	acquired, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	if !acquired {
+		return nil, false, nil
+	}
+
+	extendCtx, cancel := context.WithCancel(context.Background())
+	go l.extend(extendCtx, key, token, ttl)
+
+	var once sync.Once
+	unlock = func() {
+		once.Do(func() {
+			cancel()
+			// Use background context so the lock is released even if ctx is done
+			releaseLockScript.Run(context.Background(), l.client, []string{key}, token)
+		})
+	}
+
+	return unlock, true, nil
+}
+
+// extend periodically resets the lock TTL until ctx is cancelled.
+//
+// Parameters:
+//   - ctx: Context for cancellation (when cancelled, extension stops)
+//   - key: The Redis key for the lock
+//   - token: The unique value identifying this lock holder
+//   - ttl: The time-to-live duration to extend the lock to
+func (l *RedisLock) extend(ctx context.Context, key, token string, ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 3) // Extend at 1/3 of TTL
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			extendLockScript.Run(ctx, l.client, []string{key}, token, ttl.Milliseconds())
+		}
+	}
+}
+
+// newLockToken returns a random value identifying a single lock holder.
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,110 @@
+package cacher
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedisClient connects to the Redis server named by REDIS_ADDR and
+// skips the test when it is unset or unreachable.
+func newTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set; skipping Redis test")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("redis at %s unreachable: %v", addr, err)
+	}
+
+	return client
+}
+
+func TestRedisLock(t *testing.T) {
+	client := newTestRedisClient(t)
+	ctx := context.Background()
+	key := "test:redis-lock:" + t.Name()
+	t.Cleanup(func() { client.Del(context.Background(), key) })
+
+	t.Run("rejects non-positive ttl", func(t *testing.T) {
+		_, ok, err := NewRedisLock(client).Acquire(ctx, key, 0)
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("second instance cannot acquire a held lock", func(t *testing.T) {
+		first := NewRedisLock(client)
+		second := NewRedisLock(client)
+
+		unlock, ok, err := first.Acquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		_, ok, err = second.Acquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		unlock()
+		unlock() // idempotent
+
+		unlock, ok, err = second.Acquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		unlock()
+	})
+
+	t.Run("lock is extended while held", func(t *testing.T) {
+		unlock, ok, err := NewRedisLock(client).Acquire(ctx, key, 300*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, ok)
+		defer unlock()
+
+		time.Sleep(time.Second)
+		_, ok, err = NewRedisLock(client).Acquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("mutual exclusion across instances", func(t *testing.T) {
+		locks := []*RedisLock{NewRedisLock(client), NewRedisLock(client)}
+
+		var gauge concurrencyGauge
+		var acquisitions atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(lock *RedisLock) {
+				defer wg.Done()
+				unlock, ok, err := lock.Acquire(ctx, key, time.Second)
+				if err != nil || !ok {
+					return
+				}
+				defer unlock()
+
+				acquisitions.Add(1)
+				gauge.enter()
+				time.Sleep(10 * time.Millisecond)
+				gauge.leave()
+			}(locks[i%2])
+		}
+		wg.Wait()
+
+		assert.GreaterOrEqual(t, acquisitions.Load(), int32(1))
+		assert.Equal(t, 1, gauge.highest())
+	})
+}
@@ -0,0 +1,80 @@
+package cacher
+
+import "sync"
+
+// tagIndex maps tags to the keys carrying them and keys back to their tags, so that
+// the keys of a tag can be found without scanning the cache and a key can be dropped
+// from all of its tags when it leaves the cache.
+type tagIndex struct {
+	mu   sync.Mutex
+	tags map[string]map[string]struct{} // tag -> keys
+	keys map[string]map[string]struct{} // key -> tags
+}
+
+// newTagIndex creates an empty tag index.
+func newTagIndex() *tagIndex {
+	return &tagIndex{
+		tags: make(map[string]map[string]struct{}),
+		keys: make(map[string]map[string]struct{}),
+	}
+}
+
+// add tags key with tags; callers must hold mu.
+func (t *tagIndex) add(key string, tags []string) {
+	for _, tag := range tags {
+		link(t.tags, tag, key)
+		link(t.keys, key, tag)
+	}
+}
+
+// remove drops key from all of its tags; callers must hold mu.
+func (t *tagIndex) remove(key string) {
+	for tag := range t.keys[key] {
+		unlink(t.tags, tag, key)
+	}
+	delete(t.keys, key)
+}
+
+// keysOf returns a snapshot of the keys tagged with tag.
+func (t *tagIndex) keysOf(tag string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := make([]string, 0, len(t.tags[tag]))
+	for key := range t.tags[tag] {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// reset empties the index.
+func (t *tagIndex) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tags = make(map[string]map[string]struct{})
+	t.keys = make(map[string]map[string]struct{})
+}
+
+// link adds member to the set stored under name in sets.
+func link(sets map[string]map[string]struct{}, name, member string) {
+	set, ok := sets[name]
+	if !ok {
+		set = make(map[string]struct{})
+		sets[name] = set
+	}
+	set[member] = struct{}{}
+}
+
+// unlink removes member from the set stored under name, dropping the set once empty.
+func unlink(sets map[string]map[string]struct{}, name, member string) {
+	set, ok := sets[name]
+	if !ok {
+		return
+	}
+
+	delete(set, member)
+	if len(set) == 0 {
+		delete(sets, name)
+	}
+}
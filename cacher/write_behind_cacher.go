@@ -0,0 +1,495 @@
+package cacher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCacherClosed is returned by WriteBehindCacher writes after Close has been called.
+var ErrCacherClosed = errors.New("cacher is closed")
+
+// BackingStore is the slower, durable tier behind a WriteBehindCacher (e.g. Redis).
+// Implementations must be safe for concurrent use.
+type BackingStore[T any] interface {
+	// Get returns the value stored under key.
+	//
+	// Returns:
+	//   - The stored value, and true if key was found
+	//   - An error if the lookup fails
+	Get(ctx context.Context, key string) (T, bool, error)
+
+	// Set stores value under key with the given TTL.
+	Set(ctx context.Context, key string, value T, ttl time.Duration) error
+
+	// Delete removes key from the store.
+	Delete(ctx context.Context, key string) error
+
+	// DeleteByPrefix removes every key that starts with prefix.
+	//
+	// Returns:
+	//   - The number of keys removed
+	//   - An error if the removal fails
+	DeleteByPrefix(ctx context.Context, prefix string) (int, error)
+
+	// Clear removes every key from the store.
+	Clear(ctx context.Context) error
+}
+
+// FlushErrorHandler is called when a WriteBehindCacher fails to write a buffered
+// value to its backing store. The value is dropped after the handler returns.
+type FlushErrorHandler func(key string, err error)
+
+// WriteBehindOption configures optional behavior of a WriteBehindCacher.
+type WriteBehindOption[T any] func(*WriteBehindCacher[T])
+
+// WithFlushInterval sets how often buffered writes are flushed to the backing store.
+// The default is one second.
+//
+// Parameters:
+//   - interval: Time between background flushes; must be positive
+//
+// Returns:
+//   - A WriteBehindOption to pass to NewWriteBehindCacher
+func WithFlushInterval[T any](interval time.Duration) WriteBehindOption[T] {
+	return func(c *WriteBehindCacher[T]) {
+		c.flushInterval = interval
+	}
+}
+
+// WithFlushBatchSize triggers a flush as soon as this many distinct keys are
+// buffered, without waiting for the flush interval. The default is 100.
+//
+// Parameters:
+//   - size: Number of buffered keys that triggers an early flush; must be positive
+//
+// Returns:
+//   - A WriteBehindOption to pass to NewWriteBehindCacher
+func WithFlushBatchSize[T any](size int) WriteBehindOption[T] {
+	return func(c *WriteBehindCacher[T]) {
+		c.batchSize = size
+	}
+}
+
+// WithFlushErrorHandler sets a function called for every buffered write that
+// could not be stored in the backing store.
+//
+// Parameters:
+//   - onError: Function receiving the key and the store error
+//
+// Returns:
+//   - A WriteBehindOption to pass to NewWriteBehindCacher
+func WithFlushErrorHandler[T any](onError FlushErrorHandler) WriteBehindOption[T] {
+	return func(c *WriteBehindCacher[T]) {
+		c.onFlushError = onError
+	}
+}
+
+// pendingWrite is a buffered value waiting to be flushed to the backing store.
+type pendingWrite[T any] struct {
+	value T
+	ttl   time.Duration
+}
+
+// WriteBehindCacher is a two-tier Cacher that serves reads and writes from an
+// in-memory L1 and persists values to a slower BackingStore (L2) in the
+// background. Writes are buffered and flushed on an interval or once a batch
+// size is reached; repeated writes to the same key before a flush are
+// coalesced so only the latest value reaches L2.
+//
+// Durability tradeoff: buffered writes live only in memory until flushed, so
+// writes made shortly before a crash can be lost. Call Close on shutdown to
+// flush what is buffered.
+type WriteBehindCacher[T any] struct {
+	l1    *MemoryCacher[T]
+	store BackingStore[T]
+
+	flushInterval time.Duration
+	batchSize     int
+	onFlushError  FlushErrorHandler
+
+	mu      sync.Mutex
+	pending map[string]pendingWrite[T]
+	closed  bool
+
+	// flushMu serializes flushes with deletes so a flush cannot write back a
+	// key that was deleted while it was running.
+	flushMu sync.Mutex
+
+	flushNow  chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewWriteBehindCacher creates a write-behind cacher in front of store and starts
+// its background flusher. Call Close when done to flush pending writes and stop it.
+//
+// Parameters:
+//   - store: The backing store that buffered writes are flushed to
+//   - defaultExpiration: Default TTL for L1 items (use cache.NoExpiration for no default)
+//   - cleanupInterval: Interval at which expired L1 items are removed
+//   - opts: Optional settings such as WithFlushInterval and WithFlushBatchSize
+//
+// Returns:
+//   - A new WriteBehindCacher instance
+func NewWriteBehindCacher[T any](
+	store BackingStore[T],
+	defaultExpiration, cleanupInterval time.Duration,
+	opts ...WriteBehindOption[T],
+) *WriteBehindCacher[T] {
+	c := &WriteBehindCacher[T]{
+		l1:            NewMemoryCacher[T](defaultExpiration, cleanupInterval).(*MemoryCacher[T]),
+		store:         store,
+		flushInterval: time.Second,
+		batchSize:     100,
+		pending:       make(map[string]pendingWrite[T]),
+		flushNow:      make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	go c.flushLoop()
+
+	return c
+}
+
+// GetOrFetch returns the value from L1, or on an L1 miss from the pending
+// buffer or L2. Only if none of them has the key is fetchFn called; the
+// fetched value is stored in L1 immediately and written to L2 in the background.
+// An L2 read error is treated as a miss.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - key: The cache key to retrieve or set
+//   - ttl: Time-to-live duration for the cached value
+//   - fetchFn: Function to fetch the value if not in either tier
+//
+// Returns:
+//   - The cached or fetched value of type T
+//   - An error if fetching fails
+func (c *WriteBehindCacher[T]) GetOrFetch(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	fetchFn FetchFunc[T],
+) (T, error) {
+	return c.l1.GetOrFetch(ctx, key, ttl, c.fetchThrough(key, ttl, fetchFn))
+}
+
+// GetOrFetchTimeout behaves like GetOrFetch, but bounds the fetch call by fetchTimeout
+// independently of ctx. Only fetchFn is bounded; the L2 lookup follows ctx.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - key: The cache key to retrieve or set
+//   - ttl: Time-to-live duration for the cached value
+//   - fetchTimeout: Maximum duration of the fetch call; <= 0 disables the bound
+//   - fetchFn: Function to fetch the value if not in either tier
+//
+// Returns:
+//   - The cached or fetched value of type T
+//   - An error if fetching fails, or one wrapping ErrFetchTimeout
+func (c *WriteBehindCacher[T]) GetOrFetchTimeout(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	fetchTimeout time.Duration,
+	fetchFn FetchFunc[T],
+) (T, error) {
+	return c.GetOrFetch(ctx, key, ttl, withFetchTimeout(fetchTimeout, fetchFn))
+}
+
+// GetOrFetchMany returns the values found in L1, then looks up the remaining keys in
+// the pending buffer and L2, and calls fetchFn once for the keys none of them has.
+// Fetched values are stored in L1 immediately and written to L2 in the background.
+// L2 read errors are treated as misses.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - keys: The cache keys to retrieve
+//   - ttl: Time-to-live duration for fetched values
+//   - fetchFn: Function to fetch the keys missing from both tiers in one call
+//
+// Returns:
+//   - The cached and fetched values keyed by cache key
+//   - An error if fetching fails
+func (c *WriteBehindCacher[T]) GetOrFetchMany(
+	ctx context.Context,
+	keys []string,
+	ttl time.Duration,
+	fetchFn FetchManyFunc[T],
+) (map[string]T, error) {
+	return c.l1.GetOrFetchMany(ctx, keys, ttl, c.fetchManyThrough(ttl, fetchFn))
+}
+
+// fetchManyThrough is the FetchManyFunc counterpart of fetchThrough.
+func (c *WriteBehindCacher[T]) fetchManyThrough(ttl time.Duration, fetchFn FetchManyFunc[T]) FetchManyFunc[T] {
+	return func(ctx context.Context, keys []string) (map[string]T, error) {
+		found := make(map[string]T, len(keys))
+
+		c.mu.Lock()
+		for _, key := range keys {
+			if write, ok := c.pending[key]; ok {
+				found[key] = write.value
+			}
+		}
+		c.mu.Unlock()
+
+		missing := missingKeys(keys, func(key string) bool {
+			if _, ok := found[key]; ok {
+				return true
+			}
+
+			// L2 is a cache too, so a read error falls through to the source
+			if val, ok, err := c.store.Get(ctx, key); err == nil && ok {
+				found[key] = val
+				return true
+			}
+
+			return false
+		})
+
+		if len(missing) == 0 {
+			return found, nil
+		}
+
+		fetched, err := fetchFn(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range missing {
+			if val, ok := fetched[key]; ok {
+				found[key] = val
+				_ = c.enqueue(key, val, ttl)
+			}
+		}
+
+		return found, nil
+	}
+}
+
+// fetchThrough wraps fetchFn so that an L1 miss is served from the pending buffer
+// or L2 first, and freshly fetched values are queued for L2.
+func (c *WriteBehindCacher[T]) fetchThrough(key string, ttl time.Duration, fetchFn FetchFunc[T]) FetchFunc[T] {
+	return func(ctx context.Context) (T, error) {
+		c.mu.Lock()
+		write, found := c.pending[key]
+		c.mu.Unlock()
+		if found {
+			return write.value, nil
+		}
+
+		// L2 is a cache too, so a read error falls through to the source
+		if val, found, err := c.store.Get(ctx, key); err == nil && found {
+			return val, nil
+		}
+
+		val, err := fetchFn(ctx)
+		if err != nil {
+			return val, err
+		}
+
+		_ = c.enqueue(key, val, ttl)
+		return val, nil
+	}
+}
+
+// Set stores value in L1 immediately and buffers it for writing to L2.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - key: The cache key to set
+//   - value: The value to store
+//   - ttl: Time-to-live duration for the value in both tiers
+//
+// Returns:
+//   - ErrCacherClosed after Close; ctx.Err() if ctx is done
+func (c *WriteBehindCacher[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrCacherClosed
+	}
+
+	// L1 and the buffer are updated under the same lock Delete holds, so a
+	// concurrent Delete cannot land between them and leave the key in only one.
+	c.l1.store(key, value, ttl)
+	c.enqueueLocked(key, value, ttl)
+	return nil
+}
+
+// enqueue buffers a write for L2, replacing any buffered value for the same key.
+func (c *WriteBehindCacher[T]) enqueue(key string, value T, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrCacherClosed
+	}
+
+	c.enqueueLocked(key, value, ttl)
+	return nil
+}
+
+// enqueueLocked is enqueue for callers that already hold c.mu.
+func (c *WriteBehindCacher[T]) enqueueLocked(key string, value T, ttl time.Duration) {
+	c.pending[key] = pendingWrite[T]{value: value, ttl: ttl}
+	if len(c.pending) >= c.batchSize {
+		select {
+		case c.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush writes all buffered values to the backing store now.
+//
+// Parameters:
+//   - ctx: Context for the backing store writes
+//
+// Returns:
+//   - nil if every write succeeded; otherwise the write errors joined together
+func (c *WriteBehindCacher[T]) Flush(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = make(map[string]pendingWrite[T])
+	c.mu.Unlock()
+
+	var errs []error
+	for key, write := range batch {
+		if err := c.store.Set(ctx, key, write.value, write.ttl); err != nil {
+			err = fmt.Errorf("failed to flush key %s: %w", key, err)
+			errs = append(errs, err)
+			if c.onFlushError != nil {
+				c.onFlushError(key, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// flushLoop flushes buffered writes on every interval tick or when a batch fills up,
+// until Close is called.
+func (c *WriteBehindCacher[T]) flushLoop() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		case <-c.flushNow:
+		}
+
+		// Errors are reported through the flush error handler
+		_ = c.Flush(context.Background())
+	}
+}
+
+// Close stops the background flusher and flushes all buffered writes. Later
+// writes return ErrCacherClosed; reads are still served from L1 and L2.
+// Idempotent; only the first call flushes.
+//
+// Parameters:
+//   - ctx: Context for the final backing store writes
+//
+// Returns:
+//   - nil if the final flush succeeded; otherwise the write errors joined together
+func (c *WriteBehindCacher[T]) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+
+		close(c.stop)
+		<-c.done
+
+		c.closeErr = c.Flush(ctx)
+	})
+
+	return c.closeErr
+}
+
+// Delete removes key from L1, the pending buffer, and L2.
+func (c *WriteBehindCacher[T]) Delete(ctx context.Context, key string) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	delete(c.pending, key)
+	err := c.l1.Delete(ctx, key)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return c.store.Delete(ctx, key)
+}
+
+// Clear removes all items from L1, discards buffered writes, and clears the
+// backing store. Like Delete, Clear waits for an in-flight flush, so no write
+// buffered before it reaches the backing store after it returns.
+func (c *WriteBehindCacher[T]) Clear(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	c.pending = make(map[string]pendingWrite[T])
+	err := c.l1.Clear(ctx)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return c.store.Clear(ctx)
+}
+
+// ItemCount returns the number of items in L1.
+func (c *WriteBehindCacher[T]) ItemCount(ctx context.Context) (int, error) {
+	return c.l1.ItemCount(ctx)
+}
+
+// DeleteByPrefix deletes all keys with the given prefix from L1, the write
+// buffer, and the backing store. A key may live in L1, in the backing store,
+// or in both, so the count returned is the larger of the two tiers' counts.
+func (c *WriteBehindCacher[T]) DeleteByPrefix(ctx context.Context, prefix string) (int, error) {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	for key := range c.pending {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.pending, key)
+		}
+	}
+	deleted, err := c.l1.DeleteByPrefix(ctx, prefix)
+	c.mu.Unlock()
+	if err != nil {
+		return deleted, err
+	}
+
+	storeDeleted, err := c.store.DeleteByPrefix(ctx, prefix)
+	return max(deleted, storeDeleted), err
+}
@@ -0,0 +1,336 @@
+package cacher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore is an in-memory BackingStore that records writes.
+type fakeStore[T any] struct {
+	mu     sync.Mutex
+	data   map[string]T
+	sets   int
+	setErr error
+}
+
+func newFakeStore[T any]() *fakeStore[T] {
+	return &fakeStore[T]{data: make(map[string]T)}
+}
+
+func (s *fakeStore[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	return v, ok, nil
+}
+
+func (s *fakeStore[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.setErr != nil {
+		return s.setErr
+	}
+	s.sets++
+	s.data[key] = value
+	return nil
+}
+
+func (s *fakeStore[T]) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s *fakeStore[T]) DeleteByPrefix(ctx context.Context, prefix string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.data, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *fakeStore[T]) Clear(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.data)
+	return nil
+}
+
+func (s *fakeStore[T]) get(key string) (T, bool) {
+	v, ok, _ := s.Get(context.Background(), key)
+	return v, ok
+}
+
+func (s *fakeStore[T]) setCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sets
+}
+
+func TestWriteBehindCacher_ImplementsCacher(t *testing.T) {
+	var _ Cacher[string] = (*WriteBehindCacher[string])(nil)
+}
+
+func TestWriteBehindCacher_SetReachesStoreEventually(t *testing.T) {
+	store := newFakeStore[string]()
+	c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[string](20*time.Millisecond))
+	defer func() { _ = c.Close(context.Background()) }()
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "key", "value", time.Minute))
+
+	// L1 is updated immediately
+	val, err := c.GetOrFetch(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		t.Fatal("fetch called for a key set in L1")
+		return "", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "value", val)
+
+	assert.Eventually(t, func() bool {
+		v, ok := store.get("key")
+		return ok && v == "value"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWriteBehindCacher_CoalescesWrites(t *testing.T) {
+	store := newFakeStore[int]()
+	c := NewWriteBehindCacher[int](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[int](time.Hour))
+	ctx := context.Background()
+
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, c.Set(ctx, "counter", i, time.Minute))
+	}
+	require.NoError(t, c.Flush(ctx))
+
+	v, ok := store.get("counter")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	assert.Equal(t, 1, store.setCount())
+	require.NoError(t, c.Close(ctx))
+}
+
+func TestWriteBehindCacher_BatchSizeTriggersFlush(t *testing.T) {
+	store := newFakeStore[int]()
+	c := NewWriteBehindCacher[int](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[int](time.Hour), WithFlushBatchSize[int](3))
+	defer func() { _ = c.Close(context.Background()) }()
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", 1, time.Minute))
+	require.NoError(t, c.Set(ctx, "b", 2, time.Minute))
+	require.NoError(t, c.Set(ctx, "c", 3, time.Minute))
+
+	assert.Eventually(t, func() bool { return store.setCount() == 3 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWriteBehindCacher_CloseFlushesBuffer(t *testing.T) {
+	store := newFakeStore[string]()
+	c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[string](time.Hour))
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", "1", time.Minute))
+	require.NoError(t, c.Set(ctx, "b", "2", time.Minute))
+	_, ok := store.get("a")
+	assert.False(t, ok, "write reached L2 before flush")
+
+	require.NoError(t, c.Close(ctx))
+	v, ok := store.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+	v, ok = store.get("b")
+	assert.True(t, ok)
+	assert.Equal(t, "2", v)
+
+	assert.ErrorIs(t, c.Set(ctx, "c", "3", time.Minute), ErrCacherClosed)
+	assert.NoError(t, c.Close(ctx))
+}
+
+func TestWriteBehindCacher_GetOrFetch(t *testing.T) {
+	store := newFakeStore[string]()
+	store.data["in-l2"] = "from-l2"
+	c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[string](time.Hour))
+	ctx := context.Background()
+
+	var fetches atomic.Int32
+	fetchFn := func(ctx context.Context) (string, error) {
+		fetches.Add(1)
+		return "fetched", nil
+	}
+
+	t.Run("L1 miss is served from L2", func(t *testing.T) {
+		val, err := c.GetOrFetch(ctx, "in-l2", time.Minute, fetchFn)
+		require.NoError(t, err)
+		assert.Equal(t, "from-l2", val)
+		assert.Equal(t, int32(0), fetches.Load())
+	})
+
+	t.Run("miss in both tiers fetches and writes behind", func(t *testing.T) {
+		val, err := c.GetOrFetch(ctx, "new", time.Minute, fetchFn)
+		require.NoError(t, err)
+		assert.Equal(t, "fetched", val)
+		assert.Equal(t, int32(1), fetches.Load())
+
+		require.NoError(t, c.Flush(ctx))
+		v, ok := store.get("new")
+		assert.True(t, ok)
+		assert.Equal(t, "fetched", v)
+	})
+
+	require.NoError(t, c.Close(ctx))
+}
+
+func TestWriteBehindCacher_DeleteDropsPendingWrite(t *testing.T) {
+	store := newFakeStore[string]()
+	c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[string](time.Hour))
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "key", "value", time.Minute))
+	require.NoError(t, c.Delete(ctx, "key"))
+	require.NoError(t, c.Close(ctx))
+
+	_, ok := store.get("key")
+	assert.False(t, ok)
+	assert.Equal(t, 0, store.setCount())
+}
+
+func TestWriteBehindCacher_BulkDeletesReachStore(t *testing.T) {
+	ctx := context.Background()
+	fetchFn := func(ctx context.Context) (string, error) { return "fetched", nil }
+
+	t.Run("DeleteByPrefix", func(t *testing.T) {
+		store := newFakeStore[string]()
+		store.data["user:1"] = "flushed"
+		store.data["order:1"] = "kept"
+		c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+			WithFlushInterval[string](time.Hour))
+		defer func() { _ = c.Close(ctx) }()
+
+		require.NoError(t, c.Set(ctx, "user:2", "buffered", time.Minute))
+		deleted, err := c.DeleteByPrefix(ctx, "user:")
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		// Neither key comes back from L2 or the buffer
+		for _, key := range []string{"user:1", "user:2"} {
+			val, err := c.GetOrFetch(ctx, key, time.Minute, fetchFn)
+			require.NoError(t, err)
+			assert.Equal(t, "fetched", val, key)
+		}
+		_, ok := store.get("order:1")
+		assert.True(t, ok)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		store := newFakeStore[string]()
+		store.data["flushed"] = "old"
+		c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+			WithFlushInterval[string](time.Hour))
+		defer func() { _ = c.Close(ctx) }()
+
+		require.NoError(t, c.Set(ctx, "buffered", "old", time.Minute))
+		require.NoError(t, c.Clear(ctx))
+
+		for _, key := range []string{"flushed", "buffered"} {
+			val, err := c.GetOrFetch(ctx, key, time.Minute, fetchFn)
+			require.NoError(t, err)
+			assert.Equal(t, "fetched", val, key)
+		}
+	})
+}
+
+func TestWriteBehindCacher_SetRacingDelete(t *testing.T) {
+	c := NewWriteBehindCacher[string](newFakeStore[string](), cache.NoExpiration, time.Minute,
+		WithFlushInterval[string](time.Hour))
+	defer func() { _ = c.Close(context.Background()) }()
+	ctx := context.Background()
+
+	for i := 0; i < 2000; i++ {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = c.Set(ctx, "key", "value", time.Minute)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = c.Delete(ctx, "key")
+		}()
+		wg.Wait()
+
+		// Whichever ran last wins in both L1 and the buffer
+		_, inL1 := c.l1.cache.Get("key")
+		c.mu.Lock()
+		_, buffered := c.pending["key"]
+		c.mu.Unlock()
+		require.Equal(t, buffered, inL1, "iteration %d", i)
+
+		require.NoError(t, c.Delete(ctx, "key"))
+	}
+}
+
+func TestWriteBehindCacher_FlushErrorHandler(t *testing.T) {
+	store := newFakeStore[string]()
+	store.setErr = errors.New("l2 down")
+
+	var failedKeys []string
+	var mu sync.Mutex
+	c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[string](time.Hour),
+		WithFlushErrorHandler[string](func(key string, err error) {
+			mu.Lock()
+			failedKeys = append(failedKeys, key)
+			mu.Unlock()
+		}))
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "key", "value", time.Minute))
+	err := c.Close(ctx)
+	assert.ErrorIs(t, err, store.setErr)
+	assert.Equal(t, []string{"key"}, failedKeys)
+}
+
+func TestWriteBehindCacher_GetOrFetchMany(t *testing.T) {
+	store := newFakeStore[string]()
+	store.data["l2"] = "from-l2"
+	c := NewWriteBehindCacher[string](store, cache.NoExpiration, time.Minute,
+		WithFlushInterval[string](time.Hour))
+	ctx := context.Background()
+	require.NoError(t, c.Set(ctx, "l1", "from-l1", time.Minute))
+
+	var requested []string
+	vals, err := c.GetOrFetchMany(ctx, []string{"l1", "l2", "src"}, time.Minute,
+		func(ctx context.Context, missingKeys []string) (map[string]string, error) {
+			requested = missingKeys
+			return map[string]string{"src": "from-source"}, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src"}, requested)
+	assert.Equal(t, map[string]string{"l1": "from-l1", "l2": "from-l2", "src": "from-source"}, vals)
+
+	require.NoError(t, c.Flush(ctx))
+	v, ok := store.get("src")
+	assert.True(t, ok)
+	assert.Equal(t, "from-source", v)
+	require.NoError(t, c.Close(ctx))
+}
@@ -4,15 +4,28 @@
 package eventdriventcpclient
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/cyberinferno/go-utils/logger"
 )
 
+// maxMessageSize is the largest frame (prefix included) accepted in length-prefixed mode, for both reads and framed sends.
+const maxMessageSize = 16 * 1024 * 1024
+
+// errUpgrading is returned for reads and writes attempted while UpgradeToTLS runs.
+var errUpgrading = errors.New("tls upgrade in progress")
+
 // ConnectionState represents the current state of the TCP connection.
 type ConnectionState int
 
@@ -49,6 +62,11 @@ type ConnectionStateEvent struct {
 	Address   string          // The remote address (e.g. "host:port")
 	Timestamp time.Time       // When the state change occurred
 	Error     error           // Non-nil if the state change was due to an error
+
+	AttemptNumber    int           // Reconnect attempt number (1-based) this event belongs to; 0 for a manual connect
+	NextDelay        time.Duration // Delay before the next reconnect attempt for Reconnecting events; 0 otherwise
+	AttemptStartedAt time.Time     // When the dial started, for Connecting, Connected, and failed-dial events
+	DialDuration     time.Duration // How long the dial took, for Connected and failed-dial events
 }
 
 // DataReceivedEvent is emitted when data is read from the connection.
@@ -78,14 +96,71 @@ type DataReceivedHandler func(event DataReceivedEvent)
 // Handlers are invoked from goroutines; implementations must be safe for concurrent use.
 type ErrorHandler func(event ErrorEvent)
 
+// Stats is a snapshot of the client's cumulative traffic counters, as returned by
+// EventDrivenTCPClient.Stats. Counters survive reconnects and are only zeroed by ResetStats.
+// Each field is read atomically, but the snapshot as a whole is point-in-time and not
+// transactionally consistent across fields.
+type Stats struct {
+	BytesSent        uint64 // Bytes written to the connection by Send and everything built on it
+	BytesReceived    uint64 // Bytes read from the connection, including length prefixes
+	MessagesReceived uint64 // DataReceivedEvents emitted (chunks in stream mode, frames in length-prefixed mode)
+	ReconnectCount   uint64 // Successful automatic reconnects
+}
+
+// clientStats holds the live counters behind Stats.
+type clientStats struct {
+	bytesSent        atomic.Uint64
+	bytesReceived    atomic.Uint64
+	messagesReceived atomic.Uint64
+	reconnectCount   atomic.Uint64
+}
+
+// CorrelationIDFunc extracts the correlation ID from an inbound message, as delivered in
+// DataReceivedEvent.Data (length prefix included in length-prefixed mode). It returns false
+// when the message carries no correlation ID.
+type CorrelationIDFunc func(data []byte) (uint32, bool)
+
+// AckFunc is called once a message queued with SendWithAck has been written to
+// the connection. err is nil when the write succeeded, or the reason it failed.
+type AckFunc func(err error)
+
+// dataHandlerEntry is a handler registered with AddDataReceivedHandler.
+type dataHandlerEntry struct {
+	id      uint64
+	handler DataReceivedHandler
+}
+
+// outboundMessage is a message waiting in the send queue for the write loop.
+type outboundMessage struct {
+	data []byte
+	ack  AckFunc
+}
+
 // Config holds configuration for the event-driven TCP client.
 type Config struct {
-	// Address is the "host:port" to connect to (e.g. "localhost:8080").
+	// Network is the network passed to the dialer, such as "tcp" or "unix"; empty means "tcp".
+	Network string
+	// Address is the "host:port" to connect to (e.g. "localhost:8080"), or the socket path
+	// when Network is "unix".
 	Address string
 	// AutoReconnect enables automatic reconnection when the connection is lost.
 	AutoReconnect bool
-	// ReconnectInterval is the delay between reconnection attempts when AutoReconnect is true.
+	// ReconnectInterval is the delay before the first reconnection attempt when AutoReconnect is true.
 	ReconnectInterval time.Duration
+	// MaxReconnectInterval caps the reconnect delay as it grows with ReconnectBackoffFactor;
+	// 0 means no cap.
+	MaxReconnectInterval time.Duration
+	// ReconnectBackoffFactor multiplies the reconnect delay after each failed attempt, and a
+	// random jitter of up to 10% is added to each delay. Values <= 1 disable backoff and jitter,
+	// so every attempt waits exactly ReconnectInterval.
+	ReconnectBackoffFactor float64
+	// MaxReconnectAttempts is the number of consecutive failed reconnect attempts after which
+	// the client gives up and stays Disconnected; 0 means unlimited.
+	MaxReconnectAttempts int
+	// ReconnectPolicy, when set, decides the delay before each reconnect attempt and when to
+	// give up, replacing ReconnectInterval, MaxReconnectInterval, ReconnectBackoffFactor, and
+	// MaxReconnectAttempts.
+	ReconnectPolicy ReconnectPolicy
 	// ReadBufferSize is the size of the read buffer when DataLengthBasedRead is false.
 	ReadBufferSize int
 	// WriteTimeout is the max duration for a single write; 0 means no timeout.
@@ -94,9 +169,69 @@ type Config struct {
 	ReadTimeout time.Duration
 	// ConnectionTimeout is the max duration for establishing a new connection.
 	ConnectionTimeout time.Duration
-	// DataLengthBasedRead, when true, reads a 4-byte little-endian length prefix
-	// and then that many bytes per message instead of streaming into fixed-size chunks.
+	// KeepAlive, when greater than zero, enables OS-level TCP keep-alive probes at this period.
+	// When zero the socket is left untouched. This is independent of any application-level heartbeat.
+	KeepAlive time.Duration
+	// SocketReadBufferSize, when greater than zero, sets the kernel receive buffer size (SO_RCVBUF)
+	// of each new connection. 0 keeps the OS default.
+	SocketReadBufferSize int
+	// SocketWriteBufferSize, when greater than zero, sets the kernel send buffer size (SO_SNDBUF)
+	// of each new connection. 0 keeps the OS default.
+	SocketWriteBufferSize int
+	// HeartbeatInterval, when greater than zero, makes the client send HeartbeatPayload at this
+	// cadence while Connected, so that a vanished peer surfaces as a write error and triggers a
+	// reconnect. 0 disables the heartbeat.
+	HeartbeatInterval time.Duration
+	// HeartbeatPayload is the raw data written on each heartbeat; it is sent as-is, without framing.
+	HeartbeatPayload []byte
+	// DataLengthBasedRead, when true, reads a length prefix (see LengthPrefixBytes and
+	// BigEndianLength) and delivers each frame as one message instead of streaming into
+	// fixed-size chunks. The length counts the whole frame including the prefix, and the
+	// delivered data includes the prefix.
 	DataLengthBasedRead bool
+	// LengthPrefixBytes is the size of the length prefix used when DataLengthBasedRead is true.
+	// Supported values are 2, 4, and 8; 0 is treated as 4.
+	LengthPrefixBytes int
+	// BigEndianLength, when true, encodes and decodes the length prefix as big-endian
+	// instead of little-endian.
+	BigEndianLength bool
+	// DelimiterBasedRead, when true, splits the stream on Delimiter and delivers each
+	// delimited message as one event. It cannot be combined with DataLengthBasedRead.
+	DelimiterBasedRead bool
+	// Delimiter is the byte that terminates each message when DelimiterBasedRead is true.
+	Delimiter byte
+	// KeepDelimiter, when true, leaves the delimiter at the end of each delivered message
+	// instead of stripping it.
+	KeepDelimiter bool
+	// MaxLineLength is the largest message, excluding the delimiter, accepted when
+	// DelimiterBasedRead is true; longer messages are reported as an error and the
+	// connection is dropped. 0 is treated as 16 MiB.
+	MaxLineLength int
+	// SendQueueSize is the capacity of the queue used by SendWithAck. When the queue
+	// is full, SendWithAck blocks until the write loop makes room.
+	SendQueueSize int
+	// SerialHandlers, when true, delivers connection-state, data, and error events in arrival
+	// order from a single goroutine instead of starting one goroutine per event. When the event
+	// queue (EventQueueSize) is full, the read loop blocks on data events until the handlers
+	// catch up; events are never dropped while the client is open. State and error events
+	// never block, so handlers may call Close or Disconnect even when the queue is full.
+	SerialHandlers bool
+	// EventQueueSize is the capacity of the event queue used when SerialHandlers is true.
+	EventQueueSize int
+	// CorrelationIDFunc extracts the correlation ID from inbound messages so that SendRequest
+	// can match responses to requests. SendRequest fails when it is nil.
+	CorrelationIDFunc CorrelationIDFunc
+	// Logger, when set, receives state transitions and sends/receives at debug level and
+	// errors at error level, in addition to the registered handlers. Nil disables logging.
+	Logger logger.Logger
+	// ReplayStateOnRegister, when true, makes OnConnectionState immediately deliver one
+	// synthetic event carrying the current state to the newly registered handler, so a
+	// handler registered after Connect still learns the present state.
+	ReplayStateOnRegister bool
+	// TLSConfig, when set, makes the client perform a TLS handshake over each new
+	// connection, bounded by ConnectionTimeout. If ServerName is empty, the host part of
+	// Address is used. Set NextProtos to offer ALPN protocols; see NegotiatedProtocol.
+	TLSConfig *tls.Config
 }
 
 // DefaultEventDrivenTCPClientConfig returns a Config with default values for the given address.
@@ -106,19 +241,56 @@ type Config struct {
 //   - address: The "host:port" to connect to
 //
 // Returns:
-//   - A Config with defaults: ReconnectInterval 5s, ReadBufferSize 4096,
-//     WriteTimeout 10s, ConnectionTimeout 10s, ReadTimeout 0, DataLengthBasedRead false.
+//   - A Config with defaults: Network "tcp", ReconnectInterval 5s, MaxReconnectInterval 0,
+//     ReconnectBackoffFactor 1 (a fixed interval), MaxReconnectAttempts 0, ReadBufferSize 4096,
+//     WriteTimeout 10s, ConnectionTimeout 10s, ReadTimeout 0, KeepAlive 0, SocketReadBufferSize 0,
+//     SocketWriteBufferSize 0, HeartbeatInterval 0,
+//     DataLengthBasedRead false, LengthPrefixBytes 4, BigEndianLength false,
+//     DelimiterBasedRead false, Delimiter '\n', KeepDelimiter false, MaxLineLength 64 KiB, SendQueueSize 256,
+//     SerialHandlers false, EventQueueSize 256.
 func DefaultEventDrivenTCPClientConfig(address string) Config {
 	return Config{
-		Address:             address,
-		AutoReconnect:       false,
-		ReconnectInterval:   5 * time.Second,
-		ReadBufferSize:      4096,
-		WriteTimeout:        10 * time.Second,
-		ReadTimeout:         0,
-		ConnectionTimeout:   10 * time.Second,
-		DataLengthBasedRead: false,
+		Network:                "tcp",
+		Address:                address,
+		AutoReconnect:          false,
+		ReconnectInterval:      5 * time.Second,
+		MaxReconnectInterval:   0,
+		ReconnectBackoffFactor: 1,
+		MaxReconnectAttempts:   0,
+		ReadBufferSize:         4096,
+		WriteTimeout:           10 * time.Second,
+		ReadTimeout:            0,
+		ConnectionTimeout:      10 * time.Second,
+		KeepAlive:              0,
+		SocketReadBufferSize:   0,
+		SocketWriteBufferSize:  0,
+		HeartbeatInterval:      0,
+		DataLengthBasedRead:    false,
+		LengthPrefixBytes:      4,
+		BigEndianLength:        false,
+		DelimiterBasedRead:     false,
+		Delimiter:              '\n',
+		KeepDelimiter:          false,
+		MaxLineLength:          64 * 1024,
+		SendQueueSize:          256,
+		SerialHandlers:         false,
+		EventQueueSize:         256,
+	}
+}
+
+// validate reports an error if the config contains unsupported settings.
+func (cfg Config) validate() error {
+	switch cfg.LengthPrefixBytes {
+	case 0, 2, 4, 8:
+	default:
+		return fmt.Errorf("unsupported length prefix size %d: must be 2, 4, or 8", cfg.LengthPrefixBytes)
+	}
+
+	if cfg.DataLengthBasedRead && cfg.DelimiterBasedRead {
+		return fmt.Errorf("DataLengthBasedRead and DelimiterBasedRead are mutually exclusive")
 	}
+
+	return nil
 }
 
 // EventDrivenTCPClient is a TCP client that drives I/O and connection lifecycle
@@ -133,12 +305,61 @@ type EventDrivenTCPClient struct {
 	onDataReceived    DataReceivedHandler
 	onError           ErrorHandler
 
+	dataHandlers      []dataHandlerEntry
+	nextDataHandlerID uint64
+
 	mu            sync.RWMutex
 	stopChan      chan struct{}
 	reconnectChan chan struct{}
 	wg            sync.WaitGroup
 	closed        bool
 	reconnecting  bool
+
+	reconnectPolicy      ReconnectPolicy
+	reconnectLoopStarted bool
+	reconnectAttempt     int
+
+	sendQueue  chan outboundMessage
+	writerOnce sync.Once
+
+	heartbeatStop chan struct{}
+
+	stats clientStats
+
+	pendingRequests map[uint32]chan []byte
+
+	// pendingWrites counts Send calls in progress plus SendWithAck messages not yet written.
+	// drainWaiters are closed when the count reaches zero. Once closing is set, new writes
+	// are rejected.
+	pendingWrites int
+	drainWaiters  []chan struct{}
+	closing       bool
+
+	// resumeChan is non-nil while reading is paused and is closed to wake paused read loops.
+	resumeChan chan struct{}
+
+	// upgrading is set while UpgradeToTLS replaces the connection: the read loop exits
+	// and writes are rejected until it finishes. readDone is closed when the read loop
+	// for the current connection exits.
+	upgrading bool
+	readDone  chan struct{}
+
+	// undelivered holds complete messages read but not delivered because the client was
+	// closed; DrainReceived returns them.
+	undelivered [][]byte
+
+	events         chan func()
+	eventsStop     chan struct{}
+	dispatcherOnce sync.Once
+
+	// eventsMu guards the backlog: events that could not wait for room in events,
+	// run by the event loop once events is empty. backlogDrained is closed when
+	// the loop takes the backlog, and eventsDone is set once the loop has exited.
+	eventsMu       sync.Mutex
+	backlog        []func()
+	backlogDrained chan struct{}
+	eventsWake     chan struct{}
+	eventsDone     bool
 }
 
 // NewEventDrivenTCPClient creates a new event-driven TCP client with the given config.
@@ -150,24 +371,66 @@ type EventDrivenTCPClient struct {
 // Returns:
 //   - A new *EventDrivenTCPClient ready to use; call Close when done to release resources.
 func NewEventDrivenTCPClient(config Config) *EventDrivenTCPClient {
+	queueSize := config.SendQueueSize
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	eventQueueSize := config.EventQueueSize
+	if eventQueueSize < 0 {
+		eventQueueSize = 0
+	}
+
+	reconnectPolicy := config.ReconnectPolicy
+	if reconnectPolicy == nil {
+		reconnectPolicy = BackoffPolicy{
+			Interval:    config.ReconnectInterval,
+			MaxInterval: config.MaxReconnectInterval,
+			Factor:      config.ReconnectBackoffFactor,
+			MaxAttempts: config.MaxReconnectAttempts,
+		}
+	}
+
 	return &EventDrivenTCPClient{
-		config:        config,
-		state:         Disconnected,
-		stopChan:      make(chan struct{}),
-		reconnectChan: make(chan struct{}, 1),
+		config:          config,
+		reconnectPolicy: reconnectPolicy,
+		state:           Disconnected,
+		stopChan:        make(chan struct{}),
+		reconnectChan:   make(chan struct{}, 1),
+		sendQueue:       make(chan outboundMessage, queueSize),
+
+		pendingRequests: make(map[uint32]chan []byte),
+
+		events:     make(chan func(), eventQueueSize),
+		eventsStop: make(chan struct{}),
+		eventsWake: make(chan struct{}, 1),
 	}
 }
 
 // OnConnectionState registers the handler for connection state changes.
 // Only one handler is active; repeated calls replace the previous handler.
-// Pass nil to clear the handler.
+// Pass nil to clear the handler. With Config.ReplayStateOnRegister, the handler
+// also receives one event with the current state right away; if the state
+// changes concurrently, the handler may see that state twice.
 //
 // Parameters:
 //   - handler: Function called on state changes (Connecting, Connected, Disconnected, etc.)
 func (c *EventDrivenTCPClient) OnConnectionState(handler ConnectionStateHandler) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.onConnectionState = handler
+	state := c.state
+	c.mu.Unlock()
+
+	if handler == nil || !c.config.ReplayStateOnRegister {
+		return
+	}
+
+	event := ConnectionStateEvent{
+		State:     state,
+		Address:   c.config.Address,
+		Timestamp: time.Now(),
+	}
+	c.dispatch(func() { handler(event) })
 }
 
 // OnDataReceived registers the handler for incoming data.
@@ -182,6 +445,42 @@ func (c *EventDrivenTCPClient) OnDataReceived(handler DataReceivedHandler) {
 	c.onDataReceived = handler
 }
 
+// AddDataReceivedHandler registers an additional handler for incoming data. Unlike
+// OnDataReceived, it does not replace other handlers: every added handler, plus the one
+// set with OnDataReceived, receives each event. Handlers may be added and removed at any
+// time, including while data is being read.
+//
+// Parameters:
+//   - handler: Function called with each chunk or message of received data
+//
+// Returns:
+//   - A function that deregisters handler; calling it more than once is a no-op.
+func (c *EventDrivenTCPClient) AddDataReceivedHandler(handler DataReceivedHandler) (remove func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextDataHandlerID++
+	id := c.nextDataHandlerID
+
+	// Copy on write so that emitDataReceived can iterate a snapshot without holding mu
+	handlers := make([]dataHandlerEntry, len(c.dataHandlers), len(c.dataHandlers)+1)
+	copy(handlers, c.dataHandlers)
+	c.dataHandlers = append(handlers, dataHandlerEntry{id: id, handler: handler})
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		handlers := make([]dataHandlerEntry, 0, len(c.dataHandlers))
+		for _, entry := range c.dataHandlers {
+			if entry.id != id {
+				handlers = append(handlers, entry)
+			}
+		}
+		c.dataHandlers = handlers
+	}
+}
+
 // OnError registers the handler for read, write, and connection errors.
 // Only one handler is active; repeated calls replace the previous handler.
 // Pass nil to clear the handler.
@@ -195,12 +494,32 @@ func (c *EventDrivenTCPClient) OnError(handler ErrorHandler) {
 }
 
 // Connect establishes a TCP connection to the configured address.
-// It returns an error if the client is closed, already connected/connecting, or if the dial fails.
-// When AutoReconnect is enabled, a read goroutine and reconnect goroutine are started.
+// It is equivalent to ConnectWithContext with context.Background().
 //
 // Returns:
 //   - nil on success; otherwise an error (e.g. "client is closed", "already connected or connecting", or dial error).
 func (c *EventDrivenTCPClient) Connect() error {
+	return c.ConnectWithContext(context.Background())
+}
+
+// ConnectWithContext establishes a TCP connection to the configured address,
+// aborting the dial as soon as ctx is cancelled. ConnectionTimeout still bounds the dial.
+// It returns an error if the config is invalid (e.g. an unsupported LengthPrefixBytes),
+// the client is closed, already connected/connecting, or if the dial fails.
+// When AutoReconnect is enabled, a read goroutine and reconnect goroutine are started.
+// If ctx is cancelled while connecting, the client returns to Disconnected and ctx.Err() is returned.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the dial
+//
+// Returns:
+//   - nil on success; ctx.Err() if the context was cancelled; otherwise an error
+//     (e.g. "client is closed", "already connected or connecting", or dial error).
+func (c *EventDrivenTCPClient) ConnectWithContext(ctx context.Context) error {
+	if err := c.config.validate(); err != nil {
+		return err
+	}
+
 	c.mu.Lock()
 	if c.closed {
 		c.mu.Unlock()
@@ -212,7 +531,7 @@ func (c *EventDrivenTCPClient) Connect() error {
 	}
 	c.mu.Unlock()
 
-	return c.connect()
+	return c.connect(ctx)
 }
 
 // Disconnect closes the current connection and moves to Disconnected state.
@@ -222,25 +541,51 @@ func (c *EventDrivenTCPClient) Connect() error {
 // Returns:
 //   - nil if already disconnected/closed, or the error from closing the connection.
 func (c *EventDrivenTCPClient) Disconnect() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.state == Disconnected || c.state == Closed {
+	state := c.GetState()
+	if state == Disconnected || state == Closed {
 		return nil
 	}
 
 	return c.disconnect()
 }
 
-func (c *EventDrivenTCPClient) disconnect() error {
-	if c.conn != nil {
-		err := c.conn.Close()
-		c.conn = nil
-		c.setState(Disconnected, nil)
+// DisconnectGraceful waits for in-flight Send calls and queued SendWithAck messages
+// to be written, then disconnects like Disconnect. Unlike CloseGracefully, new sends
+// are still accepted while waiting, and the client may be reconnected afterwards.
+// If ctx is done first, the connection is closed immediately and ctx.Err() is returned.
+//
+// Parameters:
+//   - ctx: Context bounding how long to wait for pending writes
+//
+// Returns:
+//   - nil if pending writes completed and the connection closed cleanly; ctx.Err() if
+//     the wait was cut short; otherwise the error from closing the connection.
+func (c *EventDrivenTCPClient) DisconnectGraceful(ctx context.Context) error {
+	waitErr := c.waitForWrites(ctx)
+	if err := c.Disconnect(); err != nil && waitErr == nil {
 		return err
 	}
 
-	return nil
+	return waitErr
+}
+
+// disconnect closes and clears the current connection. It must be called
+// without holding mu, since the state change takes the lock itself.
+func (c *EventDrivenTCPClient) disconnect() error {
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.stopHeartbeat()
+	c.wakePausedReaders()
+	c.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	err := conn.Close()
+	c.setState(Disconnected, nil)
+	return err
 }
 
 // Close shuts down the client, closes the connection, and stops all goroutines.
@@ -257,6 +602,7 @@ func (c *EventDrivenTCPClient) Close() error {
 	}
 
 	c.closed = true
+	c.stopHeartbeat()
 
 	if c.conn != nil {
 		_ = c.conn.Close()
@@ -268,10 +614,77 @@ func (c *EventDrivenTCPClient) Close() error {
 	c.wg.Wait()
 
 	c.setState(Closed, nil)
+	close(c.eventsStop)
 
 	return nil
 }
 
+// CloseGracefully shuts the client down without losing writes that are already under way.
+// It stops reconnects, rejects new Send and SendWithAck calls, waits for in-flight Send
+// calls and queued SendWithAck messages to complete, and then closes the client like Close.
+// If ctx is done first, the client is closed immediately and ctx.Err() is returned.
+//
+// Parameters:
+//   - ctx: Context bounding how long to wait for pending writes
+//
+// Returns:
+//   - nil if all pending writes completed before closing; ctx.Err() if the wait was cut short.
+func (c *EventDrivenTCPClient) CloseGracefully(ctx context.Context) error {
+	c.mu.Lock()
+	c.closing = true
+	c.mu.Unlock()
+
+	err := c.waitForWrites(ctx)
+	_ = c.Close()
+	return err
+}
+
+// waitForWrites blocks until no writes are pending or ctx is done.
+func (c *EventDrivenTCPClient) waitForWrites(ctx context.Context) error {
+	c.mu.Lock()
+	if c.pendingWrites == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	drained := make(chan struct{})
+	c.drainWaiters = append(c.drainWaiters, drained)
+	c.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// beginWrite registers a pending write. It returns false once CloseGracefully has been called.
+func (c *EventDrivenTCPClient) beginWrite() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closing {
+		return false
+	}
+
+	c.pendingWrites++
+	return true
+}
+
+// endWrite marks a pending write as finished and wakes waitForWrites when none remain.
+func (c *EventDrivenTCPClient) endWrite() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pendingWrites--
+	if c.pendingWrites == 0 {
+		for _, drained := range c.drainWaiters {
+			close(drained)
+		}
+		c.drainWaiters = nil
+	}
+}
+
 // Send writes data to the connection. It returns an error if not connected or if the write fails.
 // When WriteTimeout is set in config, each write is limited to that duration.
 // On write error, the error handler is invoked and reconnect may be triggered if AutoReconnect is enabled.
@@ -280,17 +693,33 @@ func (c *EventDrivenTCPClient) Close() error {
 //   - data: Bytes to send; not modified
 //
 // Returns:
-//   - nil on success; an error if not connected, connection is nil, or the write fails.
+//   - nil on success; an error if not connected, connection is nil, the client is closing,
+//     or the write fails.
 func (c *EventDrivenTCPClient) Send(data []byte) error {
+	if !c.beginWrite() {
+		return fmt.Errorf("client is closing")
+	}
+	defer c.endWrite()
+
+	return c.write(data)
+}
+
+// write performs a single write on the current connection.
+func (c *EventDrivenTCPClient) write(data []byte) error {
 	c.mu.RLock()
 	conn := c.conn
 	state := c.state
+	upgrading := c.upgrading
 	c.mu.RUnlock()
 
 	if state != Connected {
 		return fmt.Errorf("not connected")
 	}
 
+	if upgrading {
+		return errUpgrading
+	}
+
 	if conn == nil {
 		return fmt.Errorf("connection is nil")
 	}
@@ -305,7 +734,9 @@ func (c *EventDrivenTCPClient) Send(data []byte) error {
 		}()
 	}
 
-	_, err := conn.Write(data)
+	n, err := conn.Write(data)
+	c.stats.bytesSent.Add(uint64(n))
+	c.logDebug("sent data", logger.Field{Key: "bytes", Value: n})
 	if err != nil {
 		c.emitError(err)
 		c.triggerReconnect()
@@ -314,183 +745,1050 @@ func (c *EventDrivenTCPClient) Send(data []byte) error {
 	return err
 }
 
-// GetState returns the current connection state.
+// SendMessage writes a single message to the connection. When DataLengthBasedRead is
+// enabled, data is prefixed with its length (encoded per LengthPrefixBytes and
+// BigEndianLength) and the whole frame is written with a single write. As in the read
+// loop, the length counts the entire frame including the prefix itself. When
+// DataLengthBasedRead is disabled, it behaves identically to Send.
+//
+// Parameters:
+//   - data: Message payload; not modified
 //
 // Returns:
-//   - The current ConnectionState (Disconnected, Connecting, Connected, Reconnecting, or Closed).
-func (c *EventDrivenTCPClient) GetState() ConnectionState {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.state
-}
+//   - nil on success; an error if the frame exceeds the 16 MiB limit (or what the prefix
+//     can encode), if the config is invalid, if not connected, or if the write fails.
+func (c *EventDrivenTCPClient) SendMessage(data []byte) error {
+	if !c.config.DataLengthBasedRead {
+		return c.Send(data)
+	}
 
-// IsConnected returns true if the client is in Connected state.
-func (c *EventDrivenTCPClient) IsConnected() bool {
-	return c.GetState() == Connected
+	if err := c.config.validate(); err != nil {
+		return err
+	}
+
+	prefixSize := c.lengthPrefixSize()
+	frameLength := prefixSize + len(data)
+	if limit := c.maxFrameLength(); frameLength > limit {
+		return fmt.Errorf("message frame size %d exceeds maximum frame size %d", frameLength, limit)
+	}
+
+	frame := make([]byte, frameLength)
+	c.encodeLength(frame[:prefixSize], uint64(frameLength))
+	copy(frame[prefixSize:], data)
+
+	return c.Send(frame)
 }
 
-func (c *EventDrivenTCPClient) connect() error {
-	c.setState(Connecting, nil)
+// SendWithAck queues data for delivery and returns immediately. A single write
+// goroutine drains the queue in FIFO order and calls ack once each write has
+// completed, with nil on success or the write error otherwise. Acks are therefore
+// invoked sequentially and in the same order the messages were queued.
+// If the client is closed before the message is written, ack receives an error.
+// SendWithAck blocks while the queue (Config.SendQueueSize) is full. Messages queued
+// before CloseGracefully or DisconnectGraceful is called are written before the
+// connection closes.
+//
+// Parameters:
+//   - data: Bytes to send; must not be modified until ack is called
+//   - ack: Function called with the outcome of the write; may be nil
+func (c *EventDrivenTCPClient) SendWithAck(data []byte, ack AckFunc) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		if ack != nil {
+			ack(fmt.Errorf("client is closed"))
+		}
+		return
+	}
 
-	dialer := net.Dialer{
-		Timeout: c.config.ConnectionTimeout,
+	if c.closing {
+		c.mu.Unlock()
+		if ack != nil {
+			ack(fmt.Errorf("client is closing"))
+		}
+		return
 	}
+	c.pendingWrites++
 
-	conn, err := dialer.Dial("tcp", c.config.Address)
-	if err != nil {
-		c.setState(Disconnected, err)
-		c.emitError(err)
-		return err
+	c.writerOnce.Do(func() {
+		c.wg.Add(1)
+		go c.writeLoop()
+	})
+	c.mu.Unlock()
+
+	select {
+	case c.sendQueue <- outboundMessage{data: data, ack: ack}:
+	case <-c.stopChan:
+		c.endWrite()
+		if ack != nil {
+			ack(fmt.Errorf("client is closed"))
+		}
+	}
+}
+
+// SendRequest sends data with SendMessage and waits for the response carrying the same
+// correlation ID, as reported by Config.CorrelationIDFunc. The response is the inbound
+// message exactly as it would be delivered in a DataReceivedEvent, and it is not passed to
+// the OnDataReceived handler. Only one request per correlation ID may be pending at a time.
+// The waiter is removed when the response arrives, ctx is done, or the send fails.
+//
+// Parameters:
+//   - ctx: Context bounding how long to wait for the response
+//   - correlationID: The ID the response is expected to carry; data must already contain it
+//   - data: Message payload passed to SendMessage; not modified
+//
+// Returns:
+//   - The response message on success; ctx.Err() if ctx is done first; otherwise an error
+//     (e.g. no CorrelationIDFunc configured, duplicate pending ID, send failure, or "client is closed").
+func (c *EventDrivenTCPClient) SendRequest(ctx context.Context, correlationID uint32, data []byte) ([]byte, error) {
+	if c.config.CorrelationIDFunc == nil {
+		return nil, fmt.Errorf("no correlation ID function configured")
 	}
 
+	response := make(chan []byte, 1)
+
 	c.mu.Lock()
-	c.conn = conn
+	if _, exists := c.pendingRequests[correlationID]; exists {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("request with correlation ID %d already pending", correlationID)
+	}
+	c.pendingRequests[correlationID] = response
 	c.mu.Unlock()
 
-	c.setState(Connected, nil)
-
-	c.wg.Add(1)
-	go c.readLoop()
+	// resolveRequest removes the waiter when the response arrives, after which a new
+	// request may register the same ID, so only remove the entry if it is still ours
+	defer func() {
+		c.mu.Lock()
+		if c.pendingRequests[correlationID] == response {
+			delete(c.pendingRequests, correlationID)
+		}
+		c.mu.Unlock()
+	}()
 
-	if c.config.AutoReconnect {
-		c.wg.Add(1)
-		go c.reconnectHandler()
+	if err := c.SendMessage(data); err != nil {
+		return nil, err
 	}
 
-	return nil
+	select {
+	case resp := <-response:
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.stopChan:
+		return nil, fmt.Errorf("client is closed")
+	}
 }
 
-func (c *EventDrivenTCPClient) readLoop() {
-	defer c.wg.Done()
+// resolveRequest hands data to the pending request matching its correlation ID.
+// It returns true if a waiter consumed the data.
+func (c *EventDrivenTCPClient) resolveRequest(data []byte) bool {
+	if c.config.CorrelationIDFunc == nil {
+		return false
+	}
 
-	if c.config.DataLengthBasedRead {
-		for {
-			c.mu.RLock()
-			conn := c.conn
-			closed := c.closed
-			c.mu.RUnlock()
+	id, ok := c.config.CorrelationIDFunc(data)
+	if !ok {
+		return false
+	}
 
-			if conn == nil || closed {
-				return
-			}
+	c.mu.Lock()
+	response, exists := c.pendingRequests[id]
+	if exists {
+		delete(c.pendingRequests, id)
+	}
+	c.mu.Unlock()
 
-			if c.config.ReadTimeout > 0 {
-				if err := conn.SetReadDeadline(time.Now().Add(c.config.ReadTimeout)); err != nil {
-					if !c.isClosed() {
-						c.emitError(err)
-						c.triggerReconnect()
-					}
-					break
-				}
-			} else {
-				if err := conn.SetReadDeadline(time.Time{}); err != nil {
-					if !c.isClosed() {
-						c.emitError(err)
-						c.triggerReconnect()
-					}
-					break
-				}
-			}
+	if !exists {
+		return false
+	}
 
-			var buf bytes.Buffer
-			if _, err := io.CopyN(&buf, conn, 4); err != nil {
-				if !c.isClosed() {
-					c.emitError(err)
-					c.triggerReconnect()
-				}
+	response <- data
+	return true
+}
 
-				break
-			}
+// Pause stops consuming data from the connection without tearing it down. The read loop
+// finishes the message it is currently reading, holds it back, and blocks until Resume is
+// called, so no data events are emitted while paused. No errors or reconnects are triggered and the state stays Connected. While
+// paused, incoming data accumulates in the OS socket buffers until they fill, at which
+// point TCP flow control makes the peer stop sending. Pausing persists across reconnects
+// until Resume is called. Disconnect and Close unblock a paused read loop.
+func (c *EventDrivenTCPClient) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-			if c.isClosed() {
-				return
-			}
+	if c.resumeChan == nil {
+		c.resumeChan = make(chan struct{})
+	}
+}
 
-			reader := io.MultiReader(&buf, conn)
-			dataLength := binary.LittleEndian.Uint32(buf.Bytes())
-			if dataLength == 0 {
-				continue
-			}
+// Resume continues reading after Pause. It is a no-op when the client is not paused.
+func (c *EventDrivenTCPClient) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-			if dataLength > 16*1024*1024 {
-				break
-			}
+	if c.resumeChan != nil {
+		close(c.resumeChan)
+		c.resumeChan = nil
+	}
+}
 
-			packet := make([]byte, dataLength)
-			if _, err := io.ReadFull(reader, packet); err != nil {
-				if !c.isClosed() {
-					c.emitError(err)
-					c.triggerReconnect()
-				}
+// IsPaused reports whether reading is currently paused with Pause.
+func (c *EventDrivenTCPClient) IsPaused() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.resumeChan != nil
+}
 
-				break
-			}
+// wakePausedReaders wakes read loops blocked by Pause so they can notice that their
+// connection is gone, while keeping the client paused. Callers must hold mu.
+func (c *EventDrivenTCPClient) wakePausedReaders() {
+	if c.resumeChan != nil {
+		close(c.resumeChan)
+		c.resumeChan = make(chan struct{})
+	}
+}
 
-			if c.isClosed() {
+// waitWhilePaused blocks while reading is paused. It returns false when the read loop
+// for conn should exit instead, because the client was closed, conn was replaced, or
+// UpgradeToTLS is about to replace it.
+func (c *EventDrivenTCPClient) waitWhilePaused(conn net.Conn) bool {
+	for {
+		c.mu.RLock()
+		resume := c.resumeChan
+		current := c.conn
+		closed := c.closed
+		upgrading := c.upgrading
+		c.mu.RUnlock()
+
+		if closed || upgrading || current != conn {
+			return false
+		}
+
+		if resume == nil {
+			return true
+		}
+
+		select {
+		case <-resume:
+		case <-c.stopChan:
+			return false
+		}
+	}
+}
+
+// GetState returns the current connection state.
+//
+// Returns:
+//   - The current ConnectionState (Disconnected, Connecting, Connected, Reconnecting, or Closed).
+func (c *EventDrivenTCPClient) GetState() ConnectionState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// IsConnected returns true if the client is in Connected state.
+func (c *EventDrivenTCPClient) IsConnected() bool {
+	return c.GetState() == Connected
+}
+
+// Stats returns a snapshot of the traffic counters. The counters are cumulative across
+// reconnects. Each field is loaded atomically, but fields are read one after another, so
+// the snapshot is not transactionally consistent across fields.
+//
+// Returns:
+//   - A Stats value with BytesSent, BytesReceived, MessagesReceived, and ReconnectCount.
+func (c *EventDrivenTCPClient) Stats() Stats {
+	return Stats{
+		BytesSent:        c.stats.bytesSent.Load(),
+		BytesReceived:    c.stats.bytesReceived.Load(),
+		MessagesReceived: c.stats.messagesReceived.Load(),
+		ReconnectCount:   c.stats.reconnectCount.Load(),
+	}
+}
+
+// ResetStats sets all traffic counters back to zero.
+func (c *EventDrivenTCPClient) ResetStats() {
+	c.stats.bytesSent.Store(0)
+	c.stats.bytesReceived.Store(0)
+	c.stats.messagesReceived.Store(0)
+	c.stats.reconnectCount.Store(0)
+}
+
+// DrainReceived returns the complete messages that were read from the connection but not
+// delivered to handlers because the client was closed, and forgets them. Call it after
+// Close returns, which waits for the read loop to stash what it holds.
+//
+// It is best-effort and only meaningful with DataLengthBasedRead or DelimiterBasedRead:
+// it covers a message the read loop had finished reading, for example while paused, and
+// in delimiter mode further complete messages already buffered by the client. Data still
+// in the OS socket buffers when the connection is closed is not read. In stream mode it
+// always returns nil.
+//
+// Returns:
+//   - The undelivered messages in the order they were received, or nil if there are none.
+func (c *EventDrivenTCPClient) DrainReceived() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages := c.undelivered
+	c.undelivered = nil
+	return messages
+}
+
+func (c *EventDrivenTCPClient) connect(ctx context.Context) error {
+	c.mu.RLock()
+	attempt := c.reconnectAttempt
+	c.mu.RUnlock()
+
+	startedAt := time.Now()
+	c.setStateWithEvent(ConnectionStateEvent{
+		State:            Connecting,
+		AttemptNumber:    attempt,
+		AttemptStartedAt: startedAt,
+	})
+
+	dialer := net.Dialer{
+		Timeout: c.config.ConnectionTimeout,
+	}
+
+	conn, err := dialer.DialContext(ctx, c.network(), c.config.Address)
+	dialDuration := time.Since(startedAt)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		c.setStateWithEvent(ConnectionStateEvent{
+			State:            Disconnected,
+			Error:            err,
+			AttemptNumber:    attempt,
+			AttemptStartedAt: startedAt,
+			DialDuration:     dialDuration,
+		})
+		c.emitError(err)
+		return err
+	}
+
+	if err := c.applySocketOptions(conn); err != nil {
+		_ = conn.Close()
+		c.setStateWithEvent(ConnectionStateEvent{
+			State:            Disconnected,
+			Error:            err,
+			AttemptNumber:    attempt,
+			AttemptStartedAt: startedAt,
+			DialDuration:     dialDuration,
+		})
+		c.emitError(err)
+		return err
+	}
+
+	if c.config.TLSConfig != nil {
+		tlsConn, err := c.handshakeTLS(ctx, conn, c.config.TLSConfig)
+		dialDuration = time.Since(startedAt)
+		if err != nil {
+			_ = conn.Close()
+			c.setStateWithEvent(ConnectionStateEvent{
+				State:            Disconnected,
+				Error:            err,
+				AttemptNumber:    attempt,
+				AttemptStartedAt: startedAt,
+				DialDuration:     dialDuration,
+			})
+			c.emitError(err)
+			return err
+		}
+		conn = tlsConn
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.reconnectAttempt = 0
+	startReconnectLoop := c.config.AutoReconnect && !c.reconnectLoopStarted
+	c.reconnectLoopStarted = c.reconnectLoopStarted || startReconnectLoop
+	c.mu.Unlock()
+
+	c.setStateWithEvent(ConnectionStateEvent{
+		State:            Connected,
+		AttemptNumber:    attempt,
+		AttemptStartedAt: startedAt,
+		DialDuration:     dialDuration,
+	})
+
+	c.startReadLoop()
+
+	if c.config.HeartbeatInterval > 0 {
+		c.startHeartbeat()
+	}
+
+	if startReconnectLoop {
+		c.wg.Add(1)
+		go c.reconnectHandler()
+	}
+
+	return nil
+}
+
+// startHeartbeat starts a heartbeat goroutine for the current connection,
+// replacing any previous one.
+func (c *EventDrivenTCPClient) startHeartbeat() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.stopHeartbeat()
+	stop := make(chan struct{})
+	c.heartbeatStop = stop
+
+	c.wg.Add(1)
+	go c.heartbeatLoop(stop)
+}
+
+// stopHeartbeat stops the running heartbeat goroutine, if any. Callers must hold mu.
+func (c *EventDrivenTCPClient) stopHeartbeat() {
+	if c.heartbeatStop != nil {
+		close(c.heartbeatStop)
+		c.heartbeatStop = nil
+	}
+}
+
+// heartbeatLoop sends HeartbeatPayload every HeartbeatInterval until stop or stopChan
+// is closed. Write failures go through Send, which reports them and triggers a reconnect.
+func (c *EventDrivenTCPClient) heartbeatLoop(stop <-chan struct{}) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+			if c.GetState() != Connected {
+				continue
+			}
+			_ = c.Send(c.config.HeartbeatPayload)
+		}
+	}
+}
+
+// handshakeTLS runs a client TLS handshake over conn using tlsConfig, bounded by ctx
+// and ConnectionTimeout.
+func (c *EventDrivenTCPClient) handshakeTLS(ctx context.Context, conn net.Conn, tlsConfig *tls.Config) (*tls.Conn, error) {
+	config := tlsConfig.Clone()
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(c.config.Address)
+		if err != nil {
+			host = c.config.Address
+		}
+		config.ServerName = host
+	}
+
+	if c.config.ConnectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.ConnectionTimeout)
+		defer cancel()
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("tls handshake failed: %w", err)
+	}
+
+	return tlsConn, nil
+}
+
+// NegotiatedProtocol returns the application protocol selected by the server
+// through ALPN on the current connection, for example to choose a framing mode
+// after Connect.
+//
+// Returns:
+//   - The negotiated protocol; empty when not connected, when TLSConfig is unset,
+//     or when the server selected no protocol
+func (c *EventDrivenTCPClient) NegotiatedProtocol() string {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return ""
+	}
+
+	return tlsConn.ConnectionState().NegotiatedProtocol
+}
+
+// UpgradeToTLS switches the current plaintext connection to TLS in-band, as protocols
+// with a STARTTLS command do: send the command, wait for the server's go-ahead, then call
+// UpgradeToTLS. It stops the read loop, runs a client handshake over the existing
+// connection, and resumes reading on the encrypted connection. The state stays Connected
+// throughout.
+//
+// Sends fail with an upgrade error while the upgrade runs. Writes already under way when
+// it starts are waited for before the handshake, so no plaintext reaches the socket once
+// it has begun; together with the handshake, this wait is bounded by ConnectionTimeout.
+// SendWithAck messages still queued when the upgrade starts are not sent: their AckFunc
+// receives the upgrade error, so wait for the acks of anything that must go out in
+// plaintext before calling UpgradeToTLS. It must not be called from an AckFunc, which
+// would block the queue it waits for. Plaintext that was read but not yet delivered when
+// the upgrade starts is discarded rather than trusted. When tlsConfig has no ServerName, the host
+// from Config.Address is used. A failed handshake leaves the connection unusable, so it
+// is closed and reported like a read error, triggering a reconnect with AutoReconnect.
+// Connections made by a reconnect are plaintext again; repeat the exchange on Connected.
+//
+// Parameters:
+//   - tlsConfig: TLS settings for the handshake; not modified
+//
+// Returns:
+//   - nil once reading has resumed over TLS; an error if tlsConfig is nil, the client is
+//     not connected, the connection already uses TLS, another upgrade is in progress, or
+//     the handshake fails.
+func (c *EventDrivenTCPClient) UpgradeToTLS(tlsConfig *tls.Config) error {
+	if tlsConfig == nil {
+		return fmt.Errorf("tls config is nil")
+	}
+
+	c.mu.Lock()
+	conn := c.conn
+	if c.state != Connected || conn == nil {
+		c.mu.Unlock()
+		return fmt.Errorf("not connected")
+	}
+	if c.upgrading {
+		c.mu.Unlock()
+		return errUpgrading
+	}
+	if _, ok := conn.(*tls.Conn); ok {
+		c.mu.Unlock()
+		return fmt.Errorf("connection already uses tls")
+	}
+	c.upgrading = true
+	readDone := c.readDone
+	c.wakePausedReaders()
+	c.mu.Unlock()
+
+	ctx := context.Background()
+	if c.config.ConnectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.ConnectionTimeout)
+		defer cancel()
+	}
+
+	// Writes that checked upgrading just before it was set may still be putting
+	// plaintext on the socket, which would corrupt the handshake
+	err := c.waitForWrites(ctx)
+	if err != nil {
+		err = fmt.Errorf("waiting for pending writes: %w", err)
+	}
+
+	// An expired deadline wakes the read loop from a blocked read; it sees upgrading and
+	// exits quietly. The deadline is cleared again before the handshake.
+	if err == nil {
+		err = conn.SetReadDeadline(time.Now())
+	}
+	if err == nil && readDone != nil {
+		<-readDone
+	}
+	if err == nil {
+		err = conn.SetReadDeadline(time.Time{})
+	}
+
+	var tlsConn *tls.Conn
+	if err == nil {
+		tlsConn, err = c.handshakeTLS(ctx, conn, tlsConfig)
+	}
+
+	c.mu.Lock()
+	c.upgrading = false
+	replaced := c.closed || c.conn != conn
+	if err == nil && !replaced {
+		c.conn = tlsConn
+	}
+	c.mu.Unlock()
+
+	if replaced {
+		return fmt.Errorf("connection closed during tls upgrade")
+	}
+
+	if err != nil {
+		_ = c.disconnect()
+		c.emitError(err)
+		c.triggerReconnect()
+		return err
+	}
+
+	c.startReadLoop()
+	c.logDebug("upgraded connection to tls", logger.Field{Key: "address", Value: c.config.Address})
+	return nil
+}
+
+// network returns the configured dial network, defaulting to "tcp".
+func (c *EventDrivenTCPClient) network() string {
+	if c.config.Network == "" {
+		return "tcp"
+	}
+
+	return c.config.Network
+}
+
+// applySocketOptions applies the configured keep-alive and socket buffer settings to a
+// freshly dialed connection. Both only apply to TCP connections.
+func (c *EventDrivenTCPClient) applySocketOptions(conn net.Conn) error {
+	if err := c.applyKeepAlive(conn); err != nil {
+		return err
+	}
+
+	return c.applySocketBuffers(conn)
+}
+
+// applyKeepAlive enables TCP keep-alive on conn when KeepAlive is configured.
+// Connections that are not *net.TCPConn are left untouched.
+func (c *EventDrivenTCPClient) applyKeepAlive(conn net.Conn) error {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if c.config.KeepAlive <= 0 || !ok {
+		return nil
+	}
+
+	if err := tcpConn.SetKeepAlive(true); err != nil {
+		return fmt.Errorf("failed to enable keep-alive: %w", err)
+	}
+	if err := tcpConn.SetKeepAlivePeriod(c.config.KeepAlive); err != nil {
+		return fmt.Errorf("failed to set keep-alive period: %w", err)
+	}
+
+	return nil
+}
+
+// applySocketBuffers sets the kernel socket buffer sizes on conn when configured.
+// Connections that are not *net.TCPConn are left untouched.
+func (c *EventDrivenTCPClient) applySocketBuffers(conn net.Conn) error {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	if size := c.config.SocketReadBufferSize; size > 0 {
+		if err := tcpConn.SetReadBuffer(size); err != nil {
+			return fmt.Errorf("failed to set socket read buffer: %w", err)
+		}
+	}
+	if size := c.config.SocketWriteBufferSize; size > 0 {
+		if err := tcpConn.SetWriteBuffer(size); err != nil {
+			return fmt.Errorf("failed to set socket write buffer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// startReadLoop starts the read loop for the current connection.
+func (c *EventDrivenTCPClient) startReadLoop() {
+	done := make(chan struct{})
+	c.mu.Lock()
+	c.readDone = done
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer close(done)
+		c.readLoop()
+	}()
+}
+
+// armReadDeadline sets the deadline for the next read on conn from ReadTimeout. Once
+// UpgradeToTLS has started it returns errUpgrading instead, so that the deadline the
+// upgrade uses to interrupt reading is not overwritten.
+func (c *EventDrivenTCPClient) armReadDeadline(conn net.Conn) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.upgrading {
+		return errUpgrading
+	}
+
+	deadline := time.Time{}
+	if c.config.ReadTimeout > 0 {
+		deadline = time.Now().Add(c.config.ReadTimeout)
+	}
+
+	return conn.SetReadDeadline(deadline)
+}
+
+// readStopped reports whether a read error is expected because the client was closed
+// or UpgradeToTLS interrupted reading, so the read loop exits without reporting it.
+func (c *EventDrivenTCPClient) readStopped() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closed || c.upgrading
+}
+
+func (c *EventDrivenTCPClient) readLoop() {
+	defer c.wg.Done()
+
+	if c.config.DelimiterBasedRead {
+		c.readDelimited()
+		return
+	}
+
+	if c.config.DataLengthBasedRead {
+		for {
+			c.mu.RLock()
+			conn := c.conn
+			closed := c.closed
+			c.mu.RUnlock()
+
+			if conn == nil || closed || !c.waitWhilePaused(conn) {
 				return
 			}
 
-			c.emitDataReceived(packet)
+			if err := c.armReadDeadline(conn); err != nil {
+				if !c.readStopped() {
+					c.emitError(err)
+					c.triggerReconnect()
+				}
+				break
+			}
+
+			var buf bytes.Buffer
+			n, err := io.CopyN(&buf, conn, int64(c.lengthPrefixSize()))
+			c.stats.bytesReceived.Add(uint64(n))
+			if err != nil {
+				if !c.readStopped() {
+					c.emitError(err)
+					c.triggerReconnect()
+				}
+
+				break
+			}
+
+			if c.isClosed() {
+				return
+			}
+
+			reader := io.MultiReader(&buf, conn)
+			dataLength := c.decodeLength(buf.Bytes())
+			if dataLength == 0 {
+				continue
+			}
+
+			if dataLength > maxMessageSize {
+				break
+			}
+
+			packet := make([]byte, dataLength)
+			buffered := buf.Len() // prefix bytes, already counted above
+			read, err := io.ReadFull(reader, packet)
+			if read > buffered {
+				c.stats.bytesReceived.Add(uint64(read - buffered))
+			}
+			if err != nil {
+				if !c.readStopped() {
+					c.emitError(err)
+					c.triggerReconnect()
+				}
+
+				break
+			}
+
+			if !c.waitWhilePaused(conn) {
+				c.stashUndelivered(packet)
+				return
+			}
+
+			c.emitDataReceived(packet)
+		}
+
+		return
+	}
+
+	buffer := make([]byte, c.config.ReadBufferSize)
+	for {
+		c.mu.RLock()
+		conn := c.conn
+		closed := c.closed
+		c.mu.RUnlock()
+
+		if conn == nil || closed || !c.waitWhilePaused(conn) {
+			return
+		}
+
+		if err := c.armReadDeadline(conn); err != nil {
+			if !c.readStopped() {
+				c.emitError(err)
+				c.triggerReconnect()
+			}
+			return
+		}
+
+		n, err := conn.Read(buffer)
+		c.stats.bytesReceived.Add(uint64(n))
+
+		if c.isClosed() {
+			return
+		}
+
+		if err != nil {
+			if !c.readStopped() {
+				c.emitError(err)
+				c.triggerReconnect()
+			}
+
+			return
+		}
+
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buffer[:n])
+			if !c.waitWhilePaused(conn) {
+				return
+			}
+			c.emitDataReceived(data)
 		}
+	}
+}
+
+// readDelimited reads messages terminated by Config.Delimiter from the current connection
+// and emits one data event per message. The buffered reader lives for the whole connection,
+// so messages split across several reads are reassembled, and bytes after a delimiter are
+// kept for the next message.
+func (c *EventDrivenTCPClient) readDelimited() {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
 
+	if conn == nil {
 		return
 	}
 
-	buffer := make([]byte, c.config.ReadBufferSize)
+	maxLength := c.config.MaxLineLength
+	if maxLength <= 0 {
+		maxLength = maxMessageSize
+	}
+
+	bufferSize := c.config.ReadBufferSize
+	if bufferSize <= 0 {
+		bufferSize = 4096
+	}
+	reader := bufio.NewReaderSize(conn, bufferSize)
+
 	for {
 		c.mu.RLock()
-		conn := c.conn
 		closed := c.closed
+		current := c.conn
 		c.mu.RUnlock()
 
-		if conn == nil || closed {
+		if closed || current != conn || !c.waitWhilePaused(conn) {
+			c.stashBufferedLines(reader, nil)
 			return
 		}
 
-		if c.config.ReadTimeout > 0 {
-			if err := conn.SetReadDeadline(time.Now().Add(c.config.ReadTimeout)); err != nil {
-				if !c.isClosed() {
-					c.emitError(err)
-					c.triggerReconnect()
-				}
-				return
-			}
-		} else {
-			if err := conn.SetReadDeadline(time.Time{}); err != nil {
-				if !c.isClosed() {
-					c.emitError(err)
-					c.triggerReconnect()
-				}
-				return
+		if err := c.armReadDeadline(conn); err != nil {
+			if !c.readStopped() {
+				c.emitError(err)
+				c.triggerReconnect()
 			}
+			return
 		}
 
-		n, err := conn.Read(buffer)
+		line, err := c.readLine(reader, maxLength)
+		c.stats.bytesReceived.Add(uint64(len(line)))
 
 		if c.isClosed() {
+			if err == nil {
+				c.stashBufferedLines(reader, line)
+			}
 			return
 		}
 
 		if err != nil {
-			if !c.isClosed() {
+			if !c.readStopped() {
 				c.emitError(err)
 				c.triggerReconnect()
 			}
+			return
+		}
 
+		if !c.waitWhilePaused(conn) {
+			c.stashBufferedLines(reader, line)
 			return
 		}
 
-		if n > 0 {
-			data := make([]byte, n)
-			copy(data, buffer[:n])
-			c.emitDataReceived(data)
+		c.emitDataReceived(c.trimDelimiter(line))
+	}
+}
+
+// trimDelimiter removes the trailing delimiter from line unless Config.KeepDelimiter is set.
+func (c *EventDrivenTCPClient) trimDelimiter(line []byte) []byte {
+	if c.config.KeepDelimiter {
+		return line
+	}
+	return line[:len(line)-1]
+}
+
+// stashBufferedLines stashes line, if not nil, followed by every complete message left
+// in reader's buffer, when the client has been closed. It never reads from the connection.
+func (c *EventDrivenTCPClient) stashBufferedLines(reader *bufio.Reader, line []byte) {
+	if !c.isClosed() {
+		return
+	}
+
+	var messages [][]byte
+	if line != nil {
+		messages = append(messages, c.trimDelimiter(line))
+	}
+
+	buffered, _ := reader.Peek(reader.Buffered())
+	for {
+		i := bytes.IndexByte(buffered, c.config.Delimiter)
+		if i < 0 {
+			break
+		}
+		messages = append(messages, c.trimDelimiter(bytes.Clone(buffered[:i+1])))
+		buffered = buffered[i+1:]
+	}
+
+	c.stashUndelivered(messages...)
+}
+
+// stashUndelivered keeps messages for DrainReceived if the client has been closed, and
+// drops them otherwise, as when a connection is replaced.
+func (c *EventDrivenTCPClient) stashUndelivered(messages ...[]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		c.undelivered = append(c.undelivered, messages...)
+	}
+}
+
+// readLine reads up to and including the next delimiter without buffering more than
+// maxLength bytes of message content. The returned slice is owned by the caller.
+func (c *EventDrivenTCPClient) readLine(reader *bufio.Reader, maxLength int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice(c.config.Delimiter)
+		if len(line)+len(chunk) > maxLength+1 || (err == bufio.ErrBufferFull && len(line)+len(chunk) > maxLength) {
+			return line, fmt.Errorf("message exceeds maximum line length %d", maxLength)
+		}
+
+		line = append(line, chunk...)
+		if err == nil {
+			return line, nil
+		}
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+// lengthPrefixSize returns the configured length prefix size, defaulting to 4 bytes.
+func (c *EventDrivenTCPClient) lengthPrefixSize() int {
+	if c.config.LengthPrefixBytes == 0 {
+		return 4
+	}
+
+	return c.config.LengthPrefixBytes
+}
+
+// maxFrameLength returns the largest frame that can be sent with the configured prefix.
+func (c *EventDrivenTCPClient) maxFrameLength() int {
+	if c.lengthPrefixSize() == 2 {
+		return 0xFFFF
+	}
+
+	return maxMessageSize
+}
+
+func (c *EventDrivenTCPClient) byteOrder() binary.ByteOrder {
+	if c.config.BigEndianLength {
+		return binary.BigEndian
+	}
+
+	return binary.LittleEndian
+}
+
+// decodeLength decodes a length prefix of the configured size and endianness.
+func (c *EventDrivenTCPClient) decodeLength(prefix []byte) uint64 {
+	order := c.byteOrder()
+	switch len(prefix) {
+	case 2:
+		return uint64(order.Uint16(prefix))
+	case 8:
+		return order.Uint64(prefix)
+	default:
+		return uint64(order.Uint32(prefix))
+	}
+}
+
+// encodeLength writes length into prefix using the configured size and endianness.
+func (c *EventDrivenTCPClient) encodeLength(prefix []byte, length uint64) {
+	order := c.byteOrder()
+	switch len(prefix) {
+	case 2:
+		order.PutUint16(prefix, uint16(length))
+	case 8:
+		order.PutUint64(prefix, length)
+	default:
+		order.PutUint32(prefix, uint32(length))
+	}
+}
+
+func (c *EventDrivenTCPClient) writeLoop() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.stopChan:
+			c.failQueued()
+			return
+		case msg := <-c.sendQueue:
+			err := c.write(msg.data)
+			c.endWrite()
+			if msg.ack != nil {
+				msg.ack(err)
+			}
+		}
+	}
+}
+
+// failQueued acks every queued message with an error once the client is closed. A
+// SendWithAck call that passed the closed check before Close may still be enqueueing,
+// so it keeps draining until no writes are pending rather than stopping at an empty queue.
+func (c *EventDrivenTCPClient) failQueued() {
+	for {
+		c.mu.Lock()
+		if c.pendingWrites == 0 {
+			c.mu.Unlock()
+			return
+		}
+		drained := make(chan struct{})
+		c.drainWaiters = append(c.drainWaiters, drained)
+		c.mu.Unlock()
+
+		select {
+		case msg := <-c.sendQueue:
+			c.endWrite()
+			if msg.ack != nil {
+				msg.ack(fmt.Errorf("client is closed"))
+			}
+		case <-drained:
+			return
 		}
 	}
 }
 
+// reconnectHandler runs the reconnect cycle: on each trigger it asks the reconnect
+// policy for a delay, waits, and connects, retriggering itself after a failed attempt
+// until the policy gives up.
 func (c *EventDrivenTCPClient) reconnectHandler() {
 	defer c.wg.Done()
 
+	var lastErr error
 	for {
 		select {
 		case <-c.stopChan:
@@ -504,14 +1802,31 @@ func (c *EventDrivenTCPClient) reconnectHandler() {
 			c.reconnecting = true
 			c.mu.Unlock()
 
-			c.mu.Lock()
 			if err := c.disconnect(); err != nil {
 				c.emitError(err)
 			}
 
+			c.mu.Lock()
+			c.reconnectAttempt++
+			attempt := c.reconnectAttempt
 			c.mu.Unlock()
 
-			c.setState(Reconnecting, nil)
+			delay, giveUp := c.reconnectPolicy.NextDelay(attempt, lastErr)
+			if giveUp {
+				c.mu.Lock()
+				c.reconnecting = false
+				c.mu.Unlock()
+
+				c.abandonReconnect(attempt-1, lastErr)
+				lastErr = nil
+				continue
+			}
+
+			c.setStateWithEvent(ConnectionStateEvent{
+				State:         Reconnecting,
+				AttemptNumber: attempt,
+				NextDelay:     delay,
+			})
 
 			select {
 			case <-c.stopChan:
@@ -519,7 +1834,7 @@ func (c *EventDrivenTCPClient) reconnectHandler() {
 				c.reconnecting = false
 				c.mu.Unlock()
 				return
-			case <-time.After(c.config.ReconnectInterval):
+			case <-time.After(delay):
 			}
 
 			if c.isClosed() {
@@ -529,24 +1844,44 @@ func (c *EventDrivenTCPClient) reconnectHandler() {
 				return
 			}
 
-			err := c.connect()
+			err := c.connect(context.Background())
 
 			c.mu.Lock()
 			c.reconnecting = false
 			c.mu.Unlock()
 
-			if err != nil {
-				select {
-				case c.reconnectChan <- struct{}{}:
-				default:
-				}
+			if err == nil {
+				c.stats.reconnectCount.Add(1)
+				lastErr = nil
+				continue
+			}
+
+			lastErr = err
+			select {
+			case c.reconnectChan <- struct{}{}:
+			default:
 			}
 		}
 	}
 }
 
+// abandonReconnect stops the reconnect cycle after the last allowed attempt failed.
+// The attempt counter is reset so that a later Connect starts the cycle from scratch.
+func (c *EventDrivenTCPClient) abandonReconnect(attempts int, lastErr error) {
+	c.mu.Lock()
+	c.reconnectAttempt = 0
+	c.mu.Unlock()
+
+	err := fmt.Errorf("reconnection abandoned after %d attempts", attempts)
+	if lastErr != nil {
+		err = fmt.Errorf("%s: %w", err, lastErr)
+	}
+	c.setState(Disconnected, err)
+	c.emitError(err)
+}
+
 func (c *EventDrivenTCPClient) triggerReconnect() {
-	if !c.config.AutoReconnect || c.isClosed() {
+	if !c.config.AutoReconnect || c.isClosed() || c.isClosing() {
 		return
 	}
 
@@ -557,47 +1892,94 @@ func (c *EventDrivenTCPClient) triggerReconnect() {
 }
 
 func (c *EventDrivenTCPClient) setState(state ConnectionState, err error) {
+	c.setStateWithEvent(ConnectionStateEvent{State: state, Error: err})
+}
+
+// setStateWithEvent updates the state to event.State and emits event; Address
+// and Timestamp are filled in automatically.
+func (c *EventDrivenTCPClient) setStateWithEvent(event ConnectionStateEvent) {
 	c.mu.Lock()
-	c.state = state
+	c.state = event.State
 	c.mu.Unlock()
 
-	c.emitConnectionState(state, err)
+	c.emitConnectionState(event)
 }
 
-func (c *EventDrivenTCPClient) emitConnectionState(state ConnectionState, err error) {
+func (c *EventDrivenTCPClient) emitConnectionState(event ConnectionStateEvent) {
+	if c.config.Logger != nil {
+		fields := []logger.Field{
+			{Key: "state", Value: event.State.String()},
+			{Key: "address", Value: c.config.Address},
+		}
+		if event.Error != nil {
+			fields = append(fields, logger.Field{Key: "error", Value: event.Error.Error()})
+		}
+		c.config.Logger.Debug("connection state changed", fields...)
+	}
+
 	c.mu.RLock()
 	handler := c.onConnectionState
 	c.mu.RUnlock()
 
 	if handler != nil {
-		event := ConnectionStateEvent{
-			State:     state,
-			Address:   c.config.Address,
-			Timestamp: time.Now(),
-			Error:     err,
-		}
+		event.Address = c.config.Address
+		event.Timestamp = time.Now()
 
-		go handler(event)
+		c.dispatchNoWait(func() { handler(event) })
 	}
 }
 
 func (c *EventDrivenTCPClient) emitDataReceived(data []byte) {
+	c.stats.messagesReceived.Add(1)
+	c.logDebug("received data", logger.Field{Key: "bytes", Value: len(data)})
+
+	if c.resolveRequest(data) {
+		return
+	}
+
 	c.mu.RLock()
 	handler := c.onDataReceived
+	handlers := c.dataHandlers
 	c.mu.RUnlock()
 
-	if handler != nil {
-		event := DataReceivedEvent{
-			Data:      data,
-			Length:    len(data),
-			Timestamp: time.Now(),
-		}
+	if handler == nil && len(handlers) == 0 {
+		return
+	}
+
+	event := DataReceivedEvent{
+		Data:      data,
+		Length:    len(data),
+		Timestamp: time.Now(),
+	}
+
+	if c.config.SerialHandlers {
+		c.dispatch(func() {
+			if handler != nil {
+				handler(event)
+			}
+			for _, entry := range handlers {
+				entry.handler(event)
+			}
+		})
+		return
+	}
 
+	if handler != nil {
 		go handler(event)
 	}
+
+	for _, entry := range handlers {
+		go entry.handler(event)
+	}
 }
 
 func (c *EventDrivenTCPClient) emitError(err error) {
+	if c.config.Logger != nil {
+		c.config.Logger.Error("client error",
+			logger.Field{Key: "address", Value: c.config.Address},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+
 	c.mu.RLock()
 	handler := c.onError
 	c.mu.RUnlock()
@@ -608,8 +1990,162 @@ func (c *EventDrivenTCPClient) emitError(err error) {
 			Timestamp: time.Now(),
 		}
 
-		go handler(event)
+		c.dispatchNoWait(func() { handler(event) })
+	}
+}
+
+// logDebug writes a debug entry through Config.Logger when one is set.
+func (c *EventDrivenTCPClient) logDebug(msg string, fields ...logger.Field) {
+	if c.config.Logger != nil {
+		c.config.Logger.Debug(msg, fields...)
+	}
+}
+
+// dispatch runs fn, which invokes an event handler. By default each call gets its own
+// goroutine; with SerialHandlers, fn is queued for the event loop and dispatch blocks
+// while the queue is full or a backlog is pending, until Close starts. Events emitted after
+// Close has finished are dropped.
+func (c *EventDrivenTCPClient) dispatch(fn func()) {
+	if !c.config.SerialHandlers {
+		go fn()
+		return
 	}
+
+	c.dispatcherOnce.Do(func() {
+		go c.eventLoop()
+	})
+
+	for {
+		c.eventsMu.Lock()
+		drained := c.backlogDrained
+		waiting := len(c.backlog) > 0
+		c.eventsMu.Unlock()
+
+		// Once Close starts, a handler may be the one closing, so stop waiting for it
+		if !waiting {
+			select {
+			case c.events <- fn:
+			case <-c.stopChan:
+				c.dispatchNoWait(fn)
+			case <-c.eventsStop:
+			}
+			return
+		}
+
+		// Queue behind the backlog rather than ahead of it, to keep events in order
+		select {
+		case <-drained:
+		case <-c.stopChan:
+			c.dispatchNoWait(fn)
+			return
+		case <-c.eventsStop:
+			return
+		}
+	}
+}
+
+// dispatchNoWait is dispatch for events that can be emitted from a handler, such as
+// the state change from a Close or Disconnect call. The handler may be running on
+// the event loop, so rather than block on a full queue, fn goes to the backlog.
+func (c *EventDrivenTCPClient) dispatchNoWait(fn func()) {
+	if !c.config.SerialHandlers {
+		go fn()
+		return
+	}
+
+	c.dispatcherOnce.Do(func() {
+		go c.eventLoop()
+	})
+
+	c.eventsMu.Lock()
+	defer c.eventsMu.Unlock()
+
+	if c.eventsDone {
+		return
+	}
+
+	if len(c.backlog) == 0 {
+		select {
+		case c.events <- fn:
+			return
+		default:
+		}
+
+		c.backlogDrained = make(chan struct{})
+	}
+
+	c.backlog = append(c.backlog, fn)
+	select {
+	case c.eventsWake <- struct{}{}:
+	default:
+	}
+}
+
+// takeBacklog removes and returns the backlog, releasing dispatch calls waiting
+// behind it. When the backlog is empty and final is set, it marks the loop done.
+func (c *EventDrivenTCPClient) takeBacklog(final bool) []func() {
+	c.eventsMu.Lock()
+	defer c.eventsMu.Unlock()
+
+	backlog := c.backlog
+	if len(backlog) == 0 {
+		c.eventsDone = final
+		return nil
+	}
+
+	c.backlog = nil
+	close(c.backlogDrained)
+	return backlog
+}
+
+// eventLoop runs queued handler calls one at a time, in order, followed by the
+// backlog whenever the queue runs empty. Once the client is closed it runs
+// whatever is still queued, including the Closed event, and exits.
+func (c *EventDrivenTCPClient) eventLoop() {
+	for {
+		select {
+		case fn := <-c.events:
+			fn()
+			continue
+		default:
+		}
+
+		if backlog := c.takeBacklog(false); backlog != nil {
+			for _, fn := range backlog {
+				fn()
+			}
+			continue
+		}
+
+		select {
+		case fn := <-c.events:
+			fn()
+		case <-c.eventsWake:
+		case <-c.eventsStop:
+			for {
+				select {
+				case fn := <-c.events:
+					fn()
+					continue
+				default:
+				}
+
+				backlog := c.takeBacklog(true)
+				if backlog == nil {
+					return
+				}
+				for _, fn := range backlog {
+					fn()
+				}
+			}
+		}
+	}
+}
+
+func (c *EventDrivenTCPClient) isClosing() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closing
 }
 
 func (c *EventDrivenTCPClient) isClosed() bool {
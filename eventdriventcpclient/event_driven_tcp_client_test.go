@@ -0,0 +1,2172 @@
+package eventdriventcpclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyberinferno/go-utils/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer starts a loopback TCP listener that accepts a single connection
+// and forwards everything it reads to the returned channel.
+func startTestServer(t *testing.T) (net.Listener, <-chan []byte) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	received := make(chan []byte, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		buf := make([]byte, 4096)
+		for {
+			n, err := conn.Read(buf)
+			if n > 0 {
+				data := make([]byte, n)
+				copy(data, buf[:n])
+				received <- data
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return ln, received
+}
+
+func TestSendWithAck(t *testing.T) {
+	t.Run("ack receives nil after successful write", func(t *testing.T) {
+		ln, received := startTestServer(t)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		acks := make(chan error, 1)
+		client.SendWithAck([]byte("hello"), func(err error) { acks <- err })
+
+		select {
+		case err := <-acks:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("ack was not called")
+		}
+
+		select {
+		case data := <-received:
+			assert.Equal(t, []byte("hello"), data)
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not receive data")
+		}
+	})
+
+	t.Run("ack receives error when write fails", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig("127.0.0.1:0"))
+		defer func() { _ = client.Close() }()
+
+		acks := make(chan error, 1)
+		client.SendWithAck([]byte("hello"), func(err error) { acks <- err })
+
+		select {
+		case err := <-acks:
+			assert.Error(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("ack was not called")
+		}
+	})
+
+	t.Run("acks fire in queue order", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		order := make(chan int, 10)
+		for i := 0; i < 10; i++ {
+			client.SendWithAck([]byte{byte(i)}, func(err error) {
+				assert.NoError(t, err)
+				order <- i
+			})
+		}
+
+		for want := 0; want < 10; want++ {
+			select {
+			case got := <-order:
+				assert.Equal(t, want, got)
+			case <-time.After(2 * time.Second):
+				t.Fatal("ack was not called")
+			}
+		}
+	})
+
+	t.Run("ack receives error after close", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig("127.0.0.1:0"))
+		require.NoError(t, client.Close())
+
+		var ackErr error
+		client.SendWithAck([]byte("hello"), func(err error) { ackErr = err })
+		assert.Error(t, ackErr)
+	})
+
+	t.Run("ack enqueued while closing still fires", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig("127.0.0.1:0"))
+
+		// Start the write loop
+		started := make(chan error, 1)
+		client.SendWithAck([]byte("first"), func(err error) { started <- err })
+		<-started
+
+		// Simulate a SendWithAck that passed the closed check but has not enqueued yet
+		client.mu.Lock()
+		client.pendingWrites++
+		client.mu.Unlock()
+
+		closed := make(chan struct{})
+		go func() {
+			_ = client.Close()
+			close(closed)
+		}()
+		<-client.stopChan
+
+		acks := make(chan error, 1)
+		client.sendQueue <- outboundMessage{data: []byte("late"), ack: func(err error) { acks <- err }}
+
+		select {
+		case err := <-acks:
+			assert.ErrorContains(t, err, "client is closed")
+		case <-time.After(2 * time.Second):
+			t.Fatal("ack was not called")
+		}
+
+		select {
+		case <-closed:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Close did not return")
+		}
+	})
+}
+
+func TestConnectWithContext(t *testing.T) {
+	t.Run("connects with a live context", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+
+		require.NoError(t, client.ConnectWithContext(context.Background()))
+		assert.True(t, client.IsConnected())
+	})
+
+	t.Run("cancelled context aborts dial and leaves client disconnected", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := client.ConnectWithContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, Disconnected, client.GetState())
+	})
+}
+
+func TestDisconnect(t *testing.T) {
+	t.Run("closes a live connection", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		done := make(chan error, 1)
+		go func() { done <- client.Disconnect() }()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Disconnect did not return")
+		}
+		assert.Equal(t, Disconnected, client.GetState())
+	})
+
+	t.Run("auto reconnect survives repeated drops", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		accepted := make(chan struct{}, 16)
+		go func() {
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				accepted <- struct{}{}
+				time.Sleep(20 * time.Millisecond)
+				_ = conn.Close()
+			}
+		}()
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.AutoReconnect = true
+		cfg.ReconnectInterval = 10 * time.Millisecond
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		// The initial connection plus two reconnects
+		for i := 0; i < 3; i++ {
+			select {
+			case <-accepted:
+			case <-time.After(2 * time.Second):
+				t.Fatalf("connection %d was not made", i+1)
+			}
+		}
+	})
+}
+
+// readAll collects data from the server channel until want bytes have arrived.
+func readAll(t *testing.T, received <-chan []byte, want int) []byte {
+	t.Helper()
+
+	var got []byte
+	for len(got) < want {
+		select {
+		case data := <-received:
+			got = append(got, data...)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %d bytes, got %d", want, len(got))
+		}
+	}
+
+	return got
+}
+
+func TestSendMessage(t *testing.T) {
+	t.Run("prefixes length when length-based read is enabled", func(t *testing.T) {
+		ln, received := startTestServer(t)
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.DataLengthBasedRead = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		require.NoError(t, client.SendMessage([]byte("hello")))
+
+		got := readAll(t, received, 9)
+		assert.Equal(t, []byte{9, 0, 0, 0, 'h', 'e', 'l', 'l', 'o'}, got)
+	})
+
+	t.Run("writes raw bytes when length-based read is disabled", func(t *testing.T) {
+		ln, received := startTestServer(t)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		require.NoError(t, client.SendMessage([]byte("hello")))
+
+		got := readAll(t, received, 5)
+		assert.Equal(t, []byte("hello"), got)
+	})
+
+	t.Run("round-trips through the length-based read loop", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			_, _ = io.Copy(conn, conn)
+		}()
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.DataLengthBasedRead = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		messages := make(chan []byte, 1)
+		client.OnDataReceived(func(event DataReceivedEvent) { messages <- event.Data })
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.SendMessage([]byte("hello")))
+
+		select {
+		case msg := <-messages:
+			assert.Equal(t, []byte("hello"), msg[4:])
+		case <-time.After(2 * time.Second):
+			t.Fatal("message was not received")
+		}
+	})
+
+	t.Run("rejects payloads larger than the frame limit", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig("127.0.0.1:0")
+		cfg.DataLengthBasedRead = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		err := client.SendMessage(make([]byte, maxMessageSize-3))
+		assert.ErrorContains(t, err, "exceeds maximum frame size")
+	})
+}
+
+func TestLengthPrefixConfiguration(t *testing.T) {
+	t.Run("two-byte big-endian prefix is used for send and read", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		serverGot := make(chan []byte, 1)
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+
+			frame := make([]byte, 7)
+			if _, err := io.ReadFull(conn, frame); err != nil {
+				return
+			}
+			serverGot <- frame
+			_, _ = conn.Write([]byte{0, 4, 'o', 'k'})
+			time.Sleep(time.Second)
+		}()
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.DataLengthBasedRead = true
+		cfg.LengthPrefixBytes = 2
+		cfg.BigEndianLength = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		messages := make(chan []byte, 1)
+		client.OnDataReceived(func(event DataReceivedEvent) { messages <- event.Data })
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.SendMessage([]byte("hello")))
+
+		select {
+		case frame := <-serverGot:
+			assert.Equal(t, []byte{0, 7, 'h', 'e', 'l', 'l', 'o'}, frame)
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not receive frame")
+		}
+
+		select {
+		case msg := <-messages:
+			assert.Equal(t, []byte{0, 4, 'o', 'k'}, msg)
+		case <-time.After(2 * time.Second):
+			t.Fatal("client did not receive frame")
+		}
+	})
+
+	t.Run("eight-byte little-endian prefix encodes frame length", func(t *testing.T) {
+		ln, received := startTestServer(t)
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.DataLengthBasedRead = true
+		cfg.LengthPrefixBytes = 8
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.SendMessage([]byte("hi")))
+
+		got := readAll(t, received, 10)
+		assert.Equal(t, []byte{10, 0, 0, 0, 0, 0, 0, 0, 'h', 'i'}, got)
+	})
+
+	t.Run("unsupported prefix size fails on connect", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.DataLengthBasedRead = true
+		cfg.LengthPrefixBytes = 3
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		err := client.Connect()
+		assert.ErrorContains(t, err, "unsupported length prefix size")
+		assert.Equal(t, Disconnected, client.GetState())
+	})
+}
+
+func TestReconnectDelay(t *testing.T) {
+	t.Run("grows geometrically up to the max with jitter", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig("127.0.0.1:0")
+		cfg.ReconnectInterval = 100 * time.Millisecond
+		cfg.ReconnectBackoffFactor = 2
+		cfg.MaxReconnectInterval = time.Second
+		client := NewEventDrivenTCPClient(cfg)
+
+		expected := []time.Duration{
+			100 * time.Millisecond,
+			200 * time.Millisecond,
+			400 * time.Millisecond,
+			800 * time.Millisecond,
+			time.Second,
+			time.Second,
+		}
+		for i, want := range expected {
+			got, giveUp := client.reconnectPolicy.NextDelay(i+1, nil)
+			assert.False(t, giveUp)
+			assert.GreaterOrEqual(t, got, want, "attempt %d", i+1)
+			assert.LessOrEqual(t, got, want+want/10, "attempt %d", i+1)
+		}
+	})
+
+	t.Run("factor of one or less keeps a fixed interval", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig("127.0.0.1:0")
+		cfg.ReconnectInterval = 100 * time.Millisecond
+		cfg.ReconnectBackoffFactor = 0
+		client := NewEventDrivenTCPClient(cfg)
+
+		for attempt := 1; attempt <= 5; attempt++ {
+			delay, _ := client.reconnectPolicy.NextDelay(attempt, nil)
+			assert.Equal(t, 100*time.Millisecond, delay)
+		}
+	})
+
+	t.Run("defaults keep a fixed interval", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig("127.0.0.1:0"))
+
+		for attempt := 1; attempt <= 5; attempt++ {
+			delay, _ := client.reconnectPolicy.NextDelay(attempt, nil)
+			assert.Equal(t, 5*time.Second, delay)
+		}
+	})
+}
+
+func TestReconnectEvents(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	// Accept connections and drop each one shortly after it is established
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+			_ = conn.Close()
+		}
+	}()
+
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.AutoReconnect = true
+	cfg.ReconnectInterval = 10 * time.Millisecond
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	reconnecting := make(chan ConnectionStateEvent, 16)
+	client.OnConnectionState(func(event ConnectionStateEvent) {
+		if event.State == Reconnecting {
+			reconnecting <- event
+		}
+	})
+	require.NoError(t, client.Connect())
+
+	// Each drop is followed by a successful reconnect, so the attempt counter
+	// resets and every Reconnecting event reports the first attempt
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-reconnecting:
+			assert.Equal(t, 1, event.AttemptNumber)
+			assert.GreaterOrEqual(t, event.NextDelay, 10*time.Millisecond)
+			assert.LessOrEqual(t, event.NextDelay, 11*time.Millisecond)
+		case <-time.After(2 * time.Second):
+			t.Fatal("no reconnecting event")
+		}
+	}
+}
+
+func TestMaxReconnectAttempts(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	// Accept a single connection, then take the server down for good
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_ = ln.Close()
+		_ = conn.Close()
+	}()
+
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.AutoReconnect = true
+	cfg.ReconnectInterval = 5 * time.Millisecond
+	cfg.ReconnectBackoffFactor = 1
+	cfg.MaxReconnectAttempts = 3
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	var mu sync.Mutex
+	var attempts []int
+	client.OnConnectionState(func(event ConnectionStateEvent) {
+		if event.State == Reconnecting {
+			mu.Lock()
+			attempts = append(attempts, event.AttemptNumber)
+			mu.Unlock()
+		}
+	})
+	abandoned := make(chan error, 1)
+	client.OnError(func(event ErrorEvent) {
+		if strings.Contains(event.Error.Error(), "reconnection abandoned") {
+			abandoned <- event.Error
+		}
+	})
+	require.NoError(t, client.Connect())
+
+	select {
+	case err := <-abandoned:
+		assert.Contains(t, err.Error(), "after 3 attempts")
+	case <-time.After(2 * time.Second):
+		t.Fatal("reconnection was not abandoned")
+	}
+
+	assert.Eventually(t, func() bool {
+		return client.GetState() == Disconnected
+	}, time.Second, 5*time.Millisecond)
+
+	// Handlers run on their own goroutines, so give the last event time to land
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+	mu.Unlock()
+
+	// A fresh Connect restarts the cycle against a server that is back up
+	ln2, err := net.Listen("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = ln2.Close() }()
+	go func() {
+		conn, err := ln2.Accept()
+		if err == nil {
+			defer func() { _ = conn.Close() }()
+			_, _ = io.Copy(io.Discard, conn)
+		}
+	}()
+
+	require.NoError(t, client.Connect())
+	assert.True(t, client.IsConnected())
+}
+
+func TestCustomReconnectPolicy(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	// Accept a single connection, then take the server down for good
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_ = ln.Close()
+		_ = conn.Close()
+	}()
+
+	type call struct {
+		attempt int
+		lastErr error
+	}
+	var mu sync.Mutex
+	var calls []call
+
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.AutoReconnect = true
+	cfg.ReconnectPolicy = ReconnectPolicyFunc(func(attempt int, lastErr error) (time.Duration, bool) {
+		mu.Lock()
+		calls = append(calls, call{attempt, lastErr})
+		mu.Unlock()
+		return time.Duration(attempt) * 5 * time.Millisecond, attempt > 2
+	})
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	var delays []time.Duration
+	client.OnConnectionState(func(event ConnectionStateEvent) {
+		if event.State == Reconnecting {
+			mu.Lock()
+			delays = append(delays, event.NextDelay)
+			mu.Unlock()
+		}
+	})
+	abandoned := make(chan error, 1)
+	client.OnError(func(event ErrorEvent) {
+		if strings.Contains(event.Error.Error(), "reconnection abandoned") {
+			abandoned <- event.Error
+		}
+	})
+	require.NoError(t, client.Connect())
+
+	select {
+	case err := <-abandoned:
+		assert.Contains(t, err.Error(), "after 2 attempts")
+	case <-time.After(2 * time.Second):
+		t.Fatal("reconnection was not abandoned")
+	}
+
+	assert.Eventually(t, func() bool {
+		return client.GetState() == Disconnected
+	}, time.Second, 5*time.Millisecond)
+
+	// Handlers run on their own goroutines, so give the last event time to land
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, calls, 3)
+	for i, c := range calls {
+		assert.Equal(t, i+1, c.attempt)
+	}
+	assert.NoError(t, calls[0].lastErr, "first attempt has no previous error")
+	assert.Error(t, calls[1].lastErr)
+	assert.Error(t, calls[2].lastErr)
+
+	assert.Equal(t, []time.Duration{5 * time.Millisecond, 10 * time.Millisecond}, delays)
+}
+
+func TestConnectionAttemptTiming(t *testing.T) {
+	ln, _ := startTestServer(t)
+	defer func() { _ = ln.Close() }()
+
+	client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+	defer func() { _ = client.Close() }()
+
+	connected := make(chan ConnectionStateEvent, 1)
+	client.OnConnectionState(func(event ConnectionStateEvent) {
+		if event.State == Connected {
+			connected <- event
+		}
+	})
+
+	before := time.Now()
+	require.NoError(t, client.Connect())
+	after := time.Now()
+
+	select {
+	case event := <-connected:
+		assert.Equal(t, 0, event.AttemptNumber)
+		assert.False(t, event.AttemptStartedAt.Before(before))
+		assert.False(t, event.AttemptStartedAt.After(after))
+		assert.Greater(t, event.DialDuration, time.Duration(0))
+		assert.LessOrEqual(t, event.DialDuration, after.Sub(before))
+		assert.False(t, event.Timestamp.Before(event.AttemptStartedAt.Add(event.DialDuration)))
+	case <-time.After(time.Second):
+		t.Fatal("no connected event")
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	ln, _ := startTestServer(t)
+	defer func() { _ = ln.Close() }()
+
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.KeepAlive = 30 * time.Second
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	require.NoError(t, client.Connect())
+	assert.True(t, client.IsConnected())
+}
+
+func TestHeartbeat(t *testing.T) {
+	t.Run("sends the payload while connected", func(t *testing.T) {
+		ln, received := startTestServer(t)
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.HeartbeatInterval = 10 * time.Millisecond
+		cfg.HeartbeatPayload = []byte("ping")
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		assert.Equal(t, "pingpingping", string(readAll(t, received, 12)[:12]))
+	})
+
+	t.Run("stops on Disconnect", func(t *testing.T) {
+		ln, received := startTestServer(t)
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.HeartbeatInterval = 10 * time.Millisecond
+		cfg.HeartbeatPayload = []byte("ping")
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		readAll(t, received, 4)
+		require.NoError(t, client.Disconnect())
+
+		client.mu.RLock()
+		assert.Nil(t, client.heartbeatStop)
+		client.mu.RUnlock()
+	})
+
+	t.Run("Close waits for the heartbeat goroutine", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.HeartbeatInterval = time.Millisecond
+		cfg.HeartbeatPayload = []byte("ping")
+		client := NewEventDrivenTCPClient(cfg)
+		require.NoError(t, client.Connect())
+
+		done := make(chan struct{})
+		go func() {
+			_ = client.Close()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Close did not return")
+		}
+	})
+}
+
+func TestStats(t *testing.T) {
+	t.Run("counts sent and received traffic", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+
+			buf := make([]byte, 5)
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte{9, 0, 0, 0, 'w', 'o', 'r', 'l', 'd'})
+			time.Sleep(time.Second)
+		}()
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.DataLengthBasedRead = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		received := make(chan struct{}, 1)
+		client.OnDataReceived(func(event DataReceivedEvent) { received <- struct{}{} })
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.Send([]byte("hello")))
+
+		select {
+		case <-received:
+		case <-time.After(2 * time.Second):
+			t.Fatal("no data received")
+		}
+
+		assert.Equal(t, Stats{BytesSent: 5, BytesReceived: 9, MessagesReceived: 1}, client.Stats())
+
+		client.ResetStats()
+		assert.Equal(t, Stats{}, client.Stats())
+	})
+
+	t.Run("counts reconnects cumulatively", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		go func() {
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				time.Sleep(20 * time.Millisecond)
+				_ = conn.Close()
+			}
+		}()
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.AutoReconnect = true
+		cfg.ReconnectInterval = 10 * time.Millisecond
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		assert.Eventually(t, func() bool {
+			return client.Stats().ReconnectCount >= 2
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestSendRequest(t *testing.T) {
+	// Frames are [length:4][correlation ID:4][body]; the server echoes each frame back
+	correlationID := func(data []byte) (uint32, bool) {
+		if len(data) < 8 {
+			return 0, false
+		}
+		return binary.LittleEndian.Uint32(data[4:8]), true
+	}
+	request := func(id uint32, body string) []byte {
+		return append(binary.LittleEndian.AppendUint32(nil, id), body...)
+	}
+
+	newClient := func(t *testing.T, addr string) *EventDrivenTCPClient {
+		cfg := DefaultEventDrivenTCPClientConfig(addr)
+		cfg.DataLengthBasedRead = true
+		cfg.CorrelationIDFunc = correlationID
+		client := NewEventDrivenTCPClient(cfg)
+		t.Cleanup(func() { _ = client.Close() })
+		require.NoError(t, client.Connect())
+		return client
+	}
+
+	t.Run("resolves with the matching response", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			_, _ = io.Copy(conn, conn)
+		}()
+
+		client := newClient(t, ln.Addr().String())
+		dataEvents := make(chan DataReceivedEvent, 1)
+		client.OnDataReceived(func(event DataReceivedEvent) { dataEvents <- event })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		resp, err := client.SendRequest(ctx, 7, request(7, "ping"))
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(resp[8:]))
+
+		select {
+		case <-dataEvents:
+			t.Fatal("response was also delivered to OnDataReceived")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		client.mu.RLock()
+		assert.Empty(t, client.pendingRequests)
+		client.mu.RUnlock()
+	})
+
+	t.Run("times out via the context and removes the waiter", func(t *testing.T) {
+		ln, received := startTestServer(t)
+		client := newClient(t, ln.Addr().String())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.SendRequest(ctx, 1, request(1, "ping"))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		readAll(t, received, 12)
+
+		client.mu.RLock()
+		assert.Empty(t, client.pendingRequests)
+		client.mu.RUnlock()
+	})
+
+	t.Run("reuses an ID straight after its response", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			_, _ = io.Copy(conn, conn)
+		}()
+
+		client := newClient(t, ln.Addr().String())
+
+		// Two goroutines take turns on one ID; the one that just got its response
+		// must not remove the other's waiter when it cleans up
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for sent := 0; sent < 100; {
+					ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+					_, err := client.SendRequest(ctx, 5, request(5, "ping"))
+					cancel()
+					if err != nil && strings.Contains(err.Error(), "already pending") {
+						runtime.Gosched()
+						continue
+					}
+					if !assert.NoError(t, err) {
+						return
+					}
+					sent++
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("requires a correlation ID function", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig("127.0.0.1:0"))
+		defer func() { _ = client.Close() }()
+
+		_, err := client.SendRequest(context.Background(), 1, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestAddDataReceivedHandler(t *testing.T) {
+	t.Run("all handlers receive each event", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig("127.0.0.1:0"))
+		defer func() { _ = client.Close() }()
+
+		single := make(chan []byte, 1)
+		first := make(chan []byte, 1)
+		second := make(chan []byte, 1)
+		client.OnDataReceived(func(event DataReceivedEvent) { single <- event.Data })
+		client.AddDataReceivedHandler(func(event DataReceivedEvent) { first <- event.Data })
+		client.AddDataReceivedHandler(func(event DataReceivedEvent) { second <- event.Data })
+
+		client.emitDataReceived([]byte("hello"))
+
+		for _, ch := range []chan []byte{single, first, second} {
+			select {
+			case data := <-ch:
+				assert.Equal(t, []byte("hello"), data)
+			case <-time.After(time.Second):
+				t.Fatal("handler was not called")
+			}
+		}
+	})
+
+	t.Run("removed handlers are not called", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig("127.0.0.1:0"))
+		defer func() { _ = client.Close() }()
+
+		removed := make(chan struct{}, 1)
+		kept := make(chan struct{}, 1)
+		remove := client.AddDataReceivedHandler(func(event DataReceivedEvent) { removed <- struct{}{} })
+		client.AddDataReceivedHandler(func(event DataReceivedEvent) { kept <- struct{}{} })
+
+		remove()
+		remove()
+		client.emitDataReceived([]byte("hello"))
+
+		select {
+		case <-kept:
+		case <-time.After(time.Second):
+			t.Fatal("remaining handler was not called")
+		}
+		select {
+		case <-removed:
+			t.Fatal("removed handler was called")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("concurrent add and remove during reads", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		stop := make(chan struct{})
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := conn.Write([]byte("data")); err != nil {
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+		defer close(stop)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+		require.NoError(t, client.Connect())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					remove := client.AddDataReceivedHandler(func(event DataReceivedEvent) {})
+					time.Sleep(100 * time.Microsecond)
+					remove()
+				}
+			}()
+		}
+		wg.Wait()
+
+		client.mu.RLock()
+		assert.Empty(t, client.dataHandlers)
+		client.mu.RUnlock()
+	})
+}
+
+func TestSocketBufferSizes(t *testing.T) {
+	ln, received := startTestServer(t)
+
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.SocketReadBufferSize = 256 * 1024
+	cfg.SocketWriteBufferSize = 256 * 1024
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.Send([]byte("hello")))
+	assert.Equal(t, "hello", string(readAll(t, received, 5)))
+}
+
+func TestSerialHandlers(t *testing.T) {
+	t.Run("delivers data events in arrival order", func(t *testing.T) {
+		const messages = 200
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			for i := uint32(0); i < messages; i++ {
+				frame := binary.LittleEndian.AppendUint32(nil, 8)
+				frame = binary.LittleEndian.AppendUint32(frame, i)
+				if _, err := conn.Write(frame); err != nil {
+					return
+				}
+			}
+			time.Sleep(time.Second)
+		}()
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.DataLengthBasedRead = true
+		cfg.SerialHandlers = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		received := make(chan uint32, messages)
+		client.OnDataReceived(func(event DataReceivedEvent) {
+			received <- binary.LittleEndian.Uint32(event.Data[4:])
+		})
+		require.NoError(t, client.Connect())
+
+		for i := uint32(0); i < messages; i++ {
+			select {
+			case got := <-received:
+				require.Equal(t, i, got)
+			case <-time.After(2 * time.Second):
+				t.Fatalf("message %d not received", i)
+			}
+		}
+	})
+
+	t.Run("delivers state events in order including Closed", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.SerialHandlers = true
+		client := NewEventDrivenTCPClient(cfg)
+
+		var mu sync.Mutex
+		var states []ConnectionState
+		client.OnConnectionState(func(event ConnectionStateEvent) {
+			mu.Lock()
+			states = append(states, event.State)
+			mu.Unlock()
+		})
+
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.Close())
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(states) == 3
+		}, time.Second, 10*time.Millisecond)
+
+		mu.Lock()
+		assert.Equal(t, []ConnectionState{Connecting, Connected, Closed}, states)
+		mu.Unlock()
+	})
+
+	// flood connects a client with a one-slot event queue to a server that keeps
+	// sending, and runs stop from the first data handler once the queue has filled up.
+	flood := func(t *testing.T, stop func(client *EventDrivenTCPClient) error) (chan error, chan ConnectionState) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			for {
+				if _, err := conn.Write([]byte("data")); err != nil {
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.SerialHandlers = true
+		cfg.EventQueueSize = 1
+		client := NewEventDrivenTCPClient(cfg)
+		t.Cleanup(func() { _ = client.Close() })
+
+		states := make(chan ConnectionState, 16)
+		client.OnConnectionState(func(event ConnectionStateEvent) {
+			select {
+			case states <- event.State:
+			default:
+			}
+		})
+
+		stopped := make(chan error, 1)
+		var once sync.Once
+		client.OnDataReceived(func(event DataReceivedEvent) {
+			once.Do(func() {
+				time.Sleep(50 * time.Millisecond) // let the read loop fill the queue
+				stopped <- stop(client)
+			})
+		})
+		require.NoError(t, client.Connect())
+
+		return stopped, states
+	}
+
+	waitForState := func(t *testing.T, states chan ConnectionState, want ConnectionState) {
+		for {
+			select {
+			case state := <-states:
+				if state == want {
+					return
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("%s state event not delivered", want)
+			}
+		}
+	}
+
+	t.Run("handler can Close while the queue is full", func(t *testing.T) {
+		stopped, states := flood(t, func(client *EventDrivenTCPClient) error { return client.Close() })
+
+		select {
+		case err := <-stopped:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Close from a handler hung")
+		}
+		waitForState(t, states, Closed)
+	})
+
+	t.Run("handler can Disconnect while the queue is full", func(t *testing.T) {
+		stopped, states := flood(t, func(client *EventDrivenTCPClient) error { return client.Disconnect() })
+
+		select {
+		case err := <-stopped:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Disconnect from a handler hung")
+		}
+		waitForState(t, states, Disconnected)
+	})
+}
+
+func TestDelimiterBasedRead(t *testing.T) {
+	// serve accepts one connection and writes each chunk with a short pause, so that
+	// messages arrive split across and combined within reads
+	serve := func(t *testing.T, chunks ...string) string {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			for _, chunk := range chunks {
+				if _, err := conn.Write([]byte(chunk)); err != nil {
+					return
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			time.Sleep(time.Second)
+		}()
+
+		return ln.Addr().String()
+	}
+
+	collect := func(t *testing.T, cfg Config, want int) []string {
+		cfg.SerialHandlers = true
+		client := NewEventDrivenTCPClient(cfg)
+		t.Cleanup(func() { _ = client.Close() })
+
+		received := make(chan string, want)
+		client.OnDataReceived(func(event DataReceivedEvent) { received <- string(event.Data) })
+		require.NoError(t, client.Connect())
+
+		var lines []string
+		for len(lines) < want {
+			select {
+			case line := <-received:
+				lines = append(lines, line)
+			case <-time.After(2 * time.Second):
+				t.Fatalf("received %d of %d messages", len(lines), want)
+			}
+		}
+		return lines
+	}
+
+	t.Run("reassembles messages across reads", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig(serve(t, "hel", "lo\nwor", "ld\n\nlast\n"))
+		cfg.DelimiterBasedRead = true
+		cfg.ReadBufferSize = 16
+
+		assert.Equal(t, []string{"hello", "world", "", "last"}, collect(t, cfg, 4))
+	})
+
+	t.Run("keeps the delimiter when configured", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig(serve(t, "a;b;"))
+		cfg.DelimiterBasedRead = true
+		cfg.Delimiter = ';'
+		cfg.KeepDelimiter = true
+
+		assert.Equal(t, []string{"a;", "b;"}, collect(t, cfg, 2))
+	})
+
+	t.Run("accepts a message longer than the read buffer", func(t *testing.T) {
+		long := strings.Repeat("x", 100)
+		cfg := DefaultEventDrivenTCPClientConfig(serve(t, long+"\n"))
+		cfg.DelimiterBasedRead = true
+		cfg.ReadBufferSize = 16
+
+		assert.Equal(t, []string{long}, collect(t, cfg, 1))
+	})
+
+	t.Run("rejects messages over MaxLineLength", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig(serve(t, "short\n", strings.Repeat("x", 64)))
+		cfg.DelimiterBasedRead = true
+		cfg.ReadBufferSize = 16
+		cfg.MaxLineLength = 32
+
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		errs := make(chan error, 1)
+		client.OnError(func(event ErrorEvent) { errs <- event.Error })
+		require.NoError(t, client.Connect())
+
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "maximum line length 32")
+		case <-time.After(2 * time.Second):
+			t.Fatal("no error for an over-long message")
+		}
+	})
+
+	t.Run("cannot be combined with DataLengthBasedRead", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig("127.0.0.1:0")
+		cfg.DelimiterBasedRead = true
+		cfg.DataLengthBasedRead = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		assert.ErrorContains(t, client.Connect(), "mutually exclusive")
+	})
+}
+
+func TestPauseResume(t *testing.T) {
+	// startWriter accepts one connection and writes whatever is sent on the returned channel
+	startWriter := func(t *testing.T) (string, chan<- string) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		outgoing := make(chan string, 16)
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			for msg := range outgoing {
+				if _, err := conn.Write([]byte(msg)); err != nil {
+					return
+				}
+			}
+		}()
+
+		return ln.Addr().String(), outgoing
+	}
+
+	t.Run("paused client does not read until resumed", func(t *testing.T) {
+		addr, outgoing := startWriter(t)
+		defer close(outgoing)
+
+		cfg := DefaultEventDrivenTCPClientConfig(addr)
+		cfg.DelimiterBasedRead = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		received := make(chan string, 4)
+		errs := make(chan error, 4)
+		client.OnDataReceived(func(event DataReceivedEvent) { received <- string(event.Data) })
+		client.OnError(func(event ErrorEvent) { errs <- event.Error })
+		require.NoError(t, client.Connect())
+
+		outgoing <- "first\n"
+		select {
+		case msg := <-received:
+			assert.Equal(t, "first", msg)
+		case <-time.After(2 * time.Second):
+			t.Fatal("first message not received")
+		}
+
+		client.Pause()
+		assert.True(t, client.IsPaused())
+		outgoing <- "second\n"
+
+		select {
+		case msg := <-received:
+			t.Fatalf("received %q while paused", msg)
+		case err := <-errs:
+			t.Fatalf("error while paused: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+		assert.Equal(t, Connected, client.GetState())
+
+		client.Resume()
+		assert.False(t, client.IsPaused())
+		select {
+		case msg := <-received:
+			assert.Equal(t, "second", msg)
+		case <-time.After(2 * time.Second):
+			t.Fatal("message not received after Resume")
+		}
+	})
+
+	t.Run("Disconnect and Close unblock a paused read loop", func(t *testing.T) {
+		addr, outgoing := startWriter(t)
+		defer close(outgoing)
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(addr))
+		require.NoError(t, client.Connect())
+
+		client.Pause()
+		time.Sleep(20 * time.Millisecond)
+		require.NoError(t, client.Disconnect())
+
+		done := make(chan struct{})
+		go func() {
+			_ = client.Close()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Close did not return while paused")
+		}
+	})
+}
+
+func TestDrainReceived(t *testing.T) {
+	// closeWhileHolding connects a paused client to a server that writes data once, waits
+	// until the read loop has read a message and is holding it, then closes the client.
+	closeWhileHolding := func(t *testing.T, cfg Config, data []byte, read uint64) *EventDrivenTCPClient {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		write := make(chan struct{})
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			<-write
+			_, _ = conn.Write(data)
+			time.Sleep(time.Second)
+		}()
+
+		cfg.Address = ln.Addr().String()
+		client := NewEventDrivenTCPClient(cfg)
+		delivered := make(chan []byte, 4)
+		client.OnDataReceived(func(event DataReceivedEvent) { delivered <- event.Data })
+		require.NoError(t, client.Connect())
+
+		// Let the read loop block in a read before pausing, so it reads one message
+		time.Sleep(20 * time.Millisecond)
+		client.Pause()
+		close(write)
+		require.Eventually(t, func() bool { return client.Stats().BytesReceived >= read }, 2*time.Second, 5*time.Millisecond)
+
+		require.NoError(t, client.Close())
+		assert.Empty(t, delivered)
+		return client
+	}
+
+	t.Run("returns a final framed message read before Close", func(t *testing.T) {
+		frame := make([]byte, 4, 9)
+		binary.LittleEndian.PutUint32(frame, 9)
+		frame = append(frame, "final"...)
+
+		cfg := DefaultEventDrivenTCPClientConfig("")
+		cfg.DataLengthBasedRead = true
+		client := closeWhileHolding(t, cfg, frame, uint64(len(frame)))
+
+		assert.Equal(t, [][]byte{frame}, client.DrainReceived())
+		assert.Nil(t, client.DrainReceived(), "messages are returned only once")
+	})
+
+	t.Run("returns buffered delimited messages", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig("")
+		cfg.DelimiterBasedRead = true
+		client := closeWhileHolding(t, cfg, []byte("one\ntwo\nthree\npartial"), uint64(len("one\n")))
+
+		assert.Equal(t, [][]byte{[]byte("one"), []byte("two"), []byte("three")}, client.DrainReceived())
+	})
+
+	t.Run("nil when everything was delivered", func(t *testing.T) {
+		ln, _ := startTestServer(t)
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.Close())
+
+		assert.Nil(t, client.DrainReceived())
+	})
+}
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	// Echo everything back to the client
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	cfg := DefaultEventDrivenTCPClientConfig(path)
+	cfg.Network = "unix"
+	cfg.KeepAlive = 30 * time.Second
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	connected := make(chan ConnectionStateEvent, 1)
+	client.OnConnectionState(func(event ConnectionStateEvent) {
+		if event.State == Connected {
+			connected <- event
+		}
+	})
+	received := make(chan []byte, 1)
+	client.OnDataReceived(func(event DataReceivedEvent) { received <- event.Data })
+
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.Send([]byte("hello")))
+
+	select {
+	case data := <-received:
+		assert.Equal(t, "hello", string(data))
+	case <-time.After(2 * time.Second):
+		t.Fatal("no data received over the unix socket")
+	}
+
+	select {
+	case event := <-connected:
+		assert.Equal(t, path, event.Address)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no connected event")
+	}
+}
+
+func TestCloseGracefully(t *testing.T) {
+	t.Run("flushes queued messages before closing", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		received := make(chan []byte, 1)
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			data, _ := io.ReadAll(conn)
+			received <- data
+		}()
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		require.NoError(t, client.Connect())
+
+		var acked atomic.Int32
+		for i := 0; i < 50; i++ {
+			client.SendWithAck([]byte("x"), func(err error) {
+				if err == nil {
+					acked.Add(1)
+				}
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		require.NoError(t, client.CloseGracefully(ctx))
+		assert.Equal(t, int32(50), acked.Load())
+		assert.Equal(t, Closed, client.GetState())
+
+		select {
+		case data := <-received:
+			assert.Equal(t, strings.Repeat("x", 50), string(data))
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not see the connection close")
+		}
+
+		assert.Error(t, client.Send([]byte("late")))
+	})
+
+	t.Run("closes when context expires", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		// Accept but never read, so a large write blocks
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			time.Sleep(3 * time.Second)
+		}()
+
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		require.NoError(t, client.Connect())
+
+		sendDone := make(chan error, 1)
+		go func() { sendDone <- client.Send(make([]byte, 64<<20)) }()
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, client.CloseGracefully(ctx), context.DeadlineExceeded)
+
+		select {
+		case err := <-sendDone:
+			assert.Error(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("blocked send was not released by close")
+		}
+	})
+}
+
+func TestDisconnectGraceful(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+	defer func() { _ = client.Close() }()
+	require.NoError(t, client.Connect())
+
+	for i := 0; i < 50; i++ {
+		client.SendWithAck([]byte("y"), nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, client.DisconnectGraceful(ctx))
+	assert.Equal(t, Disconnected, client.GetState())
+
+	select {
+	case data := <-received:
+		assert.Equal(t, strings.Repeat("y", 50), string(data))
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not see the connection close")
+	}
+
+	// The client stays usable after a graceful disconnect
+	client.SendWithAck([]byte("z"), func(err error) {
+		assert.Error(t, err)
+	})
+	require.NoError(t, client.DisconnectGraceful(ctx))
+}
+
+// capturingLogger records the message and fields of every entry it receives.
+type capturingLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *capturingLogger) record(level, msg string, fields []logger.Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := level + " " + msg
+	for _, f := range fields {
+		entry += fmt.Sprintf(" %s=%v", f.Key, f.Value)
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *capturingLogger) Debug(msg string, fields ...logger.Field) { l.record("debug", msg, fields) }
+func (l *capturingLogger) Info(msg string, fields ...logger.Field)  { l.record("info", msg, fields) }
+func (l *capturingLogger) Warn(msg string, fields ...logger.Field)  { l.record("warn", msg, fields) }
+func (l *capturingLogger) Error(msg string, fields ...logger.Field) { l.record("error", msg, fields) }
+func (l *capturingLogger) Fatal(msg string, fields ...logger.Field) { l.record("fatal", msg, fields) }
+func (l *capturingLogger) Panic(msg string, fields ...logger.Field) { l.record("panic", msg, fields) }
+func (l *capturingLogger) Log(level zerolog.Level, msg string, fields ...logger.Field) {
+	l.record(level.String(), msg, fields)
+}
+func (l *capturingLogger) Enabled(level zerolog.Level) bool          { return true }
+func (l *capturingLogger) With(fields ...logger.Field) logger.Logger { return l }
+func (l *capturingLogger) WithError(err error) logger.Logger         { return l }
+func (l *capturingLogger) WithCaller() logger.Logger                 { return l }
+func (l *capturingLogger) GetLoggerInstance() interface{}            { return nil }
+func (l *capturingLogger) Close() error                              { return nil }
+
+func (l *capturingLogger) has(substr string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		if strings.Contains(entry, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLogger(t *testing.T) {
+	ln, received := startTestServer(t)
+
+	log := &capturingLogger{}
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.Logger = log
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.Send([]byte("hello")))
+	readAll(t, received, 5)
+
+	assert.Eventually(t, func() bool {
+		return log.has("debug connection state changed state=Connected")
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, log.has("debug sent data bytes=5"))
+
+	client.emitError(fmt.Errorf("boom"))
+	assert.True(t, log.has("error client error"))
+
+	_ = client.Disconnect()
+	assert.True(t, log.has("state=Disconnected"))
+}
+
+func TestLoggerUnset(t *testing.T) {
+	ln, _ := startTestServer(t)
+
+	client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+	defer func() { _ = client.Close() }()
+
+	// A nil Logger must not be dereferenced on any path
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.Send([]byte("hello")))
+	client.emitError(fmt.Errorf("boom"))
+}
+
+func TestReplayStateOnRegister(t *testing.T) {
+	ln, _ := startTestServer(t)
+
+	t.Run("late handler receives the current state", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.ReplayStateOnRegister = true
+		client := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = client.Close() }()
+
+		require.NoError(t, client.Connect())
+
+		events := make(chan ConnectionStateEvent, 4)
+		client.OnConnectionState(func(event ConnectionStateEvent) { events <- event })
+
+		select {
+		case event := <-events:
+			assert.Equal(t, Connected, event.State)
+			assert.Equal(t, cfg.Address, event.Address)
+			assert.False(t, event.Timestamp.IsZero())
+		case <-time.After(2 * time.Second):
+			t.Fatal("current state was not replayed")
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+		defer func() { _ = client.Close() }()
+
+		events := make(chan ConnectionStateEvent, 4)
+		client.OnConnectionState(func(event ConnectionStateEvent) { events <- event })
+
+		select {
+		case event := <-events:
+			t.Fatalf("unexpected event %v", event.State)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
+// selfSignedCert returns a certificate for 127.0.0.1 and a pool that trusts it.
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestTLSNegotiatedProtocol(t *testing.T) {
+	cert, pool := selfSignedCert(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"proto/v2", "proto/v1"},
+	})
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	// Echo everything back to each client
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.TLSConfig = &tls.Config{
+		RootCAs:    pool,
+		NextProtos: []string{"proto/v1"},
+	}
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	received := make(chan []byte, 1)
+	client.OnDataReceived(func(event DataReceivedEvent) { received <- event.Data })
+
+	assert.Empty(t, client.NegotiatedProtocol(), "not connected yet")
+	require.NoError(t, client.Connect())
+	assert.Equal(t, "proto/v1", client.NegotiatedProtocol())
+
+	require.NoError(t, client.Send([]byte("hello")))
+	select {
+	case data := <-received:
+		assert.Equal(t, "hello", string(data))
+	case <-time.After(2 * time.Second):
+		t.Fatal("no data received over TLS")
+	}
+
+	t.Run("empty without TLS", func(t *testing.T) {
+		plainLn, _ := startTestServer(t)
+		plain := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(plainLn.Addr().String()))
+		defer func() { _ = plain.Close() }()
+
+		require.NoError(t, plain.Connect())
+		assert.Empty(t, plain.NegotiatedProtocol())
+	})
+
+	t.Run("untrusted certificate fails to connect", func(t *testing.T) {
+		cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+		cfg.TLSConfig = &tls.Config{}
+		untrusted := NewEventDrivenTCPClient(cfg)
+		defer func() { _ = untrusted.Close() }()
+
+		err := untrusted.Connect()
+		assert.ErrorContains(t, err, "tls handshake failed")
+		assert.Equal(t, Disconnected, untrusted.GetState())
+	})
+}
+
+func TestUpgradeToTLS(t *testing.T) {
+	cert, pool := selfSignedCert(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	// Plaintext until the client sends STARTTLS, then TLS echo
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil || line != "STARTTLS\n" {
+			return
+		}
+		if _, err := conn.Write([]byte("READY\n")); err != nil {
+			return
+		}
+
+		tlsConn := tls.Server(conn, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"starttls/v1"},
+		})
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		_, _ = io.Copy(tlsConn, tlsConn)
+	}()
+
+	cfg := DefaultEventDrivenTCPClientConfig(ln.Addr().String())
+	cfg.DelimiterBasedRead = true
+	client := NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	received := make(chan string, 4)
+	client.OnDataReceived(func(event DataReceivedEvent) { received <- string(event.Data) })
+
+	tlsConfig := &tls.Config{RootCAs: pool, NextProtos: []string{"starttls/v1"}}
+	assert.ErrorContains(t, client.UpgradeToTLS(tlsConfig), "not connected")
+
+	require.NoError(t, client.Connect())
+	assert.ErrorContains(t, client.UpgradeToTLS(nil), "tls config is nil")
+
+	require.NoError(t, client.Send([]byte("STARTTLS\n")))
+	select {
+	case line := <-received:
+		require.Equal(t, "READY", line)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no STARTTLS response received")
+	}
+
+	require.NoError(t, client.UpgradeToTLS(tlsConfig))
+	assert.Equal(t, Connected, client.GetState())
+	assert.Equal(t, "starttls/v1", client.NegotiatedProtocol())
+
+	require.NoError(t, client.Send([]byte("hello\n")))
+	select {
+	case line := <-received:
+		assert.Equal(t, "hello", line)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no data received over TLS")
+	}
+
+	assert.ErrorContains(t, client.UpgradeToTLS(tlsConfig), "already uses tls")
+}
+
+// gatedConn holds back writes of gatedData until release is closed, signalling on
+// blocked once such a write has started.
+type gatedConn struct {
+	net.Conn
+	gatedData string
+	blocked   chan struct{}
+	release   chan struct{}
+}
+
+func (g *gatedConn) Write(p []byte) (int, error) {
+	if string(p) == g.gatedData {
+		close(g.blocked)
+		<-g.release
+	}
+	return g.Conn.Write(p)
+}
+
+// bufferedConn reads through r, so bytes already buffered while peeking are not lost.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) {
+	return b.r.Read(p)
+}
+
+// startPlaintextThenTLSServer accepts one connection, skips plaintext lines until a
+// TLS record starts, and then echoes over TLS. Plaintext that arrives after the
+// ClientHello makes the handshake fail.
+func startPlaintextThenTLSServer(t *testing.T) (string, *x509.CertPool) {
+	cert, pool := selfSignedCert(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		r := bufio.NewReader(conn)
+		for {
+			next, err := r.Peek(1)
+			if err != nil {
+				return
+			}
+			if next[0] == 0x16 {
+				break
+			}
+			if _, err := r.ReadString('\n'); err != nil {
+				return
+			}
+		}
+
+		tlsConn := tls.Server(&bufferedConn{Conn: conn, r: r}, &tls.Config{Certificates: []tls.Certificate{cert}})
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		_, _ = io.Copy(tlsConn, tlsConn)
+	}()
+
+	return ln.Addr().String(), pool
+}
+
+func TestUpgradeToTLS_ConcurrentSends(t *testing.T) {
+	addr, pool := startPlaintextThenTLSServer(t)
+
+	client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(addr))
+	defer func() { _ = client.Close() }()
+
+	hello := make(chan struct{}, 1)
+	client.OnDataReceived(func(event DataReceivedEvent) {
+		if strings.Contains(string(event.Data), "hello") {
+			select {
+			case hello <- struct{}{}:
+			default:
+			}
+		}
+	})
+	require.NoError(t, client.Connect())
+
+	gated := &gatedConn{gatedData: "slow\n", blocked: make(chan struct{}), release: make(chan struct{})}
+	client.mu.Lock()
+	gated.Conn = client.conn
+	client.conn = gated
+	client.mu.Unlock()
+
+	// A send that is already writing when the upgrade starts
+	slowSent := make(chan error, 1)
+	go func() { slowSent <- client.Send([]byte("slow\n")) }()
+	<-gated.blocked
+
+	// Sends racing the upgrade either complete in plaintext first or are rejected
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if err := client.Send([]byte("x\n")); err != nil {
+					assert.ErrorIs(t, err, errUpgrading)
+				}
+			}
+		}()
+	}
+
+	upgraded := make(chan error, 1)
+	go func() { upgraded <- client.UpgradeToTLS(&tls.Config{RootCAs: pool}) }()
+
+	select {
+	case err := <-upgraded:
+		t.Fatalf("upgrade finished while a write was pending: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(gated.release)
+	require.NoError(t, <-slowSent)
+	require.NoError(t, <-upgraded)
+	close(stop)
+	wg.Wait()
+
+	require.NoError(t, client.Send([]byte("hello\n")))
+	select {
+	case <-hello:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no data received over TLS")
+	}
+}
+
+func TestUpgradeToTLS_QueuedAcks(t *testing.T) {
+	addr, pool := startPlaintextThenTLSServer(t)
+
+	client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(addr))
+	defer func() { _ = client.Close() }()
+	require.NoError(t, client.Connect())
+
+	gated := &gatedConn{gatedData: "slow\n", blocked: make(chan struct{}), release: make(chan struct{})}
+	client.mu.Lock()
+	gated.Conn = client.conn
+	client.conn = gated
+	client.mu.Unlock()
+
+	// One message is being written when the upgrade starts; two wait behind it
+	acks := make(chan error, 3)
+	ack := func(err error) { acks <- err }
+	client.SendWithAck([]byte("slow\n"), ack)
+	<-gated.blocked
+	client.SendWithAck([]byte("queued\n"), ack)
+	client.SendWithAck([]byte("queued\n"), ack)
+
+	upgraded := make(chan error, 1)
+	go func() { upgraded <- client.UpgradeToTLS(&tls.Config{RootCAs: pool}) }()
+	require.Eventually(t, func() bool {
+		client.mu.RLock()
+		defer client.mu.RUnlock()
+		return client.upgrading
+	}, 2*time.Second, time.Millisecond)
+
+	close(gated.release)
+	require.NoError(t, <-upgraded)
+
+	assert.NoError(t, <-acks, "the write under way completes in plaintext")
+	assert.ErrorIs(t, <-acks, errUpgrading)
+	assert.ErrorIs(t, <-acks, errUpgrading)
+}
+
+func TestUpgradeToTLS_HandshakeFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	// Answer whatever arrives, including a ClientHello, with plaintext
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		buf := make([]byte, 4096)
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("500 unrecognized command\r\n"))
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	client := NewEventDrivenTCPClient(DefaultEventDrivenTCPClientConfig(ln.Addr().String()))
+	defer func() { _ = client.Close() }()
+
+	errs := make(chan error, 1)
+	client.OnError(func(event ErrorEvent) { errs <- event.Error })
+
+	require.NoError(t, client.Connect())
+
+	err = client.UpgradeToTLS(&tls.Config{})
+	assert.ErrorContains(t, err, "tls handshake failed")
+	assert.Equal(t, Disconnected, client.GetState())
+
+	select {
+	case reported := <-errs:
+		assert.Equal(t, err, reported)
+	case <-time.After(2 * time.Second):
+		t.Fatal("handshake failure was not reported")
+	}
+}
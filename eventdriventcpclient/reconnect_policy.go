@@ -0,0 +1,83 @@
+package eventdriventcpclient
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ReconnectPolicy decides how long the client waits before each automatic reconnect
+// attempt and when it stops trying. Set Config.ReconnectPolicy to use one; by default
+// the client uses a BackoffPolicy built from the Reconnect* fields of Config.
+type ReconnectPolicy interface {
+	// NextDelay is called before each reconnect attempt of a cycle. The attempt counter
+	// restarts at 1 once a reconnect succeeds or the client gives up.
+	//
+	// Parameters:
+	//   - attempt: The 1-based number of the attempt about to be made
+	//   - lastErr: The error from the previous failed attempt; nil for the first attempt
+	//
+	// Returns:
+	//   - The delay before the attempt, and true to give up instead of making it
+	NextDelay(attempt int, lastErr error) (delay time.Duration, giveUp bool)
+}
+
+// ReconnectPolicyFunc adapts an ordinary function to the ReconnectPolicy interface.
+type ReconnectPolicyFunc func(attempt int, lastErr error) (time.Duration, bool)
+
+// NextDelay calls f(attempt, lastErr).
+func (f ReconnectPolicyFunc) NextDelay(attempt int, lastErr error) (time.Duration, bool) {
+	return f(attempt, lastErr)
+}
+
+// BackoffPolicy is a ReconnectPolicy with exponential backoff. It is the policy used when
+// Config.ReconnectPolicy is nil, with its fields taken from ReconnectInterval,
+// MaxReconnectInterval, ReconnectBackoffFactor, and MaxReconnectAttempts.
+type BackoffPolicy struct {
+	// Interval is the delay before the first attempt.
+	Interval time.Duration
+	// MaxInterval caps the delay as it grows with Factor; 0 means no cap.
+	MaxInterval time.Duration
+	// Factor multiplies the delay after each failed attempt, and a random jitter of up to
+	// 10% is added to each delay. Values <= 1 disable backoff and jitter, so every attempt
+	// waits exactly Interval.
+	Factor float64
+	// MaxAttempts is the number of attempts after which the policy gives up; 0 means unlimited.
+	MaxAttempts int
+}
+
+// NextDelay returns Interval grown geometrically by Factor for the given attempt, capped at
+// MaxInterval and with up to 10% jitter added, and gives up once MaxAttempts is exceeded.
+//
+// Parameters:
+//   - attempt: The 1-based number of the attempt about to be made
+//   - lastErr: Ignored
+//
+// Returns:
+//   - The delay before the attempt, and true if more than MaxAttempts attempts have been made
+func (p BackoffPolicy) NextDelay(attempt int, lastErr error) (time.Duration, bool) {
+	if p.MaxAttempts > 0 && attempt > p.MaxAttempts {
+		return 0, true
+	}
+
+	base := p.Interval
+	if p.Factor <= 1 || base <= 0 {
+		return base, false
+	}
+
+	delay := float64(base) * math.Pow(p.Factor, float64(attempt-1))
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		delay = float64(p.MaxInterval)
+	}
+
+	if delay > math.MaxInt64/2 {
+		delay = math.MaxInt64 / 2
+	}
+
+	result := time.Duration(delay)
+	if jitter := int64(result / 10); jitter > 0 {
+		result += time.Duration(rand.Int63n(jitter + 1))
+	}
+
+	return result, false
+}
@@ -0,0 +1,261 @@
+// Package tcptest provides a loopback TCP server for testing code that uses the
+// eventdriventcpclient package, without hand-writing listeners in every test.
+package tcptest
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultTimeout is how long Server methods wait for a connection or for data
+// before failing.
+const DefaultTimeout = 2 * time.Second
+
+// Server is a TCP server listening on a random loopback port. It accepts client
+// connections, records everything the current client sends, and can push data back.
+// When a new connection is accepted (e.g. after the client reconnects), it replaces
+// the previous one. Methods are safe for concurrent use.
+type Server struct {
+	t        testing.TB
+	listener net.Listener
+
+	// Timeout bounds how long methods wait for a connection or data; it defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	mu          sync.Mutex
+	conn        net.Conn
+	connections int
+	ready       chan struct{}
+	readyOnce   sync.Once
+	accepted    chan struct{}
+	received    chan []byte
+	pending     []byte
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewServer starts a Server on 127.0.0.1 with a random port. The server is closed
+// automatically when the test finishes.
+//
+// Parameters:
+//   - t: The test or benchmark that owns the server
+//
+// Returns:
+//   - A running *Server; pass Addr() as the client's Config.Address
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("tcptest: failed to listen: %v", err)
+	}
+
+	s := &Server{
+		t:        t,
+		listener: ln,
+		Timeout:  DefaultTimeout,
+		ready:    make(chan struct{}),
+		accepted: make(chan struct{}, 64),
+		received: make(chan []byte, 256),
+		done:     make(chan struct{}),
+	}
+
+	go s.acceptLoop()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Addr returns the "host:port" the server listens on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Connections returns how many connections the server has accepted so far.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// WaitForConnection blocks until the server accepts a connection that has not yet been
+// waited for, so consecutive calls observe consecutive connections (e.g. a reconnect).
+//
+// Returns:
+//   - An error if no connection is accepted within Timeout
+func (s *Server) WaitForConnection() error {
+	select {
+	case <-s.accepted:
+		return nil
+	case <-time.After(s.Timeout):
+		return fmt.Errorf("tcptest: no connection accepted within %s", s.Timeout)
+	}
+}
+
+// Send writes data to the current client connection, waiting up to Timeout for the
+// first connection to be accepted.
+//
+// Parameters:
+//   - data: Bytes to push to the client
+//
+// Returns:
+//   - An error if no client connected in time or the write fails
+func (s *Server) Send(data []byte) error {
+	select {
+	case <-s.ready:
+	case <-time.After(s.Timeout):
+		return fmt.Errorf("tcptest: no connection accepted within %s", s.Timeout)
+	}
+
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return fmt.Errorf("tcptest: server is closed")
+	}
+
+	_, err := conn.Write(data)
+	return err
+}
+
+// Read returns exactly n bytes sent by the client, waiting up to Timeout for them.
+// Bytes beyond n are kept for the next call.
+//
+// Parameters:
+//   - n: Number of bytes to read
+//
+// Returns:
+//   - The bytes read, or an error if fewer than n bytes arrived within Timeout
+func (s *Server) Read(n int) ([]byte, error) {
+	deadline := time.After(s.Timeout)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.pending) < n {
+		s.mu.Unlock()
+		select {
+		case chunk := <-s.received:
+			s.mu.Lock()
+			s.pending = append(s.pending, chunk...)
+		case <-deadline:
+			s.mu.Lock()
+			return nil, fmt.Errorf("tcptest: received %d of %d bytes within %s", len(s.pending), n, s.Timeout)
+		}
+	}
+
+	data := make([]byte, n)
+	copy(data, s.pending)
+	s.pending = s.pending[n:]
+
+	return data, nil
+}
+
+// ExpectReceived fails the test unless the next bytes sent by the client equal want.
+//
+// Parameters:
+//   - want: The exact bytes the client is expected to have sent
+func (s *Server) ExpectReceived(want []byte) {
+	s.t.Helper()
+
+	got, err := s.Read(len(want))
+	if err != nil {
+		s.t.Fatalf("%v", err)
+	}
+	if !bytes.Equal(got, want) {
+		s.t.Fatalf("tcptest: received %q, want %q", got, want)
+	}
+}
+
+// MustSend is like Send but fails the test on error.
+//
+// Parameters:
+//   - data: Bytes to push to the client
+func (s *Server) MustSend(data []byte) {
+	s.t.Helper()
+
+	if err := s.Send(data); err != nil {
+		s.t.Fatalf("%v", err)
+	}
+}
+
+// DropConnection closes the current client connection, simulating a network failure.
+// The server keeps listening, so a reconnecting client is accepted again.
+func (s *Server) DropConnection() {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+// Close stops listening and closes the current connection, discarding any data
+// that was received but not read. It is safe to call multiple times.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+	_ = s.listener.Close()
+
+	s.mu.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.mu.Unlock()
+
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		previous := s.conn
+		s.conn = conn
+		s.connections++
+		s.mu.Unlock()
+
+		if previous != nil {
+			_ = previous.Close()
+		}
+
+		s.readyOnce.Do(func() { close(s.ready) })
+		select {
+		case s.accepted <- struct{}{}:
+		default:
+		}
+
+		go s.readLoop(conn)
+	}
+}
+
+func (s *Server) readLoop(conn net.Conn) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+
+			// Nobody reads the backlog once the server is closed
+			select {
+			case s.received <- chunk:
+			case <-s.done:
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tcptest_test
+
+import (
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/cyberinferno/go-utils/eventdriventcpclient"
+	"github.com/cyberinferno/go-utils/eventdriventcpclient/tcptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServer_RoundTrip shows the typical flow: the client sends a request, the test
+// asserts the bytes on the server side, and the server pushes a reply back.
+func TestServer_RoundTrip(t *testing.T) {
+	server := tcptest.NewServer(t)
+
+	client := eventdriventcpclient.NewEventDrivenTCPClient(
+		eventdriventcpclient.DefaultEventDrivenTCPClientConfig(server.Addr()))
+	defer func() { _ = client.Close() }()
+
+	replies := make(chan string, 1)
+	client.OnDataReceived(func(event eventdriventcpclient.DataReceivedEvent) {
+		replies <- string(event.Data)
+	})
+	require.NoError(t, client.Connect())
+
+	require.NoError(t, client.Send([]byte("ping")))
+	server.ExpectReceived([]byte("ping"))
+
+	server.MustSend([]byte("pong"))
+	select {
+	case reply := <-replies:
+		assert.Equal(t, "pong", reply)
+	case <-time.After(tcptest.DefaultTimeout):
+		t.Fatal("no reply received")
+	}
+}
+
+func TestServer_LengthPrefixedRoundTrip(t *testing.T) {
+	server := tcptest.NewServer(t)
+
+	cfg := eventdriventcpclient.DefaultEventDrivenTCPClientConfig(server.Addr())
+	cfg.DataLengthBasedRead = true
+	client := eventdriventcpclient.NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	replies := make(chan []byte, 1)
+	client.OnDataReceived(func(event eventdriventcpclient.DataReceivedEvent) { replies <- event.Data })
+	require.NoError(t, client.Connect())
+
+	require.NoError(t, client.SendMessage([]byte("hi")))
+	server.ExpectReceived([]byte{6, 0, 0, 0, 'h', 'i'})
+
+	server.MustSend([]byte{6, 0, 0, 0, 'o', 'k'})
+	select {
+	case reply := <-replies:
+		assert.Equal(t, "ok", string(reply[4:]))
+	case <-time.After(tcptest.DefaultTimeout):
+		t.Fatal("no reply received")
+	}
+}
+
+func TestServer_DropConnection(t *testing.T) {
+	server := tcptest.NewServer(t)
+
+	cfg := eventdriventcpclient.DefaultEventDrivenTCPClientConfig(server.Addr())
+	cfg.AutoReconnect = true
+	cfg.ReconnectInterval = 10 * time.Millisecond
+	client := eventdriventcpclient.NewEventDrivenTCPClient(cfg)
+	defer func() { _ = client.Close() }()
+
+	require.NoError(t, client.Connect())
+	require.NoError(t, server.WaitForConnection())
+
+	server.DropConnection()
+	require.NoError(t, server.WaitForConnection())
+	assert.Equal(t, 2, server.Connections())
+}
+
+func TestServer_ReadTimeout(t *testing.T) {
+	server := tcptest.NewServer(t)
+	server.Timeout = 20 * time.Millisecond
+
+	_, err := server.Read(1)
+	assert.Error(t, err)
+}
+
+func TestServer_CloseWithUnreadData(t *testing.T) {
+	before := runtime.NumGoroutine()
+	server := tcptest.NewServer(t)
+
+	conn, err := net.Dial("tcp", server.Addr())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	// Send more separate chunks than the server buffers, without reading any
+	for range 400 {
+		_, err := conn.Write([]byte{1})
+		require.NoError(t, err)
+		time.Sleep(100 * time.Microsecond)
+	}
+
+	server.Close()
+
+	// Poll by hand: assert.Eventually would add a goroutine of its own
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before, "server goroutines exit on Close")
+}
@@ -3,10 +3,12 @@ package idgenerator
 import "sync/atomic"
 
 // IdGenerator generates monotonically increasing uint32 IDs in a concurrency-safe
-// manner. Each call to Id returns the next ID. The starting value is set at
-// construction and the first Id() returns startValue+1.
+// manner. Each call to Id returns the next ID. The starting value and step are
+// set at construction and the first Id() returns startValue+step. The zero value
+// is ready to use and behaves like NewIdGenerator(0).
 type IdGenerator struct {
 	start uint32
+	step  uint32
 	id    atomic.Uint32
 }
 
@@ -20,18 +22,109 @@ type IdGenerator struct {
 // Returns:
 //   - A new IdGenerator instance
 func NewIdGenerator(startValue uint32) *IdGenerator {
+	return NewIdGeneratorWithStep(startValue, 1)
+}
+
+// NewIdGeneratorWithStep creates an IdGenerator whose Id() advances by step, so
+// it returns startValue+step, startValue+2*step, and so on. Generators sharing a
+// step with different start values below the step hand out disjoint IDs, which
+// partitions an ID space across shards: shard k of n uses (k, n). The partition
+// only holds until the counter wraps, unless step is a power of two.
+//
+// Parameters:
+//   - startValue: The value to initialize the counter to
+//   - step: The amount each Id() advances the counter by; 0 is treated as 1
+//
+// Returns:
+//   - A new IdGenerator instance
+func NewIdGeneratorWithStep(startValue, step uint32) *IdGenerator {
+	if step == 0 {
+		step = 1
+	}
+
 	gen := &IdGenerator{
 		start: startValue,
+		step:  step,
 	}
 	gen.id.Store(startValue)
 	return gen
 }
 
-// Id returns the next unique ID by atomically incrementing the internal counter.
-// It is safe for concurrent use by multiple goroutines.
+// Id returns the next unique ID by atomically advancing the internal counter by
+// the step. It is safe for concurrent use by multiple goroutines.
 //
 // Returns:
 //   - The next uint32 ID
 func (l *IdGenerator) Id() uint32 {
-	return l.id.Add(1)
+	return l.id.Add(l.stepOrDefault())
+}
+
+// stepOrDefault returns the step, or 1 for a zero-value IdGenerator.
+func (l *IdGenerator) stepOrDefault() uint32 {
+	if l.step == 0 {
+		return 1
+	}
+
+	return l.step
+}
+
+// Current returns the ID produced by the most recent Id call without consuming
+// one, or the start value if Id has not been called yet. It is safe for
+// concurrent use, but under concurrent Id calls the result may already be stale.
+//
+// Returns:
+//   - The last assigned uint32 ID, or the start value
+func (l *IdGenerator) Current() uint32 {
+	return l.id.Load()
+}
+
+// Peek returns the ID the next Id call would produce, without consuming it.
+// Like Current, it is only a snapshot: a concurrent Id call may take that ID
+// first, so do not use Peek to assign IDs.
+//
+// Returns:
+//   - The uint32 ID the next Id call would return
+func (l *IdGenerator) Peek() uint32 {
+	return l.id.Load() + l.stepOrDefault()
+}
+
+// Reserve atomically claims the next n IDs, for callers that assign IDs locally
+// in bulk. The IDs are start, start+step, ..., end, which is a contiguous range
+// with the default step of 1. Concurrent Reserve and Id calls never return
+// overlapping IDs. If n is 0, nothing is reserved and start is end+step.
+//
+// Like Id, Reserve wraps around at the uint32 limit. A reservation that crosses
+// the wrap has start > end and covers start..math.MaxUint32 followed by 0..end;
+// iterate it by adding the step to a uint32 from start n times rather than
+// comparing against end.
+//
+// Parameters:
+//   - n: The number of IDs to reserve
+//
+// Returns:
+//   - start: The first reserved ID
+//   - end: The last reserved ID (inclusive)
+func (l *IdGenerator) Reserve(n uint32) (start, end uint32) {
+	step := l.stepOrDefault()
+	end = l.id.Add(n * step)
+	return end - n*step + step, end
+}
+
+// Reset returns the counter to the start value given at construction, so the
+// next Id call returns the same ID as the first one did. Like SetCurrent, it
+// should only be called while no other goroutine is calling Id or Reserve.
+func (l *IdGenerator) Reset() {
+	l.id.Store(l.start)
+}
+
+// SetCurrent sets the counter to v, so the next Id call returns v+step, for
+// example to resume from a persisted high-water mark after a restart. The store
+// itself is atomic, but it is not coordinated with concurrent Id or Reserve
+// calls: IDs handed out around the transition may be duplicated or skipped.
+// Call it before the generator is shared, or accept that risk.
+//
+// Parameters:
+//   - v: The value to set the counter to, typically the last ID assigned
+func (l *IdGenerator) SetCurrent(v uint32) {
+	l.id.Store(v)
 }
@@ -120,8 +120,203 @@ func TestIdGenerator_multiple_generators_independent(t *testing.T) {
 	assert.Equal(t, uint32(2), gen2.Id())
 }
 
+func TestIdGenerator_zero_value(t *testing.T) {
+	var gen IdGenerator
+
+	assert.Equal(t, uint32(1), gen.Peek())
+	assert.Equal(t, uint32(1), gen.Id())
+	assert.Equal(t, uint32(2), gen.Id())
+
+	start, end := gen.Reserve(3)
+	assert.Equal(t, uint32(3), start)
+	assert.Equal(t, uint32(5), end)
+}
+
 func TestIdGenerator_reserve_zero(t *testing.T) {
 	// Documented use case: start from 0 so first ID is 1 and 0 can mean "invalid"
 	gen := NewIdGenerator(0)
 	assert.Equal(t, uint32(1), gen.Id(), "first id should be 1 when reserving 0")
 }
+
+func TestIdGenerator_CurrentPeek(t *testing.T) {
+	t.Run("before any Id call", func(t *testing.T) {
+		gen := NewIdGenerator(100)
+		assert.Equal(t, uint32(100), gen.Current())
+		assert.Equal(t, uint32(101), gen.Peek())
+	})
+
+	t.Run("do not consume ids", func(t *testing.T) {
+		gen := NewIdGenerator(0)
+		id := gen.Id()
+		assert.Equal(t, id, gen.Current())
+		assert.Equal(t, id+1, gen.Peek())
+		assert.Equal(t, id+1, gen.Peek())
+		assert.Equal(t, id+1, gen.Id())
+	})
+
+	t.Run("peek wraps at max uint32", func(t *testing.T) {
+		gen := NewIdGenerator(^uint32(0))
+		assert.Equal(t, uint32(0), gen.Peek())
+	})
+
+	t.Run("safe under concurrent Id calls", func(t *testing.T) {
+		gen := NewIdGenerator(0)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				gen.Id()
+			}()
+			go func() {
+				defer wg.Done()
+				_ = gen.Current()
+				_ = gen.Peek()
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, uint32(50), gen.Current())
+	})
+}
+
+func TestIdGenerator_Reserve(t *testing.T) {
+	t.Run("claims a contiguous inclusive range", func(t *testing.T) {
+		gen := NewIdGenerator(0)
+		start, end := gen.Reserve(10)
+		assert.Equal(t, uint32(1), start)
+		assert.Equal(t, uint32(10), end)
+		assert.Equal(t, uint32(11), gen.Id())
+	})
+
+	t.Run("zero reserves nothing", func(t *testing.T) {
+		gen := NewIdGenerator(5)
+		start, end := gen.Reserve(0)
+		assert.Equal(t, uint32(6), start)
+		assert.Equal(t, uint32(5), end)
+		assert.Equal(t, uint32(6), gen.Id())
+	})
+
+	t.Run("wraps at max uint32", func(t *testing.T) {
+		gen := NewIdGenerator(^uint32(0) - 1)
+		start, end := gen.Reserve(4)
+		assert.Equal(t, ^uint32(0), start)
+		assert.Equal(t, uint32(2), end)
+	})
+
+	t.Run("concurrent Reserve and Id never overlap", func(t *testing.T) {
+		gen := NewIdGenerator(0)
+		var mu sync.Mutex
+		seen := make(map[uint32]bool)
+		claim := func(id uint32) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.False(t, seen[id], "duplicate id %d", id)
+			seen[id] = true
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				start, end := gen.Reserve(7)
+				for id := start; id <= end; id++ {
+					claim(id)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				claim(gen.Id())
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, seen, 50*8)
+		assert.Equal(t, uint32(50*8), gen.Current())
+	})
+}
+
+func TestNewIdGeneratorWithStep(t *testing.T) {
+	t.Run("advances by step", func(t *testing.T) {
+		gen := NewIdGeneratorWithStep(1, 3)
+		assert.Equal(t, uint32(4), gen.Id())
+		assert.Equal(t, uint32(7), gen.Id())
+		assert.Equal(t, uint32(10), gen.Peek())
+		assert.Equal(t, uint32(7), gen.Current())
+	})
+
+	t.Run("zero step is treated as one", func(t *testing.T) {
+		gen := NewIdGeneratorWithStep(0, 0)
+		assert.Equal(t, uint32(1), gen.Id())
+		assert.Equal(t, uint32(2), gen.Id())
+	})
+
+	t.Run("reserve follows the step", func(t *testing.T) {
+		gen := NewIdGeneratorWithStep(2, 5)
+		start, end := gen.Reserve(3)
+		assert.Equal(t, uint32(7), start)
+		assert.Equal(t, uint32(17), end)
+		assert.Equal(t, uint32(22), gen.Id())
+
+		start, end = gen.Reserve(0)
+		assert.Equal(t, uint32(27), start)
+		assert.Equal(t, uint32(22), end)
+	})
+
+	t.Run("offset generators with the same step never collide", func(t *testing.T) {
+		const shards = 3
+		gens := make([]*IdGenerator, shards)
+		for k := range gens {
+			gens[k] = NewIdGeneratorWithStep(uint32(k), shards)
+		}
+
+		var mu sync.Mutex
+		seen := make(map[uint32]int)
+		var wg sync.WaitGroup
+		for k, gen := range gens {
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						id := gen.Id()
+						mu.Lock()
+						prev, dup := seen[id]
+						seen[id] = k
+						mu.Unlock()
+						assert.False(t, dup, "id %d from shard %d already issued by shard %d", id, k, prev)
+						assert.Equal(t, uint32(k), id%shards)
+					}
+				}()
+			}
+		}
+		wg.Wait()
+		assert.Len(t, seen, shards*10*100)
+	})
+}
+
+func TestIdGenerator_ResetSetCurrent(t *testing.T) {
+	t.Run("reset returns to the start value", func(t *testing.T) {
+		gen := NewIdGenerator(10)
+		gen.Id()
+		gen.Id()
+		gen.Reset()
+		assert.Equal(t, uint32(10), gen.Current())
+		assert.Equal(t, uint32(11), gen.Id())
+	})
+
+	t.Run("set current resumes from a high-water mark", func(t *testing.T) {
+		gen := NewIdGenerator(0)
+		gen.SetCurrent(5000)
+		assert.Equal(t, uint32(5000), gen.Current())
+		assert.Equal(t, uint32(5001), gen.Id())
+	})
+
+	t.Run("set current honours the step", func(t *testing.T) {
+		gen := NewIdGeneratorWithStep(1, 3)
+		gen.SetCurrent(301)
+		assert.Equal(t, uint32(304), gen.Id())
+
+		gen.Reset()
+		assert.Equal(t, uint32(4), gen.Id())
+	})
+}
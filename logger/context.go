@@ -0,0 +1,37 @@
+package logger
+
+import "context"
+
+// contextKey is the context key under which ContextWithLogger stores a Logger.
+type contextKey struct{}
+
+// ContextWithLogger returns a copy of ctx that carries l, so that code further
+// down the call chain can log with the same fields through FromContext. Store a
+// logger derived with With or StartScope to have its fields, such as a request
+// ID, appear on every entry logged downstream.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - l: The Logger to store
+//
+// Returns:
+//   - A context carrying l
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx by ContextWithLogger. It never
+// returns nil: when ctx carries no logger, it returns a no-op Logger.
+//
+// Parameters:
+//   - ctx: The context to read the logger from
+//
+// Returns:
+//   - The stored Logger, or a Logger from NewNoopLogger
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok && l != nil {
+		return l
+	}
+
+	return NewNoopLogger()
+}
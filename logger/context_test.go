@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWithLogger(t *testing.T) {
+	t.Run("derived fields flow through the stored logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		base := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+		ctx := ContextWithLogger(context.Background(), base.With(Str(RequestIDField, "req-1")))
+
+		FromContext(ctx).Info("handled", Int("status", 200))
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "req-1", entry[RequestIDField])
+		assert.Equal(t, float64(200), entry["status"])
+	})
+
+	t.Run("falls back to a no-op logger", func(t *testing.T) {
+		log := FromContext(context.Background())
+		require.NotNil(t, log)
+		assert.False(t, log.Enabled(zerolog.ErrorLevel))
+		assert.NotPanics(t, func() { log.With(Str("k", "v")).Error("dropped") })
+
+		log = FromContext(ContextWithLogger(context.Background(), nil))
+		assert.NotNil(t, log, "a stored nil logger also falls back")
+	})
+}
+
+func TestNoopLogger(t *testing.T) {
+	log := NewNoopLogger()
+
+	assert.NotPanics(t, func() {
+		log.Debug("d")
+		log.Info("i", Str("k", "v"))
+		log.Warn("w")
+		log.Error("e", Err(assert.AnError))
+		log.Log(zerolog.WarnLevel, "l")
+	})
+	assert.False(t, log.Enabled(zerolog.TraceLevel))
+	assert.False(t, log.Enabled(zerolog.PanicLevel))
+	assert.Equal(t, log, log.With(Str("k", "v")))
+	assert.Equal(t, log, log.WithCaller())
+	assert.Equal(t, log, log.WithError(assert.AnError))
+	assert.Nil(t, log.GetLoggerInstance())
+	assert.NoError(t, log.Close())
+
+	t.Run("fatal still exits and panic still panics", func(t *testing.T) {
+		code := -1
+		defer func(orig func(int)) { exit = orig }(exit)
+		exit = func(c int) { code = c }
+
+		log.Fatal("fatal")
+		assert.Equal(t, 1, code)
+		assert.PanicsWithValue(t, "panic", func() { log.Panic("panic") })
+	})
+}
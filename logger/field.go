@@ -0,0 +1,49 @@
+package logger
+
+import "time"
+
+// ErrorField is the field key under which Err logs errors.
+const ErrorField = "error"
+
+// Str returns a Field with a string value.
+func Str(key, val string) Field {
+	return Field{Key: key, Value: val}
+}
+
+// Int returns a Field with an int value.
+func Int(key string, val int) Field {
+	return Field{Key: key, Value: val}
+}
+
+// Int64 returns a Field with an int64 value.
+func Int64(key string, val int64) Field {
+	return Field{Key: key, Value: val}
+}
+
+// Float64 returns a Field with a float64 value.
+func Float64(key string, val float64) Field {
+	return Field{Key: key, Value: val}
+}
+
+// Bool returns a Field with a bool value.
+func Bool(key string, val bool) Field {
+	return Field{Key: key, Value: val}
+}
+
+// Err returns a Field that logs err under the ErrorField key. The zerolog
+// logger writes the error's message, or null for a nil error.
+func Err(err error) Field {
+	return Field{Key: ErrorField, Value: err}
+}
+
+// Dur returns a Field with a duration value. The zerolog logger writes
+// durations as a number in zerolog.DurationFieldUnit, milliseconds by default.
+func Dur(key string, d time.Duration) Field {
+	return Field{Key: key, Value: d}
+}
+
+// Any returns a Field with an arbitrary value, for types without a dedicated
+// constructor. It is equivalent to Field{Key: key, Value: val}.
+func Any(key string, val any) Field {
+	return Field{Key: key, Value: val}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldConstructors(t *testing.T) {
+	err := errors.New("boom")
+
+	assert.Equal(t, Field{Key: "name", Value: "alice"}, Str("name", "alice"))
+	assert.Equal(t, Field{Key: "count", Value: 3}, Int("count", 3))
+	assert.Equal(t, Field{Key: "id", Value: int64(42)}, Int64("id", 42))
+	assert.Equal(t, Field{Key: "ratio", Value: 0.5}, Float64("ratio", 0.5))
+	assert.Equal(t, Field{Key: "ok", Value: true}, Bool("ok", true))
+	assert.Equal(t, Field{Key: "error", Value: err}, Err(err))
+	assert.Equal(t, Field{Key: "took", Value: time.Second}, Dur("took", time.Second))
+	assert.Equal(t, Field{Key: "tags", Value: []string{"a"}}, Any("tags", []string{"a"}))
+}
+
+func TestFieldConstructors_Output(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+
+	log.Error("request failed",
+		Str("path", "/users"),
+		Int("status", 502),
+		Bool("retry", false),
+		Dur("took", 1500*time.Millisecond),
+		Err(errors.New("upstream timeout")),
+	)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "/users", entry["path"])
+	assert.Equal(t, float64(502), entry["status"])
+	assert.Equal(t, false, entry["retry"])
+	assert.Equal(t, float64(1500), entry["took"])
+	assert.Equal(t, "upstream timeout", entry["error"])
+}
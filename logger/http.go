@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// RequestIDHeader is the request header whose value HTTPMiddleware logs as request_id.
+const RequestIDHeader = "X-Request-ID"
+
+// statusRecorder is an http.ResponseWriter that remembers the status code and
+// the number of body bytes written.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher when the wrapped writer does, so streaming
+// handlers keep working behind the middleware.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for use by http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// HTTPMiddleware returns net/http middleware that logs one entry per request
+// after the wrapped handler returns, with the fields method, path, status,
+// duration, bytes, and request_id (from the X-Request-ID header, if present).
+// Requests answered with a 5xx status are logged at error level, all others at
+// info level. A handler that writes nothing is logged with status 200.
+//
+// Parameters:
+//   - log: The Logger to write request entries to
+//
+// Returns:
+//   - A function that wraps an http.Handler with request logging
+func HTTPMiddleware(log Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(rec, r)
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			fields := []Field{
+				{Key: "method", Value: r.Method},
+				{Key: "path", Value: r.URL.Path},
+				{Key: "status", Value: status},
+				{Key: "duration", Value: time.Since(start)},
+				{Key: "bytes", Value: rec.bytes},
+			}
+			if id := r.Header.Get(RequestIDHeader); id != "" {
+				fields = append(fields, Field{Key: RequestIDField, Value: id})
+			}
+
+			level := zerolog.InfoLevel
+			if status >= http.StatusInternalServerError {
+				level = zerolog.ErrorLevel
+			}
+
+			log.Log(level, "http request", fields...)
+		})
+	}
+}
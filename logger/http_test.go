@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	serve := func(t *testing.T, handler http.HandlerFunc, req *http.Request) (*httptest.ResponseRecorder, map[string]any) {
+		t.Helper()
+
+		var buf bytes.Buffer
+		log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+
+		rec := httptest.NewRecorder()
+		HTTPMiddleware(log)(handler).ServeHTTP(rec, req)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		return rec, entry
+	}
+
+	t.Run("logs status, duration and request fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users?page=2", nil)
+		req.Header.Set(RequestIDHeader, "req-123")
+
+		rec, entry := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(5 * time.Millisecond)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("created"))
+		}, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "info", entry["level"])
+		assert.Equal(t, "http request", entry["message"])
+		assert.Equal(t, "POST", entry["method"])
+		assert.Equal(t, "/users", entry["path"])
+		assert.Equal(t, float64(http.StatusCreated), entry["status"])
+		assert.Equal(t, float64(len("created")), entry["bytes"])
+		assert.Equal(t, "req-123", entry["request_id"])
+		require.Contains(t, entry, "duration")
+		assert.GreaterOrEqual(t, entry["duration"].(float64), float64(5))
+	})
+
+	t.Run("implicit 200 when the handler writes nothing", func(t *testing.T) {
+		_, entry := serve(t, func(w http.ResponseWriter, r *http.Request) {}, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, float64(http.StatusOK), entry["status"])
+		assert.NotContains(t, entry, "request_id")
+	})
+
+	t.Run("server errors log at error level", func(t *testing.T) {
+		_, entry := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+		assert.Equal(t, "error", entry["level"])
+		assert.Equal(t, float64(http.StatusInternalServerError), entry["status"])
+	})
+}
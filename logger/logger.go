@@ -4,10 +4,16 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,7 +29,7 @@ type Field struct {
 }
 
 // Logger is an interface for structured logging. Implementations write log
-// entries at different levels (Debug, Info, Warn, Error) and support
+// entries at different levels (Debug, Info, Warn, Error, Fatal, Panic) and support
 // attaching structured fields. Loggers may be derived with With for
 // request-scoped or component-scoped fields.
 type Logger interface {
@@ -55,6 +61,41 @@ type Logger interface {
 	//   - fields: Optional key-value pairs to include in the log entry
 	Error(msg string, fields ...Field)
 
+	// Fatal logs a message at fatal level with optional structured fields,
+	// flushes and closes any log file, and exits the process with status 1.
+	//
+	// Parameters:
+	//   - msg: The log message
+	//   - fields: Optional key-value pairs to include in the log entry
+	Fatal(msg string, fields ...Field)
+
+	// Panic logs a message at panic level with optional structured fields,
+	// flushes any log file, and then panics with msg.
+	//
+	// Parameters:
+	//   - msg: The log message
+	//   - fields: Optional key-value pairs to include in the log entry
+	Panic(msg string, fields ...Field)
+
+	// Log logs a message at the given level with optional structured fields.
+	// Use it when the level is only known at runtime.
+	//
+	// Parameters:
+	//   - level: The level to log at (e.g. zerolog.WarnLevel)
+	//   - msg: The log message
+	//   - fields: Optional key-value pairs to include in the log entry
+	Log(level zerolog.Level, msg string, fields ...Field)
+
+	// Enabled reports whether entries at the given level would be written.
+	// Use it to skip building expensive fields for disabled levels.
+	//
+	// Parameters:
+	//   - level: The level to check (e.g. zerolog.DebugLevel)
+	//
+	// Returns:
+	//   - true if an entry at level would be written
+	Enabled(level zerolog.Level) bool
+
 	// With returns a new Logger that includes the given fields in all
 	// subsequent log entries. The original Logger is unchanged.
 	//
@@ -65,6 +106,25 @@ type Logger interface {
 	//   - A new Logger with the specified fields
 	With(fields ...Field) Logger
 
+	// WithError returns a new Logger that includes err in all subsequent log
+	// entries, as its message under "error" and its concrete type (e.g.
+	// "*fs.PathError") under "error_type". If err is nil, no fields are added.
+	//
+	// Parameters:
+	//   - err: The error to attach to the derived logger
+	//
+	// Returns:
+	//   - A new Logger with the error fields
+	WithError(err error) Logger
+
+	// WithCaller returns a new Logger that adds the file and line of the code
+	// that made the logging call to all subsequent log entries, under the
+	// "caller" key. The original Logger is unchanged.
+	//
+	// Returns:
+	//   - A new Logger that records the caller of each entry
+	WithCaller() Logger
+
 	// GetLoggerInstance returns the underlying logger implementation (e.g.
 	// zerolog.Logger) for advanced configuration or integration.
 	//
@@ -80,11 +140,52 @@ type Logger interface {
 	Close() error
 }
 
+// exit terminates the process; tests replace it to observe Fatal.
+var exit = os.Exit
+
 // zerologLogger is the zerolog-based implementation of Logger.
 type zerologLogger struct {
 	logger         zerolog.Logger
 	fileWriter     *DailyFileWriter
 	ownsFileWriter bool
+	errorHook      ErrorHook
+	hookFields     []Field // fields added with With, passed to errorHook
+}
+
+// ErrorHook is called with the message and fields of each entry logged at
+// error level or above. The fields include those added with With.
+type ErrorHook func(msg string, fields []Field)
+
+// ZerologLoggerOption configures optional behavior of the zerolog-based
+// loggers.
+type ZerologLoggerOption func(*zerologLogger)
+
+// WithErrorHook calls hook for every entry written at error level or above:
+// Error and Log at error level run it in a new goroutine so logging stays fast,
+// while Fatal and Panic run it before exiting or panicking, since the process
+// may not survive to run it later. Entries filtered out by the logger's level
+// do not trigger the hook. Loggers derived with With and WithCaller keep it.
+// Use it to mirror errors to an alerting channel, e.g. with
+// utils.SendDiscordNotification.
+//
+// Parameters:
+//   - hook: The function to call; nil disables the hook
+//
+// Returns:
+//   - A ZerologLoggerOption to pass to the NewZerolog* constructors
+func WithErrorHook(hook ErrorHook) ZerologLoggerOption {
+	return func(z *zerologLogger) {
+		z.errorHook = hook
+	}
+}
+
+// applyOptions applies opts to z and returns it.
+func (z *zerologLogger) applyOptions(opts []ZerologLoggerOption) *zerologLogger {
+	for _, opt := range opts {
+		opt(z)
+	}
+
+	return z
 }
 
 // NewZerologLogger builds a Logger that wraps the given zerolog.Logger,
@@ -95,14 +196,32 @@ type zerologLogger struct {
 //   - l: The zerolog.Logger to wrap
 //   - serviceName: Name of the service, added as a field to every log entry
 //   - level: Minimum level to log (e.g. zerolog.InfoLevel)
+//   - opts: Optional settings such as WithErrorHook
 //
 // Returns:
 //   - A Logger that writes through the given zerolog instance
-func NewZerologLogger(l zerolog.Logger, serviceName string, level zerolog.Level) Logger {
-	return &zerologLogger{
+func NewZerologLogger(l zerolog.Logger, serviceName string, level zerolog.Level, opts ...ZerologLoggerOption) Logger {
+	z := &zerologLogger{
 		logger:         l.With().Str("service", serviceName).Timestamp().Logger().Level(level),
 		ownsFileWriter: false,
 	}
+
+	return z.applyOptions(opts)
+}
+
+// NewZerologConsoleLogger creates a Logger that writes human-readable, colored lines
+// to stdout through zerolog.ConsoleWriter, for local development. Entries carry the
+// same service name and timestamp fields as the JSON loggers. No file is created.
+//
+// Parameters:
+//   - serviceName: Name of the service, added as a field to every log entry
+//   - level: Minimum level to log (e.g. zerolog.InfoLevel)
+//   - opts: Optional settings such as WithErrorHook
+//
+// Returns:
+//   - A Logger that writes formatted lines to stdout
+func NewZerologConsoleLogger(serviceName string, level zerolog.Level, opts ...ZerologLoggerOption) Logger {
+	return NewZerologLogger(zerolog.New(newConsoleWriter(os.Stdout)), serviceName, level, opts...)
 }
 
 // NewZerologFileLogger creates a Logger that writes to both stdout and
@@ -113,10 +232,33 @@ func NewZerologLogger(l zerolog.Logger, serviceName string, level zerolog.Level)
 //   - serviceName: Name of the service, used in log entries and file names
 //   - logDir: Directory for log files; created if it does not exist
 //   - level: Minimum level to log (e.g. zerolog.InfoLevel)
+//   - opts: Optional settings such as WithErrorHook
 //
 // Returns:
 //   - A Logger that writes to stdout and rotating files
-func NewZerologFileLogger(serviceName string, logDir string, level zerolog.Level) Logger {
+func NewZerologFileLogger(serviceName string, logDir string, level zerolog.Level, opts ...ZerologLoggerOption) Logger {
+	return newZerologFileLogger(serviceName, logDir, level, os.Stdout, opts...)
+}
+
+// NewZerologConsoleFileLogger is like NewZerologFileLogger, but writes human-readable,
+// colored lines to stdout as NewZerologConsoleLogger does. The log files stay JSON
+// for machine parsing. Panics under the same conditions as NewZerologFileLogger.
+//
+// Parameters:
+//   - serviceName: Name of the service, used in log entries and file names
+//   - logDir: Directory for log files; created if it does not exist
+//   - level: Minimum level to log (e.g. zerolog.InfoLevel)
+//   - opts: Optional settings such as WithErrorHook
+//
+// Returns:
+//   - A Logger that writes formatted lines to stdout and JSON to rotating files
+func NewZerologConsoleFileLogger(serviceName string, logDir string, level zerolog.Level, opts ...ZerologLoggerOption) Logger {
+	return newZerologFileLogger(serviceName, logDir, level, newConsoleWriter(os.Stdout), opts...)
+}
+
+// newZerologFileLogger creates a Logger that writes to stdout and to daily-rotated
+// log files in logDir.
+func newZerologFileLogger(serviceName string, logDir string, level zerolog.Level, stdout io.Writer, opts ...ZerologLoggerOption) Logger {
 	err := os.MkdirAll(logDir, 0755)
 	if err != nil {
 		panic(fmt.Errorf("failed to create log directory: %w", err))
@@ -127,12 +269,19 @@ func NewZerologFileLogger(serviceName string, logDir string, level zerolog.Level
 		panic(fmt.Errorf("failed to create file writer: %w", err))
 	}
 
-	multi := io.MultiWriter(os.Stdout, fileWriter)
-	return &zerologLogger{
+	multi := io.MultiWriter(stdout, fileWriter)
+	z := &zerologLogger{
 		logger:         zerolog.New(multi).With().Str("service", serviceName).Timestamp().Logger().Level(level),
 		fileWriter:     fileWriter,
 		ownsFileWriter: true,
 	}
+
+	return z.applyOptions(opts)
+}
+
+// newConsoleWriter returns a zerolog.ConsoleWriter that formats entries written to out.
+func newConsoleWriter(out io.Writer) zerolog.ConsoleWriter {
+	return zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
 }
 
 // Debug implements Logger.
@@ -153,14 +302,108 @@ func (z *zerologLogger) Warn(msg string, fields ...Field) {
 // Error implements Logger.
 func (z *zerologLogger) Error(msg string, fields ...Field) {
 	z.logger.Error().Fields(toMap(fields)).Msg(msg)
+	z.runErrorHook(zerolog.ErrorLevel, msg, fields, false)
+}
+
+// Fatal implements Logger. zerolog's own Fatal exits before file output can be
+// closed, so the entry is written with WithLevel and the exit happens here.
+func (z *zerologLogger) Fatal(msg string, fields ...Field) {
+	z.logger.WithLevel(zerolog.FatalLevel).Fields(toMap(fields)).Msg(msg)
+	z.runErrorHook(zerolog.FatalLevel, msg, fields, true)
+
+	if z.fileWriter != nil {
+		_ = z.fileWriter.Sync()
+		_ = z.fileWriter.Close()
+	}
+
+	exit(1)
+}
+
+// Panic implements Logger.
+func (z *zerologLogger) Panic(msg string, fields ...Field) {
+	z.logger.WithLevel(zerolog.PanicLevel).Fields(toMap(fields)).Msg(msg)
+	z.runErrorHook(zerolog.PanicLevel, msg, fields, true)
+	_ = z.Sync()
+
+	panic(msg)
+}
+
+// Log implements Logger. Fatal and panic levels are written at that level
+// but, unlike zerolog's Fatal and Panic, do not exit or panic.
+func (z *zerologLogger) Log(level zerolog.Level, msg string, fields ...Field) {
+	z.logger.WithLevel(level).Fields(toMap(fields)).Msg(msg)
+	z.runErrorHook(level, msg, fields, false)
+}
+
+// runErrorHook calls the error hook for an entry at level, if the entry is at
+// error level or above and was written. The hook gets its own copy of the
+// fields, after those added with With. Unless wait is set it runs in a new
+// goroutine.
+func (z *zerologLogger) runErrorHook(level zerolog.Level, msg string, fields []Field, wait bool) {
+	if z.errorHook == nil || level < zerolog.ErrorLevel || level > zerolog.PanicLevel || !z.Enabled(level) {
+		return
+	}
+
+	all := make([]Field, 0, len(z.hookFields)+len(fields))
+	all = append(all, z.hookFields...)
+	all = append(all, fields...)
+
+	if wait {
+		z.errorHook(msg, all)
+		return
+	}
+
+	go z.errorHook(msg, all)
+}
+
+// Enabled implements Logger. It applies both the logger's level and zerolog's
+// global level, as zerolog does when writing an entry.
+func (z *zerologLogger) Enabled(level zerolog.Level) bool {
+	if level == zerolog.Disabled {
+		return false
+	}
+
+	return level >= z.logger.GetLevel() && level >= zerolog.GlobalLevel()
 }
 
 // With implements Logger.
 func (z *zerologLogger) With(fields ...Field) Logger {
-	return &zerologLogger{
+	derived := &zerologLogger{
 		logger:         z.logger.With().Fields(toMap(fields)).Logger(),
 		fileWriter:     z.fileWriter,
 		ownsFileWriter: false,
+		errorHook:      z.errorHook,
+		hookFields:     z.hookFields,
+	}
+
+	if z.errorHook != nil && len(fields) > 0 {
+		derived.hookFields = append(slices.Clip(z.hookFields), fields...)
+	}
+
+	return derived
+}
+
+// WithError implements Logger.
+func (z *zerologLogger) WithError(err error) Logger {
+	if err == nil {
+		return z.With()
+	}
+
+	return z.With(
+		Field{Key: "error", Value: err.Error()},
+		Field{Key: "error_type", Value: fmt.Sprintf("%T", err)},
+	)
+}
+
+// WithCaller implements Logger. Each Logger method adds one stack frame between
+// the caller and zerolog, so one more frame than zerolog's default is skipped.
+func (z *zerologLogger) WithCaller() Logger {
+	return &zerologLogger{
+		logger:         z.logger.With().CallerWithSkipFrameCount(zerolog.CallerSkipFrameCount + 1).Logger(),
+		fileWriter:     z.fileWriter,
+		ownsFileWriter: false,
+		errorHook:      z.errorHook,
+		hookFields:     z.hookFields,
 	}
 }
 
@@ -192,16 +435,43 @@ func (z *zerologLogger) Close() error {
 	return nil
 }
 
+// Sync flushes the log file to stable storage when the logger writes to files.
+// It is a no-op for console-only loggers.
+//
+// Returns:
+//   - An error if syncing the log file fails
+func (z *zerologLogger) Sync() error {
+	if z.fileWriter != nil {
+		return z.fileWriter.Sync()
+	}
+
+	return nil
+}
+
 // DailyFileWriter is an io.Writer that writes to a log file that rotates
 // daily. File names are {service}_{date}.log. Rotation happens automatically
-// at midnight and on the first write of a new day; a background goroutine
-// also checks hourly. Safe for concurrent use.
+// at midnight, or at the time of day set with WithRotateAt, and on the first
+// write of a new day; a background goroutine also checks hourly. With WithMaxSizeBytes, files are also rotated by size
+// to {service}_{date}.1.log, {service}_{date}.2.log and so on. With
+// WithMaxAgeDays or WithMaxBackups, old files are removed after each rotation.
+// Safe for concurrent use.
 type DailyFileWriter struct {
 	service    string
 	dir        string
+	maxSize    int64
+	maxAgeDays int
+	maxBackups int
+	rotateAt   time.Duration  // offset of the daily rotation from midnight
+	loc        *time.Location // zone of file dates and rotation; nil means time.Local
+	cleanMu    sync.Mutex     // guards cleaning, cleanAgain, and cleanKeep
+	cleaning   bool           // whether a cleanup goroutine is running
+	cleanAgain bool           // whether a rotation asked for another pass meanwhile
+	cleanKeep  string         // current file as of the latest rotation; never removed
 	mu         sync.RWMutex
 	file       *os.File
 	currDate   string
+	currSeq    int
+	size       atomic.Int64 // bytes in the current file
 	ctx        context.Context
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
@@ -209,6 +479,93 @@ type DailyFileWriter struct {
 	lastRotate time.Time
 }
 
+// DailyFileWriterOption configures optional behavior of a DailyFileWriter.
+type DailyFileWriterOption func(*DailyFileWriter)
+
+// WithMaxSizeBytes rotates the log file once writing to it would take it past
+// maxSizeBytes, in addition to the daily rotation. Size-rotated files get a
+// numeric suffix: {service}_{date}.1.log, {service}_{date}.2.log, ... A single
+// write larger than the limit still goes to one file. Zero or a negative value
+// disables size-based rotation.
+//
+// Parameters:
+//   - maxSizeBytes: Maximum size of a log file in bytes
+//
+// Returns:
+//   - A DailyFileWriterOption to pass to NewDailyFileWriterWithOptions
+func WithMaxSizeBytes(maxSizeBytes int64) DailyFileWriterOption {
+	return func(w *DailyFileWriter) {
+		w.maxSize = maxSizeBytes
+	}
+}
+
+// WithMaxAgeDays removes the service's log files last modified more than days
+// days ago. Removal runs in the background after each rotation, including the
+// one that opens the first file. Zero or a negative value keeps files forever.
+//
+// Parameters:
+//   - days: Maximum age of a log file in days
+//
+// Returns:
+//   - A DailyFileWriterOption to pass to NewDailyFileWriterWithOptions
+func WithMaxAgeDays(days int) DailyFileWriterOption {
+	return func(w *DailyFileWriter) {
+		w.maxAgeDays = days
+	}
+}
+
+// WithMaxBackups keeps at most n of the service's log files besides the one
+// being written to, removing the least recently modified ones in the
+// background after each rotation. Zero or a negative value keeps all files.
+//
+// Parameters:
+//   - n: Maximum number of old log files to keep
+//
+// Returns:
+//   - A DailyFileWriterOption to pass to NewDailyFileWriterWithOptions
+func WithMaxBackups(n int) DailyFileWriterOption {
+	return func(w *DailyFileWriter) {
+		w.maxBackups = n
+	}
+}
+
+// WithRotateAt moves the daily rotation from midnight to the given offset into
+// the day, e.g. 18*time.Hour to roll over at 18:00 local time. Each file is
+// named after the date on which its day began, so with an 18:00 rotation the
+// entries written between 18:00 on 2 January and 18:00 on 3 January go to the
+// 2 January file. The time of day is local unless WithLocation is used.
+// Offsets outside [0, 24h) are taken modulo 24 hours.
+//
+// Parameters:
+//   - offset: Time of day at which to rotate, as a duration since midnight
+//
+// Returns:
+//   - A DailyFileWriterOption to pass to NewDailyFileWriterWithOptions
+func WithRotateAt(offset time.Duration) DailyFileWriterOption {
+	return func(w *DailyFileWriter) {
+		offset %= 24 * time.Hour
+		if offset < 0 {
+			offset += 24 * time.Hour
+		}
+		w.rotateAt = offset
+	}
+}
+
+// WithLocation makes file dates and the daily rotation follow the given time
+// zone instead of the local one, so that processes running in different zones
+// name files the same way for the same instant. A nil loc means time.Local.
+//
+// Parameters:
+//   - loc: The time zone to use, e.g. time.UTC
+//
+// Returns:
+//   - A DailyFileWriterOption to pass to NewDailyFileWriterWithOptions
+func WithLocation(loc *time.Location) DailyFileWriterOption {
+	return func(w *DailyFileWriter) {
+		w.loc = loc
+	}
+}
+
 // NewDailyFileWriter creates a DailyFileWriter that writes to the given
 // directory with files named {service}_{date}.log. The directory is not
 // created by this function; callers must ensure it exists.
@@ -220,6 +577,23 @@ type DailyFileWriter struct {
 // Returns:
 //   - The new DailyFileWriter, or an error if the initial file could not be opened
 func NewDailyFileWriter(service string, logDir string) (*DailyFileWriter, error) {
+	return NewDailyFileWriterWithOptions(service, logDir)
+}
+
+// NewDailyFileWriterWithOptions is like NewDailyFileWriter but accepts options
+// such as WithMaxSizeBytes, WithMaxAgeDays, WithMaxBackups, WithRotateAt and
+// WithLocation. When size-based rotation is enabled and files for
+// the current date already exist, writing resumes in the one with the highest
+// suffix.
+//
+// Parameters:
+//   - service: Service name used in log file names
+//   - logDir: Directory path for log files
+//   - opts: Optional configuration
+//
+// Returns:
+//   - The new DailyFileWriter, or an error if the initial file could not be opened
+func NewDailyFileWriterWithOptions(service string, logDir string, opts ...DailyFileWriterOption) (*DailyFileWriter, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	w := &DailyFileWriter{
 		service: service,
@@ -228,6 +602,10 @@ func NewDailyFileWriter(service string, logDir string) (*DailyFileWriter, error)
 		cancel:  cancel,
 	}
 
+	for _, opt := range opts {
+		opt(w)
+	}
+
 	if err := w.rotate(); err != nil {
 		cancel()
 		return nil, fmt.Errorf("initial rotation failed: %w", err)
@@ -238,8 +616,8 @@ func NewDailyFileWriter(service string, logDir string) (*DailyFileWriter, error)
 	return w, nil
 }
 
-// Close stops the background rotator and closes the current log file.
-// Subsequent writes return an error. It is safe to call multiple times.
+// Close stops the background rotator, closes the current log file, and waits
+// for any removal of old files to finish. Subsequent writes return an error. It is safe to call multiple times.
 //
 // Returns:
 //   - An error if closing the file fails
@@ -249,43 +627,86 @@ func (w *DailyFileWriter) Close() error {
 	}
 
 	w.cancel()
-	w.wg.Wait()
 
+	// Rotations check closed under mu, so once the file is closed none can
+	// start another cleanup, and waiting covers any already running
 	w.mu.Lock()
-	defer w.mu.Unlock()
-
+	var err error
 	if w.file != nil {
-		err := w.file.Close()
+		err = w.file.Close()
 		w.file = nil
-		return err
 	}
+	w.mu.Unlock()
 
-	return nil
+	w.wg.Wait()
+	return err
 }
 
-// autoRotate runs in a goroutine and performs hourly rotation checks.
+// autoRotate runs in a goroutine and rotates at each day boundary. An hourly
+// check also catches boundaries missed, for example after the system sleeps
+// or the clock changes.
 func (w *DailyFileWriter) autoRotate() {
 	defer w.wg.Done()
 
 	ticker := time.NewTicker(time.Hour)
 	defer ticker.Stop()
 
+	boundary := time.NewTimer(time.Until(w.nextBoundary(time.Now())))
+	defer boundary.Stop()
+
 	for {
 		select {
 		case <-w.ctx.Done():
 			return
 		case <-ticker.C:
-			if atomic.LoadInt32(&w.closed) == 1 {
-				return
-			}
+		case <-boundary.C:
+			boundary.Reset(time.Until(w.nextBoundary(time.Now())))
+		}
 
-			w.mu.Lock()
-			_ = w.rotateInternal()
-			w.mu.Unlock()
+		if atomic.LoadInt32(&w.closed) == 1 {
+			return
 		}
+
+		w.mu.Lock()
+		_ = w.rotateInternal()
+		w.mu.Unlock()
 	}
 }
 
+// dayStart returns the start of the rotation day containing t: the rotation
+// time of day in the writer's zone, on t's date or the day before. The boundary
+// is built from wall-clock fields rather than by adding the offset to
+// midnight, so it stays put on days with a DST transition.
+func (w *DailyFileWriter) dayStart(t time.Time) time.Time {
+	loc := w.loc
+	if loc == nil {
+		loc = time.Local
+	}
+
+	t = t.In(loc)
+	y, m, d := t.Date()
+	h, rem := w.rotateAt/time.Hour, w.rotateAt%time.Hour
+	mins, rem := rem/time.Minute, rem%time.Minute
+	secs, nsec := rem/time.Second, rem%time.Second
+	start := time.Date(y, m, d, int(h), int(mins), int(secs), int(nsec), loc)
+	if t.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+
+	return start
+}
+
+// dateOf returns the date used in the name of the file that entries written
+// at t belong to.
+func (w *DailyFileWriter) dateOf(t time.Time) string {
+	return w.dayStart(t).Format("2006-01-02")
+}
+
+// nextBoundary returns the first rotation time after t.
+func (w *DailyFileWriter) nextBoundary(t time.Time) time.Time {
+	return w.dayStart(t).AddDate(0, 0, 1)
+}
+
 // rotate switches to a new log file if the date has changed. It is safe to call concurrently.
 //
 // Returns:
@@ -303,32 +724,217 @@ func (w *DailyFileWriter) rotateInternal() error {
 	}
 
 	now := time.Now()
-	date := now.Format("2006-01-02")
+	date := w.dateOf(now)
 
 	if date == w.currDate && w.file != nil &&
 		now.Sub(w.lastRotate) < time.Minute {
 		return nil
 	}
 
+	seq := 0
+	if w.maxSize > 0 {
+		seq = w.latestSeq(date)
+	}
+
+	return w.openInternal(date, seq, now)
+}
+
+// rotateSizeInternal switches to the next numbered file for the current date;
+// caller must hold w.mu.
+func (w *DailyFileWriter) rotateSizeInternal() error {
+	if atomic.LoadInt32(&w.closed) == 1 {
+		return fmt.Errorf("writer is closed")
+	}
+
+	return w.openInternal(w.currDate, w.currSeq+1, time.Now())
+}
+
+// openInternal closes the current file and opens the file for date and seq,
+// appending if it already exists; caller must hold w.mu.
+func (w *DailyFileWriter) openInternal(date string, seq int, now time.Time) error {
 	if w.file != nil {
 		_ = w.file.Close()
 		w.file = nil
 	}
 
-	filename := filepath.Join(w.dir, fmt.Sprintf("%s_%s.log", w.service, date))
+	filename := w.fileName(date, seq)
 	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to open log file %s: %w", filename, err)
 	}
 
+	var size int64
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+
 	w.file = file
 	w.currDate = date
+	w.currSeq = seq
+	w.size.Store(size)
 	w.lastRotate = now
+
+	if w.maxAgeDays > 0 || w.maxBackups > 0 {
+		w.startCleanup(filename)
+	}
+
 	return nil
 }
 
+// startCleanup removes old log files in the background, keeping current. Only
+// one cleanup runs at a time; a request made while one runs gets another pass
+// once it finishes, so files left by that rotation are not missed.
+func (w *DailyFileWriter) startCleanup(current string) {
+	w.cleanMu.Lock()
+	defer w.cleanMu.Unlock()
+
+	w.cleanKeep = current
+	if w.cleaning {
+		w.cleanAgain = true
+		return
+	}
+
+	w.cleaning = true
+	w.wg.Add(1)
+	go w.cleanupLoop()
+}
+
+// cleanupLoop runs cleanup passes until no further pass has been requested.
+func (w *DailyFileWriter) cleanupLoop() {
+	defer w.wg.Done()
+
+	for {
+		w.cleanMu.Lock()
+		current := w.cleanKeep
+		w.cleanAgain = false
+		w.cleanMu.Unlock()
+
+		w.cleanup(current)
+
+		w.cleanMu.Lock()
+		if !w.cleanAgain {
+			w.cleaning = false
+			w.cleanMu.Unlock()
+			return
+		}
+		w.cleanMu.Unlock()
+	}
+}
+
+// cleanup removes the service's log files that are older than maxAgeDays or
+// beyond the newest maxBackups, never touching current. Failures are reported
+// on stderr and do not affect the writer.
+func (w *DailyFileWriter) cleanup(current string) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: failed to list log files in %s: %v\n", w.dir, err)
+		return
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+
+	var files []logFile
+	for _, entry := range entries {
+		path := filepath.Join(w.dir, entry.Name())
+		if entry.IsDir() || path == current || !w.isLogFile(entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, logFile{path: path, modTime: info.ModTime()})
+	}
+
+	// Newest first, so the backups to keep come first
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	cutoff := time.Now().AddDate(0, 0, -w.maxAgeDays)
+	for i, f := range files {
+		expired := w.maxAgeDays > 0 && f.modTime.Before(cutoff)
+		excess := w.maxBackups > 0 && i >= w.maxBackups
+		if !expired && !excess {
+			continue
+		}
+
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "logger: failed to remove old log file %s: %v\n", f.path, err)
+		}
+	}
+}
+
+// isLogFile reports whether name follows this writer's naming pattern,
+// {service}_{date}.log or {service}_{date}.{n}.log.
+func (w *DailyFileWriter) isLogFile(name string) bool {
+	rest, ok := strings.CutPrefix(name, w.service+"_")
+	if !ok {
+		return false
+	}
+
+	rest, ok = strings.CutSuffix(rest, ".log")
+	if !ok || len(rest) < len("2006-01-02") {
+		return false
+	}
+
+	if _, err := time.Parse("2006-01-02", rest[:len("2006-01-02")]); err != nil {
+		return false
+	}
+
+	seq := rest[len("2006-01-02"):]
+	if seq == "" {
+		return true
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(seq, "."))
+	return strings.HasPrefix(seq, ".") && err == nil && n > 0
+}
+
+// fileName returns the path of the log file for date and seq. Sequence 0 is
+// the unsuffixed {service}_{date}.log.
+func (w *DailyFileWriter) fileName(date string, seq int) string {
+	if seq == 0 {
+		return filepath.Join(w.dir, fmt.Sprintf("%s_%s.log", w.service, date))
+	}
+
+	return filepath.Join(w.dir, fmt.Sprintf("%s_%s.%d.log", w.service, date, seq))
+}
+
+// latestSeq returns the highest suffix among existing log files for date, or 0
+// if there are none or the directory cannot be read.
+func (w *DailyFileWriter) latestSeq(date string) int {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return 0
+	}
+
+	prefix := fmt.Sprintf("%s_%s.", w.service, date)
+	latest := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+
+		seq, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".log"))
+		if err == nil && seq > latest {
+			latest = seq
+		}
+	}
+
+	return latest
+}
+
 // Write implements io.Writer. It rotates to a new file when the date changes
-// and writes p to the current log file.
+// or, with WithMaxSizeBytes, when p would take the current file past the size
+// limit, and writes p to the current log file. Concurrent writes may push a
+// file slightly past the limit.
 //
 // Returns:
 //   - The number of bytes written and an error if the writer is closed or write fails
@@ -338,17 +944,21 @@ func (w *DailyFileWriter) Write(p []byte) (int, error) {
 	}
 
 	w.mu.RLock()
-	needsRotation := w.needsRotation()
+	needsRotation := w.needsRotation() || w.exceedsSize(len(p))
 	currentFile := w.file
 	w.mu.RUnlock()
 
 	if needsRotation {
 		w.mu.Lock()
+		var err error
 		if w.needsRotation() {
-			if err := w.rotateInternal(); err != nil {
-				w.mu.Unlock()
-				return 0, fmt.Errorf("rotation failed: %w", err)
-			}
+			err = w.rotateInternal()
+		} else if w.exceedsSize(len(p)) {
+			err = w.rotateSizeInternal()
+		}
+		if err != nil {
+			w.mu.Unlock()
+			return 0, fmt.Errorf("rotation failed: %w", err)
 		}
 
 		currentFile = w.file
@@ -366,7 +976,25 @@ func (w *DailyFileWriter) Write(p []byte) (int, error) {
 		currentFile = w.file
 	}
 
-	return currentFile.Write(p)
+	n, err := currentFile.Write(p)
+	w.size.Add(int64(n))
+	return n, err
+}
+
+// Sync commits the current log file's contents to stable storage.
+// It returns nil if no file is open.
+//
+// Returns:
+//   - An error if syncing the file fails
+func (w *DailyFileWriter) Sync() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	return w.file.Sync()
 }
 
 // needsRotation reports whether the log file should be rotated (e.g. new day).
@@ -375,8 +1003,18 @@ func (w *DailyFileWriter) needsRotation() bool {
 		return true
 	}
 
-	date := time.Now().Format("2006-01-02")
-	return date != w.currDate
+	return w.dateOf(time.Now()) != w.currDate
+}
+
+// exceedsSize reports whether writing n more bytes would take a non-empty
+// current file past the size limit. It is always false without a limit.
+func (w *DailyFileWriter) exceedsSize(n int) bool {
+	if w.maxSize <= 0 {
+		return false
+	}
+
+	size := w.size.Load()
+	return size > 0 && size+int64(n) > w.maxSize
 }
 
 // ForceRotate closes the current log file and opens a new one for the current date.
@@ -401,5 +1039,5 @@ func (w *DailyFileWriter) CurrentLogFile() string {
 		return ""
 	}
 
-	return filepath.Join(w.dir, fmt.Sprintf("%s_%s.log", w.service, w.currDate))
+	return w.fileName(w.currDate, w.currSeq)
 }
@@ -0,0 +1,570 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZerologLogger_Log(t *testing.T) {
+	levelFor := func(retries int) zerolog.Level {
+		if retries > 3 {
+			return zerolog.WarnLevel
+		}
+		return zerolog.InfoLevel
+	}
+
+	tests := []struct {
+		name    string
+		retries int
+		want    string
+	}{
+		{name: "few retries log at info", retries: 1, want: "info"},
+		{name: "many retries log at warn", retries: 5, want: "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+
+			log.Log(levelFor(tt.retries), "retrying", Field{Key: "retries", Value: tt.retries})
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, tt.want, entry["level"])
+			assert.Equal(t, "retrying", entry["message"])
+			assert.Equal(t, float64(tt.retries), entry["retries"])
+		})
+	}
+
+	t.Run("respects the minimum level", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.WarnLevel)
+
+		log.Log(zerolog.InfoLevel, "dropped")
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("fatal level does not exit", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+
+		log.Log(zerolog.FatalLevel, "still running")
+		assert.Contains(t, buf.String(), `"level":"fatal"`)
+	})
+}
+
+func TestZerologLogger_WithError(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+
+	_, err := os.Open("/does/not/exist")
+	require.Error(t, err)
+
+	log.WithError(err).Error("open failed")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "open failed", entry["message"])
+	assert.Equal(t, err.Error(), entry["error"])
+	assert.Equal(t, "*fs.PathError", entry["error_type"])
+
+	t.Run("nil error adds no fields", func(t *testing.T) {
+		buf.Reset()
+
+		log.WithError(nil).Info("ok")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.NotContains(t, entry, "error")
+		assert.NotContains(t, entry, "error_type")
+	})
+}
+
+func TestZerologLogger_Enabled(t *testing.T) {
+	log := NewZerologLogger(zerolog.New(io.Discard), "test", zerolog.InfoLevel)
+
+	assert.False(t, log.Enabled(zerolog.DebugLevel), "below the configured level")
+	assert.False(t, log.Enabled(zerolog.TraceLevel))
+	assert.True(t, log.Enabled(zerolog.InfoLevel))
+	assert.True(t, log.Enabled(zerolog.ErrorLevel))
+	assert.False(t, log.Enabled(zerolog.Disabled))
+
+	// Derived loggers keep the level
+	assert.False(t, log.With(Field{Key: "k", Value: "v"}).Enabled(zerolog.DebugLevel))
+
+	t.Run("global level", func(t *testing.T) {
+		defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+
+		assert.False(t, log.Enabled(zerolog.WarnLevel))
+		assert.True(t, log.Enabled(zerolog.ErrorLevel))
+	})
+}
+
+func TestZerologLogger_FatalAndPanic(t *testing.T) {
+	t.Run("fatal closes the log file and exits", func(t *testing.T) {
+		var code int
+		exited := false
+		defer func(orig func(int)) { exit = orig }(exit)
+		exit = func(c int) { code, exited = c, true }
+
+		log := newZerologFileLogger("test", t.TempDir(), zerolog.InfoLevel, io.Discard)
+		fileWriter := log.(*zerologLogger).fileWriter
+		file := fileWriter.CurrentLogFile()
+
+		log.With(Field{Key: "component", Value: "db"}).Fatal("cannot start", Field{Key: "port", Value: 5432})
+
+		assert.True(t, exited)
+		assert.Equal(t, 1, code)
+		assert.Empty(t, fileWriter.CurrentLogFile(), "file writer is closed")
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(data, &entry))
+		assert.Equal(t, "fatal", entry["level"])
+		assert.Equal(t, "cannot start", entry["message"])
+		assert.Equal(t, "db", entry["component"])
+		assert.Equal(t, float64(5432), entry["port"])
+	})
+
+	t.Run("panic logs and panics with the message", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.InfoLevel)
+
+		assert.PanicsWithValue(t, "invariant broken", func() {
+			log.Panic("invariant broken", Field{Key: "id", Value: 7})
+		})
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "panic", entry["level"])
+		assert.Equal(t, "invariant broken", entry["message"])
+		assert.Equal(t, float64(7), entry["id"])
+	})
+}
+
+func TestZerologLogger_WithCaller(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel).WithCaller()
+
+	caller := func(t *testing.T) string {
+		t.Helper()
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		buf.Reset()
+		require.Contains(t, entry, zerolog.CallerFieldName)
+		return entry[zerolog.CallerFieldName].(string)
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	log.Info("from the test")
+	assert.Equal(t, fmt.Sprintf("%s:%d", file, line+1), caller(t))
+
+	_, _, line, _ = runtime.Caller(0)
+	log.Log(zerolog.WarnLevel, "at a runtime level")
+	assert.Equal(t, fmt.Sprintf("%s:%d", file, line+1), caller(t))
+
+	_, _, line, _ = runtime.Caller(0)
+	log.With(Field{Key: "k", Value: "v"}).Error("from a derived logger")
+	assert.Equal(t, fmt.Sprintf("%s:%d", file, line+1), caller(t), "derived loggers keep the caller")
+
+	t.Run("off by default", func(t *testing.T) {
+		NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel).Info("no caller")
+		assert.NotContains(t, buf.String(), `"caller"`)
+		buf.Reset()
+	})
+}
+
+func TestZerologLogger_ErrorHook(t *testing.T) {
+	type call struct {
+		msg    string
+		fields []Field
+	}
+	newLogger := func(level zerolog.Level) (Logger, chan call) {
+		calls := make(chan call, 8)
+		hook := func(msg string, fields []Field) { calls <- call{msg, fields} }
+		return NewZerologLogger(zerolog.New(io.Discard), "test", level, WithErrorHook(hook)), calls
+	}
+	expectNone := func(t *testing.T, calls chan call) {
+		t.Helper()
+		select {
+		case c := <-calls:
+			t.Fatalf("unexpected hook call for %q", c.msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	t.Run("fires for errors with derived fields", func(t *testing.T) {
+		log, calls := newLogger(zerolog.DebugLevel)
+
+		log.With(Str("component", "db")).Error("query failed", Int("attempt", 2))
+
+		select {
+		case c := <-calls:
+			assert.Equal(t, "query failed", c.msg)
+			assert.Equal(t, []Field{Str("component", "db"), Int("attempt", 2)}, c.fields)
+		case <-time.After(2 * time.Second):
+			t.Fatal("hook not called")
+		}
+	})
+
+	t.Run("does not fire below error level", func(t *testing.T) {
+		log, calls := newLogger(zerolog.DebugLevel)
+
+		log.Debug("d")
+		log.Info("i")
+		log.Warn("w")
+		log.Log(zerolog.WarnLevel, "l")
+		expectNone(t, calls)
+
+		log.Log(zerolog.ErrorLevel, "dynamic")
+		select {
+		case c := <-calls:
+			assert.Equal(t, "dynamic", c.msg)
+		case <-time.After(2 * time.Second):
+			t.Fatal("hook not called for Log at error level")
+		}
+	})
+
+	t.Run("does not fire for filtered entries", func(t *testing.T) {
+		log, calls := newLogger(zerolog.FatalLevel)
+
+		log.Error("filtered")
+		expectNone(t, calls)
+	})
+
+	t.Run("fatal runs the hook before exiting", func(t *testing.T) {
+		var hooked bool
+		defer func(orig func(int)) { exit = orig }(exit)
+		exit = func(int) { assert.True(t, hooked, "hook ran before exit") }
+
+		log := NewZerologLogger(zerolog.New(io.Discard), "test", zerolog.InfoLevel,
+			WithErrorHook(func(msg string, fields []Field) { hooked = true }))
+		log.Fatal("shutting down")
+		assert.True(t, hooked)
+	})
+}
+
+func TestConsoleOutput(t *testing.T) {
+	// console returns a ConsoleWriter into buf without colors, so output can be matched.
+	console := func(buf *bytes.Buffer) zerolog.ConsoleWriter {
+		w := newConsoleWriter(buf)
+		w.NoColor = true
+		return w
+	}
+
+	t.Run("console logger writes readable lines with service and timestamp", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := NewZerologLogger(zerolog.New(console(&buf)), "billing", zerolog.InfoLevel)
+
+		log.Info("invoice sent", Field{Key: "invoice", Value: 42})
+
+		line := buf.String()
+		assert.False(t, json.Valid(buf.Bytes()), "console output is not JSON: %q", line)
+		assert.Contains(t, line, "INF")
+		assert.Contains(t, line, "invoice sent")
+		assert.Contains(t, line, "service=billing")
+		assert.Contains(t, line, "invoice=42")
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}`, line)
+	})
+
+	t.Run("file output stays JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newZerologFileLogger("billing", t.TempDir(), zerolog.InfoLevel, console(&buf))
+		file := log.(*zerologLogger).fileWriter.CurrentLogFile()
+
+		log.Info("invoice sent")
+		require.NoError(t, log.Close())
+
+		assert.Contains(t, buf.String(), "service=billing")
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(data, &entry))
+		assert.Equal(t, "billing", entry["service"])
+		assert.Equal(t, "invoice sent", entry["message"])
+		assert.Contains(t, entry, "time")
+	})
+}
+
+func TestDailyFileWriter_MaxSize(t *testing.T) {
+	line := []byte(strings.Repeat("x", 29) + "\n")
+	date := time.Now().Format("2006-01-02")
+
+	t.Run("rotates to numbered files", func(t *testing.T) {
+		dir := t.TempDir()
+		w, err := NewDailyFileWriterWithOptions("svc", dir, WithMaxSizeBytes(100))
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			_, err := w.Write(line)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, filepath.Join(dir, "svc_"+date+".3.log"), w.CurrentLogFile())
+		require.NoError(t, w.Close())
+
+		for name, want := range map[string]int{
+			"svc_" + date + ".log":   90,
+			"svc_" + date + ".1.log": 90,
+			"svc_" + date + ".2.log": 90,
+			"svc_" + date + ".3.log": 30,
+		} {
+			info, err := os.Stat(filepath.Join(dir, name))
+			require.NoError(t, err, name)
+			assert.EqualValues(t, want, info.Size(), name)
+		}
+	})
+
+	t.Run("resumes the latest file after restart", func(t *testing.T) {
+		dir := t.TempDir()
+		w, err := NewDailyFileWriterWithOptions("svc", dir, WithMaxSizeBytes(100))
+		require.NoError(t, err)
+		for i := 0; i < 4; i++ {
+			_, err := w.Write(line)
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+
+		w, err = NewDailyFileWriterWithOptions("svc", dir, WithMaxSizeBytes(100))
+		require.NoError(t, err)
+		defer w.Close()
+		assert.Equal(t, filepath.Join(dir, "svc_"+date+".1.log"), w.CurrentLogFile())
+
+		for i := 0; i < 3; i++ {
+			_, err := w.Write(line)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, filepath.Join(dir, "svc_"+date+".2.log"), w.CurrentLogFile())
+	})
+
+	t.Run("no limit keeps a single file", func(t *testing.T) {
+		dir := t.TempDir()
+		w, err := NewDailyFileWriter("svc", dir)
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			_, err := w.Write(line)
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "svc_"+date+".log", entries[0].Name())
+	})
+}
+
+func TestDailyFileWriter_Retention(t *testing.T) {
+	// touch creates name in dir with its modification time set age ago
+	touch := func(t *testing.T, dir, name string, age time.Duration) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+		mtime := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	names := func(t *testing.T, dir string) []string {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		var out []string
+		for _, entry := range entries {
+			out = append(out, entry.Name())
+		}
+		return out
+	}
+
+	day := 24 * time.Hour
+	current := "svc_" + time.Now().Format("2006-01-02") + ".log"
+
+	t.Run("removes files older than max age", func(t *testing.T) {
+		dir := t.TempDir()
+		touch(t, dir, "svc_2020-01-01.log", 10*day)
+		touch(t, dir, "svc_2020-01-01.1.log", 10*day)
+		touch(t, dir, "svc_2020-01-05.log", 3*day)
+		// Not this writer's files
+		touch(t, dir, "other_2020-01-01.log", 10*day)
+		touch(t, dir, "svc_notes.log", 10*day)
+		touch(t, dir, "svc_2020-01-01.txt", 10*day)
+
+		w, err := NewDailyFileWriterWithOptions("svc", dir, WithMaxAgeDays(7))
+		require.NoError(t, err)
+		defer w.Close()
+
+		want := []string{"other_2020-01-01.log", "svc_2020-01-01.txt", "svc_2020-01-05.log", current, "svc_notes.log"}
+		require.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual(want, names(t, dir))
+		}, 2*time.Second, 10*time.Millisecond, "files: %v", names(t, dir))
+	})
+
+	t.Run("keeps the newest backups", func(t *testing.T) {
+		dir := t.TempDir()
+		touch(t, dir, "svc_2020-01-01.log", 5*day)
+		touch(t, dir, "svc_2020-01-02.log", 4*day)
+		touch(t, dir, "svc_2020-01-03.log", 3*day)
+		touch(t, dir, "svc_2020-01-03.1.log", 2*day)
+		touch(t, dir, "svc_2020-01-04.log", 1*day)
+
+		w, err := NewDailyFileWriterWithOptions("svc", dir, WithMaxBackups(2))
+		require.NoError(t, err)
+		defer w.Close()
+
+		want := []string{"svc_2020-01-03.1.log", "svc_2020-01-04.log", current}
+		require.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual(want, names(t, dir))
+		}, 2*time.Second, 10*time.Millisecond, "files: %v", names(t, dir))
+	})
+
+	t.Run("no options keep everything", func(t *testing.T) {
+		dir := t.TempDir()
+		touch(t, dir, "svc_2020-01-01.log", 100*day)
+
+		w, err := NewDailyFileWriter("svc", dir)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		assert.Equal(t, []string{"svc_2020-01-01.log", current}, names(t, dir))
+	})
+
+	t.Run("Close waits for a running cleanup", func(t *testing.T) {
+		dir := t.TempDir()
+		for i := range 500 {
+			touch(t, dir, fmt.Sprintf("svc_2020-01-01.%d.log", i+1), 10*day)
+		}
+		touch(t, dir, "svc_2020-01-02.log", 5*day)
+
+		w, err := NewDailyFileWriterWithOptions("svc", dir, WithMaxBackups(1))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		assert.Equal(t, []string{"svc_2020-01-02.log", current}, names(t, dir))
+	})
+}
+
+func TestDailyFileWriter_RotateAt(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		require.NoError(t, err)
+		return tm
+	}
+
+	t.Run("defaults to midnight", func(t *testing.T) {
+		w := &DailyFileWriter{}
+		assert.Equal(t, "2024-01-02", w.dateOf(at("2024-01-02 00:00")))
+		assert.Equal(t, "2024-01-02", w.dateOf(at("2024-01-02 23:59")))
+		assert.Equal(t, at("2024-01-03 00:00"), w.nextBoundary(at("2024-01-02 12:00")))
+	})
+
+	t.Run("offset into the day", func(t *testing.T) {
+		w := &DailyFileWriter{}
+		WithRotateAt(18 * time.Hour)(w)
+
+		assert.Equal(t, "2024-01-01", w.dateOf(at("2024-01-02 17:59")))
+		assert.Equal(t, "2024-01-02", w.dateOf(at("2024-01-02 18:00")))
+		assert.Equal(t, "2024-01-02", w.dateOf(at("2024-01-03 00:30")))
+		assert.Equal(t, "2023-12-31", w.dateOf(at("2024-01-01 06:00")))
+
+		assert.Equal(t, at("2024-01-02 18:00"), w.nextBoundary(at("2024-01-02 17:59")))
+		assert.Equal(t, at("2024-01-03 18:00"), w.nextBoundary(at("2024-01-02 18:00")))
+	})
+
+	t.Run("offsets wrap around the day", func(t *testing.T) {
+		w := &DailyFileWriter{}
+		WithRotateAt(-6 * time.Hour)(w)
+		assert.Equal(t, 18*time.Hour, w.rotateAt)
+
+		WithRotateAt(30 * time.Hour)(w)
+		assert.Equal(t, 6*time.Hour, w.rotateAt)
+	})
+
+	t.Run("keeps the wall-clock time on DST days", func(t *testing.T) {
+		ny, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Skip("time zone database unavailable")
+		}
+
+		w := &DailyFileWriter{}
+		WithLocation(ny)(w)
+		WithRotateAt(18 * time.Hour)(w)
+
+		// Clocks go forward on 10 March 2024 and back on 3 November
+		for _, day := range []time.Time{
+			time.Date(2024, time.March, 10, 0, 0, 0, 0, ny),
+			time.Date(2024, time.November, 3, 0, 0, 0, 0, ny),
+		} {
+			noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, ny)
+			want := time.Date(day.Year(), day.Month(), day.Day(), 18, 0, 0, 0, ny)
+			assert.True(t, want.Equal(w.nextBoundary(noon)), "boundary on %s", noon.Format("2006-01-02"))
+			assert.Equal(t, noon.AddDate(0, 0, -1).Format("2006-01-02"), w.dateOf(want.Add(-time.Minute)))
+			assert.Equal(t, noon.Format("2006-01-02"), w.dateOf(want))
+		}
+	})
+
+	t.Run("names the file after the start of the rotation day", func(t *testing.T) {
+		now := time.Now()
+		// Rotate one hour from now, so the current rotation day began yesterday
+		sinceMidnight := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+		if sinceMidnight+time.Hour >= 24*time.Hour {
+			t.Skip("too close to midnight")
+		}
+
+		dir := t.TempDir()
+		w, err := NewDailyFileWriterWithOptions("svc", dir, WithRotateAt(sinceMidnight+time.Hour))
+		require.NoError(t, err)
+		defer w.Close()
+
+		yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+		assert.Equal(t, filepath.Join(dir, "svc_"+yesterday+".log"), w.CurrentLogFile())
+	})
+}
+
+func TestDailyFileWriter_Location(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	instant := time.Date(2024, 1, 2, 20, 0, 0, 0, time.UTC) // 01:30 on 3 January in IST
+
+	t.Run("dates follow the configured zone", func(t *testing.T) {
+		utc := &DailyFileWriter{}
+		WithLocation(time.UTC)(utc)
+		assert.Equal(t, "2024-01-02", utc.dateOf(instant))
+		assert.Equal(t, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), utc.nextBoundary(instant))
+
+		india := &DailyFileWriter{}
+		WithLocation(ist)(india)
+		assert.Equal(t, "2024-01-03", india.dateOf(instant))
+		assert.True(t, time.Date(2024, 1, 4, 0, 0, 0, 0, ist).Equal(india.nextBoundary(instant)))
+	})
+
+	t.Run("combines with the rotation time", func(t *testing.T) {
+		w := &DailyFileWriter{}
+		WithLocation(ist)(w)
+		WithRotateAt(2 * time.Hour)(w)
+		assert.Equal(t, "2024-01-02", w.dateOf(instant))
+	})
+
+	t.Run("names the current file", func(t *testing.T) {
+		dir := t.TempDir()
+		w, err := NewDailyFileWriterWithOptions("svc", dir, WithLocation(ist))
+		require.NoError(t, err)
+		defer w.Close()
+
+		want := "svc_" + time.Now().In(ist).Format("2006-01-02") + ".log"
+		assert.Equal(t, filepath.Join(dir, want), w.CurrentLogFile())
+	})
+}
@@ -5,6 +5,7 @@
 package logger
 
 import (
+	"github.com/rs/zerolog"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -133,6 +134,57 @@ func (_c *MockLogger_Debug_Call) RunAndReturn(run func(msg string, fields ...Fie
 	return _c
 }
 
+// Enabled provides a mock function for the type MockLogger
+func (_mock *MockLogger) Enabled(level zerolog.Level) bool {
+	ret := _mock.Called(level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enabled")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func(zerolog.Level) bool); ok {
+		r0 = returnFunc(level)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockLogger_Enabled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enabled'
+type MockLogger_Enabled_Call struct {
+	*mock.Call
+}
+
+// Enabled is a helper method to define mock.On call
+//   - level zerolog.Level
+func (_e *MockLogger_Expecter) Enabled(level interface{}) *MockLogger_Enabled_Call {
+	return &MockLogger_Enabled_Call{Call: _e.mock.On("Enabled", level)}
+}
+
+func (_c *MockLogger_Enabled_Call) Run(run func(level zerolog.Level)) *MockLogger_Enabled_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 zerolog.Level
+		if args[0] != nil {
+			arg0 = args[0].(zerolog.Level)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Enabled_Call) Return(b bool) *MockLogger_Enabled_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockLogger_Enabled_Call) RunAndReturn(run func(level zerolog.Level) bool) *MockLogger_Enabled_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Error provides a mock function for the type MockLogger
 func (_mock *MockLogger) Error(msg string, fields ...Field) {
 	if len(fields) > 0 {
@@ -187,6 +239,60 @@ func (_c *MockLogger_Error_Call) RunAndReturn(run func(msg string, fields ...Fie
 	return _c
 }
 
+// Fatal provides a mock function for the type MockLogger
+func (_mock *MockLogger) Fatal(msg string, fields ...Field) {
+	if len(fields) > 0 {
+		_mock.Called(msg, fields)
+	} else {
+		_mock.Called(msg)
+	}
+
+	return
+}
+
+// MockLogger_Fatal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fatal'
+type MockLogger_Fatal_Call struct {
+	*mock.Call
+}
+
+// Fatal is a helper method to define mock.On call
+//   - msg string
+//   - fields ...Field
+func (_e *MockLogger_Expecter) Fatal(msg interface{}, fields ...interface{}) *MockLogger_Fatal_Call {
+	return &MockLogger_Fatal_Call{Call: _e.mock.On("Fatal",
+		append([]interface{}{msg}, fields...)...)}
+}
+
+func (_c *MockLogger_Fatal_Call) Run(run func(msg string, fields ...Field)) *MockLogger_Fatal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []Field
+		var variadicArgs []Field
+		if len(args) > 1 {
+			variadicArgs = args[1].([]Field)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Fatal_Call) Return() *MockLogger_Fatal_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Fatal_Call) RunAndReturn(run func(msg string, fields ...Field)) *MockLogger_Fatal_Call {
+	_c.Run(run)
+	return _c
+}
+
 // GetLoggerInstance provides a mock function for the type MockLogger
 func (_mock *MockLogger) GetLoggerInstance() interface{} {
 	ret := _mock.Called()
@@ -287,6 +393,120 @@ func (_c *MockLogger_Info_Call) RunAndReturn(run func(msg string, fields ...Fiel
 	return _c
 }
 
+// Log provides a mock function for the type MockLogger
+func (_mock *MockLogger) Log(level zerolog.Level, msg string, fields ...Field) {
+	if len(fields) > 0 {
+		_mock.Called(level, msg, fields)
+	} else {
+		_mock.Called(level, msg)
+	}
+
+	return
+}
+
+// MockLogger_Log_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Log'
+type MockLogger_Log_Call struct {
+	*mock.Call
+}
+
+// Log is a helper method to define mock.On call
+//   - level zerolog.Level
+//   - msg string
+//   - fields ...Field
+func (_e *MockLogger_Expecter) Log(level interface{}, msg interface{}, fields ...interface{}) *MockLogger_Log_Call {
+	return &MockLogger_Log_Call{Call: _e.mock.On("Log",
+		append([]interface{}{level, msg}, fields...)...)}
+}
+
+func (_c *MockLogger_Log_Call) Run(run func(level zerolog.Level, msg string, fields ...Field)) *MockLogger_Log_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 zerolog.Level
+		if args[0] != nil {
+			arg0 = args[0].(zerolog.Level)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []Field
+		var variadicArgs []Field
+		if len(args) > 2 {
+			variadicArgs = args[2].([]Field)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Log_Call) Return() *MockLogger_Log_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Log_Call) RunAndReturn(run func(level zerolog.Level, msg string, fields ...Field)) *MockLogger_Log_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Panic provides a mock function for the type MockLogger
+func (_mock *MockLogger) Panic(msg string, fields ...Field) {
+	if len(fields) > 0 {
+		_mock.Called(msg, fields)
+	} else {
+		_mock.Called(msg)
+	}
+
+	return
+}
+
+// MockLogger_Panic_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Panic'
+type MockLogger_Panic_Call struct {
+	*mock.Call
+}
+
+// Panic is a helper method to define mock.On call
+//   - msg string
+//   - fields ...Field
+func (_e *MockLogger_Expecter) Panic(msg interface{}, fields ...interface{}) *MockLogger_Panic_Call {
+	return &MockLogger_Panic_Call{Call: _e.mock.On("Panic",
+		append([]interface{}{msg}, fields...)...)}
+}
+
+func (_c *MockLogger_Panic_Call) Run(run func(msg string, fields ...Field)) *MockLogger_Panic_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []Field
+		var variadicArgs []Field
+		if len(args) > 1 {
+			variadicArgs = args[1].([]Field)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Panic_Call) Return() *MockLogger_Panic_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Panic_Call) RunAndReturn(run func(msg string, fields ...Field)) *MockLogger_Panic_Call {
+	_c.Run(run)
+	return _c
+}
+
 // Warn provides a mock function for the type MockLogger
 func (_mock *MockLogger) Warn(msg string, fields ...Field) {
 	if len(fields) > 0 {
@@ -402,3 +622,102 @@ func (_c *MockLogger_With_Call) RunAndReturn(run func(fields ...Field) Logger) *
 	_c.Call.Return(run)
 	return _c
 }
+
+// WithCaller provides a mock function for the type MockLogger
+func (_mock *MockLogger) WithCaller() Logger {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithCaller")
+	}
+
+	var r0 Logger
+	if returnFunc, ok := ret.Get(0).(func() Logger); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Logger)
+		}
+	}
+	return r0
+}
+
+// MockLogger_WithCaller_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithCaller'
+type MockLogger_WithCaller_Call struct {
+	*mock.Call
+}
+
+// WithCaller is a helper method to define mock.On call
+func (_e *MockLogger_Expecter) WithCaller() *MockLogger_WithCaller_Call {
+	return &MockLogger_WithCaller_Call{Call: _e.mock.On("WithCaller")}
+}
+
+func (_c *MockLogger_WithCaller_Call) Run(run func()) *MockLogger_WithCaller_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockLogger_WithCaller_Call) Return(logger Logger) *MockLogger_WithCaller_Call {
+	_c.Call.Return(logger)
+	return _c
+}
+
+func (_c *MockLogger_WithCaller_Call) RunAndReturn(run func() Logger) *MockLogger_WithCaller_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithError provides a mock function for the type MockLogger
+func (_mock *MockLogger) WithError(err error) Logger {
+	ret := _mock.Called(err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithError")
+	}
+
+	var r0 Logger
+	if returnFunc, ok := ret.Get(0).(func(error) Logger); ok {
+		r0 = returnFunc(err)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Logger)
+		}
+	}
+	return r0
+}
+
+// MockLogger_WithError_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithError'
+type MockLogger_WithError_Call struct {
+	*mock.Call
+}
+
+// WithError is a helper method to define mock.On call
+//   - err error
+func (_e *MockLogger_Expecter) WithError(err interface{}) *MockLogger_WithError_Call {
+	return &MockLogger_WithError_Call{Call: _e.mock.On("WithError", err)}
+}
+
+func (_c *MockLogger_WithError_Call) Run(run func(err error)) *MockLogger_WithError_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 error
+		if args[0] != nil {
+			arg0 = args[0].(error)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLogger_WithError_Call) Return(logger Logger) *MockLogger_WithError_Call {
+	_c.Call.Return(logger)
+	return _c
+}
+
+func (_c *MockLogger_WithError_Call) RunAndReturn(run func(err error) Logger) *MockLogger_WithError_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,73 @@
+package logger
+
+import "github.com/rs/zerolog"
+
+// noopLogger is a Logger that discards every entry.
+type noopLogger struct{}
+
+// NewNoopLogger creates a Logger that discards every entry, for use as a safe
+// default where a Logger is optional. With and WithCaller return the same
+// logger, Enabled reports false for every level, GetLoggerInstance returns
+// nil, and Close returns nil. Fatal and Panic write nothing but still exit and
+// panic, since callers rely on them not returning.
+//
+// Returns:
+//   - A Logger that writes nothing
+func NewNoopLogger() Logger {
+	return noopLogger{}
+}
+
+// Debug implements Logger.
+func (noopLogger) Debug(msg string, fields ...Field) {}
+
+// Info implements Logger.
+func (noopLogger) Info(msg string, fields ...Field) {}
+
+// Warn implements Logger.
+func (noopLogger) Warn(msg string, fields ...Field) {}
+
+// Error implements Logger.
+func (noopLogger) Error(msg string, fields ...Field) {}
+
+// Fatal implements Logger.
+func (noopLogger) Fatal(msg string, fields ...Field) {
+	exit(1)
+}
+
+// Panic implements Logger.
+func (noopLogger) Panic(msg string, fields ...Field) {
+	panic(msg)
+}
+
+// Log implements Logger.
+func (noopLogger) Log(level zerolog.Level, msg string, fields ...Field) {}
+
+// Enabled implements Logger.
+func (noopLogger) Enabled(level zerolog.Level) bool {
+	return false
+}
+
+// With implements Logger.
+func (n noopLogger) With(fields ...Field) Logger {
+	return n
+}
+
+// WithCaller implements Logger.
+func (n noopLogger) WithCaller() Logger {
+	return n
+}
+
+// WithError implements Logger.
+func (n noopLogger) WithError(err error) Logger {
+	return n
+}
+
+// GetLoggerInstance implements Logger.
+func (noopLogger) GetLoggerInstance() interface{} {
+	return nil
+}
+
+// Close implements Logger.
+func (noopLogger) Close() error {
+	return nil
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// syncer is implemented by loggers that can flush buffered output, such as the
+// loggers returned by NewZerologFileLogger.
+type syncer interface {
+	Sync() error
+}
+
+// RecoverAndLog recovers a panic in the current goroutine, logs it at error level with
+// the panic value and stack trace, and flushes the logger's file output so the entry is
+// persisted. It must be called directly with defer, typically at goroutine entry:
+//
+//	defer logger.RecoverAndLog(log)
+//
+// The panic is swallowed; use RecoverLogAndRepanic to let it continue.
+//
+// Parameters:
+//   - log: The Logger to write the panic entry to
+func RecoverAndLog(log Logger) {
+	if r := recover(); r != nil {
+		logPanic(log, r, debug.Stack())
+	}
+}
+
+// RecoverLogAndRepanic behaves like RecoverAndLog, but panics again with the original
+// value after the entry has been logged and flushed. It must be called directly with defer.
+//
+// Parameters:
+//   - log: The Logger to write the panic entry to
+func RecoverLogAndRepanic(log Logger) {
+	if r := recover(); r != nil {
+		logPanic(log, r, debug.Stack())
+		panic(r)
+	}
+}
+
+// logPanic writes the panic entry and syncs the logger if it supports it.
+func logPanic(log Logger, r any, stack []byte) {
+	log.Error("recovered from panic",
+		Field{Key: "panic", Value: fmt.Sprint(r)},
+		Field{Key: "stack", Value: string(stack)},
+	)
+
+	if s, ok := log.(syncer); ok {
+		_ = s.Sync()
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturingLogger records Error entries for assertions.
+type capturingLogger struct {
+	MockLogger
+	mu      sync.Mutex
+	entries []map[string]any
+	synced  bool
+}
+
+func (c *capturingLogger) Error(msg string, fields ...Field) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := toMap(fields)
+	entry["msg"] = msg
+	c.entries = append(c.entries, entry)
+}
+
+func (c *capturingLogger) Sync() error {
+	c.synced = true
+	return nil
+}
+
+func panickingFunction() {
+	panic("boom")
+}
+
+func TestRecoverAndLog(t *testing.T) {
+	t.Run("logs the panic with its stack and syncs", func(t *testing.T) {
+		log := &capturingLogger{}
+
+		func() {
+			defer RecoverAndLog(log)
+			panickingFunction()
+		}()
+
+		require.Len(t, log.entries, 1)
+		assert.Equal(t, "recovered from panic", log.entries[0]["msg"])
+		assert.Equal(t, "boom", log.entries[0]["panic"])
+		assert.Contains(t, log.entries[0]["stack"], "panickingFunction")
+		assert.True(t, log.synced)
+	})
+
+	t.Run("does nothing without a panic", func(t *testing.T) {
+		log := &capturingLogger{}
+
+		func() {
+			defer RecoverAndLog(log)
+		}()
+
+		assert.Empty(t, log.entries)
+	})
+
+	t.Run("persists the entry to the log file", func(t *testing.T) {
+		dir := t.TempDir()
+		fileWriter, err := NewDailyFileWriter("svc", dir)
+		require.NoError(t, err)
+		log := &zerologLogger{logger: zerolog.New(fileWriter), fileWriter: fileWriter, ownsFileWriter: true}
+		defer func() { _ = log.Close() }()
+
+		func() {
+			defer RecoverAndLog(log)
+			panickingFunction()
+		}()
+
+		data, err := os.ReadFile(filepath.Clean(fileWriter.CurrentLogFile()))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "recovered from panic")
+		assert.Contains(t, string(data), "panickingFunction")
+	})
+}
+
+func TestRecoverLogAndRepanic(t *testing.T) {
+	log := &capturingLogger{}
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer RecoverLogAndRepanic(log)
+		panickingFunction()
+	})
+
+	require.Len(t, log.entries, 1)
+	assert.Contains(t, log.entries[0]["stack"], "panickingFunction")
+}
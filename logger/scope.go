@@ -0,0 +1,37 @@
+package logger
+
+import "github.com/cyberinferno/go-utils/utils"
+
+// RequestIDField is the field key under which request IDs are logged.
+const RequestIDField = "request_id"
+
+// requestIDLength is the length of the request IDs generated by StartScope.
+const requestIDLength = 16
+
+// StartScope derives a request-scoped logger from log, so that every entry
+// written during one request carries the same request ID. If fields contain a
+// RequestIDField entry with a non-empty string value, that ID is reused, for
+// example one received from an upstream service; otherwise a random
+// alphanumeric ID is generated and added to the fields.
+//
+// Parameters:
+//   - log: The Logger to derive the scoped logger from
+//   - fields: Additional fields to attach to every entry of the scoped logger
+//
+// Returns:
+//   - The scoped Logger
+//   - The request ID, for propagation to other services or goroutines
+func StartScope(log Logger, fields ...Field) (Logger, string) {
+	for _, f := range fields {
+		if id, ok := f.Value.(string); ok && f.Key == RequestIDField && id != "" {
+			return log.With(fields...), id
+		}
+	}
+
+	id := utils.GenerateRandomString(requestIDLength)
+	scoped := make([]Field, 0, len(fields)+1)
+	scoped = append(scoped, Field{Key: RequestIDField, Value: id})
+	scoped = append(scoped, fields...)
+
+	return log.With(scoped...), id
+}
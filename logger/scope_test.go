@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartScope(t *testing.T) {
+	// entries decodes one JSON log entry per line of buf.
+	entries := func(t *testing.T, buf *bytes.Buffer) []map[string]any {
+		t.Helper()
+		var out []map[string]any
+		scanner := bufio.NewScanner(buf)
+		for scanner.Scan() {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			out = append(out, entry)
+		}
+		return out
+	}
+
+	t.Run("every line carries the generated request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+
+		scoped, id := StartScope(log, Field{Key: "user", Value: "alice"})
+		require.Len(t, id, requestIDLength)
+
+		scoped.Info("first")
+		scoped.Warn("second")
+		scoped.With(Field{Key: "step", Value: 3}).Error("third")
+		log.Info("outside scope")
+
+		lines := entries(t, &buf)
+		require.Len(t, lines, 4)
+		for _, entry := range lines[:3] {
+			assert.Equal(t, id, entry[RequestIDField])
+			assert.Equal(t, "alice", entry["user"])
+		}
+		assert.NotContains(t, lines[3], RequestIDField)
+	})
+
+	t.Run("each scope gets a new ID", func(t *testing.T) {
+		log := NewZerologLogger(zerolog.Nop(), "test", zerolog.DebugLevel)
+
+		_, first := StartScope(log)
+		_, second := StartScope(log)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("reuses a supplied request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := NewZerologLogger(zerolog.New(&buf), "test", zerolog.DebugLevel)
+
+		scoped, id := StartScope(log, Field{Key: RequestIDField, Value: "upstream-42"})
+		assert.Equal(t, "upstream-42", id)
+
+		scoped.Info("hello")
+		lines := entries(t, &buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "upstream-42", lines[0][RequestIDField])
+	})
+}
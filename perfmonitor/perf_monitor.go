@@ -1,40 +1,147 @@
 package perfmonitor
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// StopLapName is the name of the lap recorded by Stop
+const StopLapName = "stop"
+
+// Lap is a named checkpoint recorded by Lap or Stop
+type Lap struct {
+	Name     string        // Name given to the checkpoint
+	Duration time.Duration // Time since the previous lap, or since Start for the first lap
+	Elapsed  time.Duration // Time since Start
+}
 
 // PerformanceMonitor helps track elapsed time between operations
 type PerformanceMonitor struct {
 	startTime time.Time
 	endTime   time.Time
+	laps      []Lap
+	stopLap   bool // whether the last lap was recorded by Stop
 }
 
-// Start begins the performance monitoring
+// Start begins the performance monitoring and clears any recorded laps
 func (p *PerformanceMonitor) Start() {
 	p.startTime = time.Now()
+	p.laps = nil
+	p.stopLap = false
 }
 
-// Stop ends the performance monitoring
+// Stop ends the performance monitoring and records a final lap named StopLapName.
+// Calling Stop again replaces that final lap instead of adding another.
 func (p *PerformanceMonitor) Stop() {
 	if p.startTime.IsZero() {
 		return
 	}
 
 	p.endTime = time.Now()
+
+	if p.stopLap {
+		p.laps = p.laps[:len(p.laps)-1]
+	}
+	p.recordLap(StopLapName, p.endTime)
+	p.stopLap = true
 }
 
-// ElapsedMilliseconds returns the elapsed time in milliseconds between Start and Stop
-func (p *PerformanceMonitor) ElapsedMilliseconds() float64 {
+// Lap records a named checkpoint without stopping the timer and returns the time
+// since the previous lap, or since Start for the first lap. It returns 0 and records
+// nothing if Start has not been called.
+func (p *PerformanceMonitor) Lap(name string) time.Duration {
+	if p.startTime.IsZero() {
+		return 0
+	}
+
+	p.stopLap = false
+	return p.recordLap(name, time.Now())
+}
+
+// Laps returns a copy of the recorded laps in the order they were taken
+func (p *PerformanceMonitor) Laps() []Lap {
+	laps := make([]Lap, len(p.laps))
+	copy(laps, p.laps)
+	return laps
+}
+
+// recordLap appends a lap ending at now and returns its duration
+func (p *PerformanceMonitor) recordLap(name string, now time.Time) time.Duration {
+	elapsed := now.Sub(p.startTime)
+
+	var previous time.Duration
+	if len(p.laps) > 0 {
+		previous = p.laps[len(p.laps)-1].Elapsed
+	}
+
+	lap := Lap{Name: name, Duration: elapsed - previous, Elapsed: elapsed}
+	p.laps = append(p.laps, lap)
+	return lap.Duration
+}
+
+// Elapsed returns the elapsed time between Start and Stop, or 0 if either has not been called.
+// All other Elapsed accessors are derived from it.
+func (p *PerformanceMonitor) Elapsed() time.Duration {
 	if p.startTime.IsZero() || p.endTime.IsZero() {
 		return 0
 	}
 
-	return float64(p.endTime.Sub(p.startTime).Microseconds()) / 1000.0
+	return p.endTime.Sub(p.startTime)
 }
 
-// Reset clears the timer values to allow reuse
+// IsRunning reports whether Start has been called and Stop has not been called since
+func (p *PerformanceMonitor) IsRunning() bool {
+	return !p.startTime.IsZero() && (p.endTime.IsZero() || p.endTime.Before(p.startTime))
+}
+
+// RunningElapsed returns the time since Start while the monitor is running, and the
+// same value as Elapsed once it has been stopped, so in-progress operations can be polled
+func (p *PerformanceMonitor) RunningElapsed() time.Duration {
+	if p.IsRunning() {
+		return time.Since(p.startTime)
+	}
+
+	return p.Elapsed()
+}
+
+// ElapsedSeconds returns the elapsed time in seconds between Start and Stop
+func (p *PerformanceMonitor) ElapsedSeconds() float64 {
+	return p.Elapsed().Seconds()
+}
+
+// ElapsedMilliseconds returns the elapsed time in milliseconds between Start and Stop,
+// with microsecond precision
+func (p *PerformanceMonitor) ElapsedMilliseconds() float64 {
+	return float64(p.Elapsed().Microseconds()) / 1000.0
+}
+
+// ElapsedMicroseconds returns the elapsed time in microseconds between Start and Stop,
+// with nanosecond precision
+func (p *PerformanceMonitor) ElapsedMicroseconds() float64 {
+	return float64(p.Elapsed().Nanoseconds()) / 1000.0
+}
+
+// ElapsedNanoseconds returns the elapsed time in nanoseconds between Start and Stop
+func (p *PerformanceMonitor) ElapsedNanoseconds() int64 {
+	return p.Elapsed().Nanoseconds()
+}
+
+// Reset clears the timer values and recorded laps to allow reuse
 func (p *PerformanceMonitor) Reset() {
 	p.startTime = time.Time{}
 	p.endTime = time.Time{}
+	p.laps = nil
+	p.stopLap = false
+}
+
+// String implements fmt.Stringer, e.g. "PerformanceMonitor{elapsed=103.4ms, running=false}".
+// The elapsed time is RunningElapsed rounded to the microsecond, or 0 if Start has not been called.
+func (p *PerformanceMonitor) String() string {
+	if p.startTime.IsZero() {
+		return "PerformanceMonitor{elapsed=0, running=false}"
+	}
+
+	return fmt.Sprintf("PerformanceMonitor{elapsed=%s, running=%t}", p.RunningElapsed().Round(time.Microsecond), p.IsRunning())
 }
 
 // NewPerformanceMonitor creates a new PerformanceMonitor instance
@@ -1,10 +1,12 @@
 package perfmonitor
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewPerformanceMonitor(t *testing.T) {
@@ -182,6 +184,148 @@ func TestElapsedMilliseconds(t *testing.T) {
 	})
 }
 
+func TestElapsedAccessors(t *testing.T) {
+	t.Run("all return zero when start or stop is unset", func(t *testing.T) {
+		for _, pm := range []*PerformanceMonitor{
+			NewPerformanceMonitor(),
+			func() *PerformanceMonitor { pm := NewPerformanceMonitor(); pm.Start(); return pm }(),
+		} {
+			assert.Equal(t, time.Duration(0), pm.Elapsed())
+			assert.Equal(t, 0.0, pm.ElapsedSeconds())
+			assert.Equal(t, 0.0, pm.ElapsedMilliseconds())
+			assert.Equal(t, 0.0, pm.ElapsedMicroseconds())
+			assert.Equal(t, int64(0), pm.ElapsedNanoseconds())
+		}
+	})
+
+	t.Run("all agree on the same measurement", func(t *testing.T) {
+		pm := NewPerformanceMonitor()
+		base := time.Now()
+		pm.startTime = base
+		pm.endTime = base.Add(1500*time.Millisecond + 2500*time.Nanosecond)
+
+		assert.Equal(t, 1500*time.Millisecond+2500*time.Nanosecond, pm.Elapsed())
+		assert.InDelta(t, 1.5000025, pm.ElapsedSeconds(), 1e-12)
+		assert.Equal(t, 1500.002, pm.ElapsedMilliseconds())
+		assert.Equal(t, 1500002.5, pm.ElapsedMicroseconds())
+		assert.Equal(t, int64(1500002500), pm.ElapsedNanoseconds())
+	})
+}
+
+func TestRunningElapsed(t *testing.T) {
+	pm := NewPerformanceMonitor()
+	assert.False(t, pm.IsRunning())
+	assert.Equal(t, time.Duration(0), pm.RunningElapsed())
+
+	pm.Start()
+	assert.True(t, pm.IsRunning())
+	time.Sleep(10 * time.Millisecond)
+	running := pm.RunningElapsed()
+	assert.GreaterOrEqual(t, running, 10*time.Millisecond)
+	assert.Equal(t, 0.0, pm.ElapsedMilliseconds(), "ElapsedMilliseconds stays 0 until Stop")
+
+	time.Sleep(5 * time.Millisecond)
+	assert.Greater(t, pm.RunningElapsed(), running, "keeps growing while running")
+
+	pm.Stop()
+	assert.False(t, pm.IsRunning())
+	frozen := pm.RunningElapsed()
+	assert.Equal(t, pm.Elapsed(), frozen)
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, frozen, pm.RunningElapsed(), "frozen after Stop")
+
+	pm.Start()
+	assert.True(t, pm.IsRunning(), "running again after a restart")
+
+	pm.Reset()
+	assert.False(t, pm.IsRunning())
+}
+
+func TestString(t *testing.T) {
+	pm := NewPerformanceMonitor()
+	assert.Equal(t, "PerformanceMonitor{elapsed=0, running=false}", pm.String())
+
+	base := time.Now()
+	pm.startTime = base
+	pm.endTime = base.Add(103400 * time.Microsecond)
+	assert.Equal(t, "PerformanceMonitor{elapsed=103.4ms, running=false}", pm.String())
+
+	pm.endTime = base.Add(2*time.Second + 1500*time.Nanosecond)
+	assert.Equal(t, "PerformanceMonitor{elapsed=2.000002s, running=false}", fmt.Sprint(pm))
+
+	pm.Reset()
+	pm.Start()
+	assert.Contains(t, pm.String(), "running=true")
+}
+
+func TestLaps(t *testing.T) {
+	t.Run("lap deltas sum to the total", func(t *testing.T) {
+		pm := NewPerformanceMonitor()
+		pm.Start()
+		time.Sleep(5 * time.Millisecond)
+		first := pm.Lap("parse")
+		time.Sleep(5 * time.Millisecond)
+		second := pm.Lap("validate")
+		time.Sleep(5 * time.Millisecond)
+		pm.Stop()
+
+		laps := pm.Laps()
+		require.Len(t, laps, 3)
+		assert.Equal(t, []string{"parse", "validate", StopLapName}, []string{laps[0].Name, laps[1].Name, laps[2].Name})
+		assert.Equal(t, first, laps[0].Duration)
+		assert.Equal(t, second, laps[1].Duration)
+		assert.GreaterOrEqual(t, first, 5*time.Millisecond)
+
+		var sum time.Duration
+		for _, lap := range laps {
+			sum += lap.Duration
+		}
+		assert.Equal(t, pm.Elapsed(), sum)
+		assert.Equal(t, pm.Elapsed(), laps[2].Elapsed)
+	})
+
+	t.Run("repeated stop replaces the final lap", func(t *testing.T) {
+		pm := NewPerformanceMonitor()
+		pm.Start()
+		pm.Lap("a")
+		pm.Stop()
+		time.Sleep(2 * time.Millisecond)
+		pm.Stop()
+
+		laps := pm.Laps()
+		require.Len(t, laps, 2)
+		assert.Equal(t, pm.Elapsed(), laps[0].Duration+laps[1].Duration)
+	})
+
+	t.Run("lap before start records nothing", func(t *testing.T) {
+		pm := NewPerformanceMonitor()
+		assert.Equal(t, time.Duration(0), pm.Lap("early"))
+		assert.Empty(t, pm.Laps())
+	})
+
+	t.Run("reset and start clear laps", func(t *testing.T) {
+		pm := NewPerformanceMonitor()
+		pm.Start()
+		pm.Lap("a")
+		pm.Reset()
+		assert.Empty(t, pm.Laps())
+
+		pm.Start()
+		pm.Lap("b")
+		pm.Start()
+		assert.Empty(t, pm.Laps())
+	})
+
+	t.Run("returned slice is a copy", func(t *testing.T) {
+		pm := NewPerformanceMonitor()
+		pm.Start()
+		pm.Lap("a")
+		laps := pm.Laps()
+		laps[0].Name = "changed"
+		assert.Equal(t, "a", pm.Laps()[0].Name)
+	})
+}
+
 func TestReset(t *testing.T) {
 	t.Run("clears start and end times", func(t *testing.T) {
 		pm := NewPerformanceMonitor()
@@ -3,16 +3,34 @@
 // consistent API for storing, loading, deleting, and iterating entries.
 package safemap
 
-import "sync"
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
 
 // SafeMap is a concurrent map that is safe for use by multiple goroutines.
 // It wraps sync.Map and exposes a generic, type-safe API. Keys must be
 // comparable (as defined by the comparable constraint); values may be any type.
 //
-// SafeMap must not be copied after first use. Store and Load operations
-// are amortized O(1). Len and Range are O(n) in the number of entries.
+// SafeMap must not be copied after first use. Store, Load, and Len are
+// amortized O(1); Range is O(n) in the number of entries.
 type SafeMap[K comparable, V any] struct {
 	m sync.Map
+
+	// size counts entries; every insert and removal goes through an operation
+	// that reports whether it added or removed a key, so Len need not iterate
+	size atomic.Int64
+
+	// computing holds a *computeEntry per key while GetOrCompute runs compute
+	computing sync.Map
+}
+
+// computeEntry guards a single GetOrCompute computation for one key.
+type computeEntry[V any] struct {
+	once  sync.Once
+	value V
+	done  bool
 }
 
 // Store sets the value for key k. It overwrites any existing value for k.
@@ -21,7 +39,9 @@ type SafeMap[K comparable, V any] struct {
 //   - k: The key to store
 //   - v: The value to associate with k
 func (m *SafeMap[K, V]) Store(k K, v V) {
-	m.m.Store(k, v)
+	if _, loaded := m.m.Swap(k, v); !loaded {
+		m.size.Add(1)
+	}
 }
 
 // Set sets the value for key k. It is equivalent to Store and overwrites
@@ -66,19 +86,155 @@ func (m *SafeMap[K, V]) Get(k K) (V, bool) {
 	return m.Load(k)
 }
 
+// LoadOrStore returns the existing value for key k if present. Otherwise it
+// stores v and returns it. The check and the insert happen atomically, so
+// when several goroutines race on the same key exactly one value wins.
+//
+// Parameters:
+//   - k: The key to look up or insert
+//   - v: The value to store if k is absent
+//
+// Returns:
+//   - The existing value for k if present, otherwise v
+//   - true if the value was loaded, false if v was stored
+func (m *SafeMap[K, V]) LoadOrStore(k K, v V) (actual V, loaded bool) {
+	a, loaded := m.m.LoadOrStore(k, v)
+	if !loaded {
+		m.size.Add(1)
+	}
+	return a.(V), loaded
+}
+
+// GetOrCompute returns the existing value for key k if present. Otherwise it
+// calls compute, stores the result, and returns it. Unlike LoadOrStore, the
+// value is only built when needed, and concurrent callers for the same key
+// run compute at most once; the others wait and receive its result. If
+// compute panics, nothing is stored and a waiting caller retries.
+//
+// Parameters:
+//   - k: The key to look up or compute
+//   - compute: Function producing the value when k is absent
+//
+// Returns:
+//   - The existing or computed value for k
+//   - true if this call ran compute, false if the value already existed
+//     or was computed by another caller
+func (m *SafeMap[K, V]) GetOrCompute(k K, compute func() V) (V, bool) {
+	for {
+		if v, found := m.Load(k); found {
+			return v, false
+		}
+
+		actual, _ := m.computing.LoadOrStore(k, &computeEntry[V]{})
+		entry := actual.(*computeEntry[V])
+
+		computed := false
+		entry.once.Do(func() {
+			defer m.computing.CompareAndDelete(k, entry)
+
+			// Another caller may have finished between Load and claiming the entry
+			if v, found := m.Load(k); found {
+				entry.value, entry.done = v, true
+				return
+			}
+
+			entry.value = compute()
+			m.Store(k, entry.value)
+			entry.done, computed = true, true
+		})
+
+		if entry.done {
+			return entry.value, computed
+		}
+	}
+}
+
+// LoadAndDelete removes the entry for key k and returns the value it held.
+// The read and the removal happen atomically, so when several goroutines
+// race to take the same key exactly one of them observes it.
+//
+// Parameters:
+//   - k: The key to take
+//
+// Returns:
+//   - The value that was associated with k, or the zero value of V if not found
+//   - true if the key was present and removed, false otherwise
+func (m *SafeMap[K, V]) LoadAndDelete(k K) (V, bool) {
+	v, loaded := m.m.LoadAndDelete(k)
+	if !loaded {
+		var empty V
+		return empty, loaded
+	}
+
+	m.size.Add(-1)
+	return v.(V), loaded
+}
+
 // Delete removes the entry for key k. It is safe to call for a key that
 // is not in the map; the call is a no-op in that case.
 //
 // Parameters:
 //   - k: The key to delete
 func (m *SafeMap[K, V]) Delete(k K) {
-	m.m.Delete(k)
+	if _, loaded := m.m.LoadAndDelete(k); loaded {
+		m.size.Add(-1)
+	}
+}
+
+// DeleteFunc removes every entry for which pred returns true, in a single
+// pass over the map. Deleting while iterating is permitted by sync.Map, so
+// this is safe; entries stored concurrently may or may not be visited.
+// Because Range may hand pred a value that has since been replaced, pred is
+// called again on the current value just before the entry is removed, so an
+// update that no longer matches survives. An update landing between that
+// second check and the removal can still be lost, as with any check-then-act
+// on the map.
+//
+// Parameters:
+//   - pred: Function called for each entry; return true to delete it
+//
+// Returns:
+//   - The number of entries deleted
+func (m *SafeMap[K, V]) DeleteFunc(pred func(k K, v V) bool) int {
+	deleted := 0
+	m.m.Range(func(k, v any) bool {
+		if !pred(k.(K), v.(V)) {
+			return true
+		}
+
+		cur, ok := m.m.Load(k)
+		if !ok || !pred(k.(K), cur.(V)) {
+			return true
+		}
+
+		if _, loaded := m.m.LoadAndDelete(k); loaded {
+			m.size.Add(-1)
+			deleted++
+		}
+		return true
+	})
+
+	return deleted
+}
+
+// Clear removes all entries from the map, leaving it empty. It deletes the
+// entries one by one so that Len stays accurate, and is O(n). A Range running
+// concurrently may still visit entries that existed before Clear, and entries
+// stored concurrently with Clear may or may not survive it.
+func (m *SafeMap[K, V]) Clear() {
+	m.m.Range(func(k, _ any) bool {
+		if _, loaded := m.m.LoadAndDelete(k); loaded {
+			m.size.Add(-1)
+		}
+		return true
+	})
 }
 
 // Range calls f sequentially for each key and value present in the map.
-// If f returns false, Range stops the iteration. Range does not support
-// concurrent modification of the map from within f; the behavior is
-// undefined if the map is modified during iteration.
+// If f returns false, Range stops the iteration. f may store or delete keys,
+// as may other goroutines while Range runs, since sync.Map permits this. Each
+// key is visited at most once, but entries stored or deleted during the
+// iteration may or may not be visited.
 //
 // Parameters:
 //   - f: Function called for each entry; return false to stop iteration
@@ -88,19 +244,103 @@ func (m *SafeMap[K, V]) Range(f func(k K, v V) bool) {
 	})
 }
 
-// Len returns the number of entries in the map. It iterates over all entries
-// to compute the count; use sparingly on large maps.
+// Len returns the number of entries in the map in O(1) from a counter kept up
+// to date by every insert and removal. When no writes are in progress it is
+// exact. While writes run concurrently it may briefly lag them, since each
+// operation updates the counter just after the map; it never reports less
+// than zero.
 //
 // Returns:
 //   - The number of key-value pairs in the map
 func (m *SafeMap[K, V]) Len() int {
-	length := 0
+	if n := m.size.Load(); n > 0 {
+		return int(n)
+	}
+
+	return 0
+}
+
+// Keys returns the keys present in the map in unspecified order. It iterates
+// over all entries once and returns a point-in-time view that may miss
+// concurrent modifications.
+//
+// Returns:
+//   - A new slice holding every key
+func (m *SafeMap[K, V]) Keys() []K {
+	keys := make([]K, 0)
+	m.Range(func(k K, v V) bool {
+		keys = append(keys, k)
+		return true
+	})
+
+	return keys
+}
+
+// Values returns the values present in the map in unspecified order. It
+// iterates over all entries once and returns a point-in-time view that may
+// miss concurrent modifications.
+//
+// Returns:
+//   - A new slice holding every value
+func (m *SafeMap[K, V]) Values() []V {
+	values := make([]V, 0)
+	m.Range(func(k K, v V) bool {
+		values = append(values, v)
+		return true
+	})
+
+	return values
+}
+
+// ToMap returns a copy of the map's entries as a plain map. It iterates over
+// all entries once and returns a point-in-time view that may miss concurrent
+// modifications. Changes to the copy do not affect the SafeMap.
+//
+// Returns:
+//   - A new map holding every key-value pair
+func (m *SafeMap[K, V]) ToMap() map[K]V {
+	snapshot := make(map[K]V)
 	m.Range(func(k K, v V) bool {
-		length++
+		snapshot[k] = v
 		return true
 	})
 
-	return length
+	return snapshot
+}
+
+// MarshalJSON encodes the map as a JSON object. It takes a snapshot with
+// ToMap and encodes that, so key types follow encoding/json rules: string and
+// integer kinds and encoding.TextMarshaler are accepted, other key types
+// return an error.
+//
+// Returns:
+//   - The JSON encoding of the map's entries
+//   - An error if a key or value cannot be encoded
+func (m *SafeMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.ToMap())
+}
+
+// UnmarshalJSON decodes a JSON object and replaces the map's contents with
+// its entries. Key types follow the same rules as MarshalJSON. On error the
+// map is left unchanged.
+//
+// Parameters:
+//   - data: The JSON object to decode
+//
+// Returns:
+//   - An error if data is not a JSON object of the expected key and value types
+func (m *SafeMap[K, V]) UnmarshalJSON(data []byte) error {
+	var decoded map[K]V
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	m.Clear()
+	for k, v := range decoded {
+		m.Store(k, v)
+	}
+
+	return nil
 }
 
 // Has reports whether key k is present in the map.
@@ -123,3 +363,31 @@ func (m *SafeMap[K, V]) Has(k K) bool {
 func NewSafeMap[K comparable, V any]() *SafeMap[K, V] {
 	return &SafeMap[K, V]{}
 }
+
+// Increment atomically adds delta to the value stored for key k and returns
+// the new value. A missing key is treated as zero. Concurrent increments of
+// the same key never lose updates; each retries with compare-and-swap until
+// it applies. Increment is a function rather than a method because it needs
+// the value type to be int64.
+//
+// Parameters:
+//   - m: The map holding the counters
+//   - k: The key to increment
+//   - delta: The amount to add; may be negative
+//
+// Returns:
+//   - The value for k after adding delta
+func Increment[K comparable](m *SafeMap[K, int64], k K, delta int64) int64 {
+	for {
+		old, loaded := m.m.LoadOrStore(k, delta)
+		if !loaded {
+			m.size.Add(1)
+			return delta
+		}
+
+		updated := old.(int64) + delta
+		if m.m.CompareAndSwap(k, old, updated) {
+			return updated
+		}
+	}
+}
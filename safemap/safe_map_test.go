@@ -1,8 +1,11 @@
 package safemap
 
 import (
+	"encoding/json"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,6 +80,242 @@ func TestSafeMap_Delete(t *testing.T) {
 	})
 }
 
+func TestSafeMap_DeleteFunc(t *testing.T) {
+	m := NewSafeMap[int, int]()
+	for i := range 100 {
+		m.Store(i, i)
+	}
+
+	// Evict entries with odd values
+	deleted := m.DeleteFunc(func(k, v int) bool { return v%2 == 1 })
+	assert.Equal(t, 50, deleted)
+	assert.Equal(t, 50, m.Len())
+	m.Range(func(k, v int) bool {
+		assert.Equal(t, 0, v%2)
+		return true
+	})
+
+	assert.Equal(t, 0, m.DeleteFunc(func(k, v int) bool { return false }))
+	assert.Equal(t, 50, m.Len())
+
+	t.Run("keeps an entry updated after it was visited", func(t *testing.T) {
+		m := NewSafeMap[string, int]()
+		m.Store("a", 1)
+
+		// Simulate a writer replacing the value while pred runs on the old one
+		deleted := m.DeleteFunc(func(k string, v int) bool {
+			if v == 1 {
+				m.Store(k, 2)
+			}
+			return v == 1
+		})
+
+		assert.Equal(t, 0, deleted)
+		v, ok := m.Load("a")
+		assert.True(t, ok)
+		assert.Equal(t, 2, v)
+	})
+}
+
+func TestSafeMap_Clear(t *testing.T) {
+	m := NewSafeMap[string, int]()
+	m.Store("a", 1)
+	m.Store("b", 2)
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	assert.False(t, m.Has("a"))
+
+	// The map remains usable after Clear
+	m.Store("c", 3)
+	v, ok := m.Load("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+}
+
+func TestSafeMap_LoadOrStore(t *testing.T) {
+	m := NewSafeMap[string, int]()
+
+	t.Run("stores when key is absent", func(t *testing.T) {
+		actual, loaded := m.LoadOrStore("a", 1)
+		assert.False(t, loaded)
+		assert.Equal(t, 1, actual)
+	})
+
+	t.Run("loads existing value when key is present", func(t *testing.T) {
+		actual, loaded := m.LoadOrStore("a", 2)
+		assert.True(t, loaded)
+		assert.Equal(t, 1, actual)
+		v, _ := m.Load("a")
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("only one concurrent value wins", func(t *testing.T) {
+		const n = 50
+		var wg sync.WaitGroup
+		results := make([]int, n)
+		stored := make([]bool, n)
+		for i := range n {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				actual, loaded := m.LoadOrStore("race", i)
+				results[i] = actual
+				stored[i] = !loaded
+			}(i)
+		}
+		wg.Wait()
+
+		winners := 0
+		for i := range n {
+			if stored[i] {
+				winners++
+			}
+		}
+		assert.Equal(t, 1, winners)
+
+		v, ok := m.Load("race")
+		require.True(t, ok)
+		for i := range n {
+			assert.Equal(t, v, results[i])
+		}
+	})
+}
+
+func TestSafeMap_GetOrCompute(t *testing.T) {
+	m := NewSafeMap[string, int]()
+
+	t.Run("computes when key is absent", func(t *testing.T) {
+		v, computed := m.GetOrCompute("a", func() int { return 1 })
+		assert.True(t, computed)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("returns existing value without computing", func(t *testing.T) {
+		v, computed := m.GetOrCompute("a", func() int {
+			t.Fatal("compute called for existing key")
+			return 0
+		})
+		assert.False(t, computed)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("concurrent callers compute once", func(t *testing.T) {
+		const n = 50
+		var calls, computedCount atomic.Int32
+		var wg sync.WaitGroup
+		results := make([]int, n)
+		start := make(chan struct{})
+		for i := range n {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				v, computed := m.GetOrCompute("memo", func() int {
+					calls.Add(1)
+					time.Sleep(10 * time.Millisecond)
+					return 99
+				})
+				if computed {
+					computedCount.Add(1)
+				}
+				results[i] = v
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, int32(1), computedCount.Load())
+		for i := range n {
+			assert.Equal(t, 99, results[i])
+		}
+	})
+
+	t.Run("panic in compute stores nothing", func(t *testing.T) {
+		assert.Panics(t, func() {
+			m.GetOrCompute("boom", func() int { panic("fail") })
+		})
+		assert.False(t, m.Has("boom"))
+
+		v, computed := m.GetOrCompute("boom", func() int { return 7 })
+		assert.True(t, computed)
+		assert.Equal(t, 7, v)
+	})
+}
+
+func TestSafeMap_LoadAndDelete(t *testing.T) {
+	m := NewSafeMap[string, int]()
+	m.Store("a", 1)
+
+	t.Run("returns value and removes key", func(t *testing.T) {
+		v, loaded := m.LoadAndDelete("a")
+		assert.True(t, loaded)
+		assert.Equal(t, 1, v)
+		assert.False(t, m.Has("a"))
+	})
+
+	t.Run("missing key returns zero value and false", func(t *testing.T) {
+		v, loaded := m.LoadAndDelete("a")
+		assert.False(t, loaded)
+		assert.Equal(t, 0, v)
+	})
+
+	t.Run("only one concurrent caller takes the key", func(t *testing.T) {
+		m.Store("job", 42)
+
+		const n = 50
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		taken := 0
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if v, loaded := m.LoadAndDelete("job"); loaded {
+					assert.Equal(t, 42, v)
+					mu.Lock()
+					taken++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, taken)
+	})
+}
+
+func TestIncrement(t *testing.T) {
+	m := NewSafeMap[string, int64]()
+
+	t.Run("missing key starts at zero", func(t *testing.T) {
+		assert.Equal(t, int64(5), Increment(m, "a", 5))
+		assert.Equal(t, int64(3), Increment(m, "a", -2))
+		v, _ := m.Load("a")
+		assert.Equal(t, int64(3), v)
+	})
+
+	t.Run("no lost updates under concurrency", func(t *testing.T) {
+		const goroutines = 50
+		const perGoroutine = 200
+		var wg sync.WaitGroup
+		for range goroutines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range perGoroutine {
+					Increment(m, "hits", 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		v, _ := m.Load("hits")
+		assert.Equal(t, int64(goroutines*perGoroutine), v)
+	})
+}
+
 func TestSafeMap_Has(t *testing.T) {
 	m := NewSafeMap[int, struct{}]()
 	m.Store(1, struct{}{})
@@ -101,6 +340,76 @@ func TestSafeMap_Len(t *testing.T) {
 	assert.Equal(t, 0, m.Len())
 }
 
+func TestSafeMap_LenMatchesRange(t *testing.T) {
+	rangeCount := func(m *SafeMap[int, int]) int {
+		n := 0
+		m.Range(func(k, v int) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	t.Run("sequential mixed operations", func(t *testing.T) {
+		m := NewSafeMap[int, int]()
+		m.Store(1, 1)
+		m.Store(1, 2) // overwrite does not grow the map
+		m.Set(2, 2)
+		m.LoadOrStore(2, 3)
+		m.LoadOrStore(3, 3)
+		m.GetOrCompute(4, func() int { return 4 })
+		m.Delete(1)
+		m.Delete(1) // missing key
+		m.LoadAndDelete(2)
+		m.LoadAndDelete(2)
+		assert.Equal(t, rangeCount(m), m.Len())
+		assert.Equal(t, 2, m.Len())
+
+		m.DeleteFunc(func(k, v int) bool { return k == 3 })
+		assert.Equal(t, 1, m.Len())
+
+		m.Clear()
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("concurrent mixed operations", func(t *testing.T) {
+		m := NewSafeMap[int, int]()
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := range 500 {
+					k := (g*31 + i) % 64
+					switch i % 5 {
+					case 0:
+						m.Store(k, i)
+					case 1:
+						m.LoadOrStore(k, i)
+					case 2:
+						m.Delete(k)
+					case 3:
+						m.LoadAndDelete(k)
+					case 4:
+						m.GetOrCompute(k, func() int { return i })
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		assert.Equal(t, rangeCount(m), m.Len())
+	})
+
+	t.Run("increment counts new keys", func(t *testing.T) {
+		m := NewSafeMap[string, int64]()
+		Increment(m, "a", 1)
+		Increment(m, "a", 1)
+		Increment(m, "b", 1)
+		assert.Equal(t, 2, m.Len())
+	})
+}
+
 func TestSafeMap_Range(t *testing.T) {
 	m := NewSafeMap[string, int]()
 	m.Store("a", 1)
@@ -139,6 +448,93 @@ func TestSafeMap_Range(t *testing.T) {
 	})
 }
 
+func TestSafeMap_Keys_Values_ToMap(t *testing.T) {
+	m := NewSafeMap[string, int]()
+
+	t.Run("empty map returns empty results", func(t *testing.T) {
+		assert.Empty(t, m.Keys())
+		assert.Empty(t, m.Values())
+		assert.Empty(t, m.ToMap())
+	})
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("c", 3)
+
+	t.Run("keys", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, m.Keys())
+	})
+
+	t.Run("values", func(t *testing.T) {
+		assert.ElementsMatch(t, []int{1, 2, 3}, m.Values())
+	})
+
+	t.Run("to map is an independent copy", func(t *testing.T) {
+		snapshot := m.ToMap()
+		assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, snapshot)
+
+		snapshot["d"] = 4
+		m.Delete("a")
+		assert.False(t, m.Has("d"))
+		assert.Equal(t, 1, snapshot["a"])
+	})
+}
+
+func TestSafeMap_JSON(t *testing.T) {
+	type endpoint struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	t.Run("marshals string keys and struct values", func(t *testing.T) {
+		m := NewSafeMap[string, endpoint]()
+		m.Store("primary", endpoint{Host: "db1", Port: 5432})
+		m.Store("replica", endpoint{Host: "db2", Port: 5433})
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"primary":{"host":"db1","port":5432},"replica":{"host":"db2","port":5433}}`, string(data))
+	})
+
+	t.Run("unmarshal round trip replaces contents", func(t *testing.T) {
+		m := NewSafeMap[string, endpoint]()
+		m.Store("stale", endpoint{Host: "old"})
+
+		data := []byte(`{"primary":{"host":"db1","port":5432}}`)
+		require.NoError(t, json.Unmarshal(data, m))
+		assert.Equal(t, map[string]endpoint{"primary": {Host: "db1", Port: 5432}}, m.ToMap())
+	})
+
+	t.Run("works as a struct field", func(t *testing.T) {
+		type config struct {
+			Endpoints *SafeMap[string, endpoint] `json:"endpoints"`
+		}
+
+		var cfg config
+		require.NoError(t, json.Unmarshal([]byte(`{"endpoints":{"a":{"host":"h","port":1}}}`), &cfg))
+		require.NotNil(t, cfg.Endpoints)
+		v, ok := cfg.Endpoints.Load("a")
+		assert.True(t, ok)
+		assert.Equal(t, endpoint{Host: "h", Port: 1}, v)
+	})
+
+	t.Run("invalid json leaves map unchanged", func(t *testing.T) {
+		m := NewSafeMap[string, int]()
+		m.Store("a", 1)
+
+		assert.Error(t, json.Unmarshal([]byte(`["not","an","object"]`), m))
+		assert.Equal(t, map[string]int{"a": 1}, m.ToMap())
+	})
+
+	t.Run("unsupported key type is rejected", func(t *testing.T) {
+		m := NewSafeMap[[2]int, int]()
+		m.Store([2]int{1, 2}, 3)
+
+		_, err := json.Marshal(m)
+		assert.Error(t, err)
+	})
+}
+
 func TestSafeMap_ZeroValueType(t *testing.T) {
 	t.Run("pointer value zero is nil", func(t *testing.T) {
 		m := NewSafeMap[string, *int]()
@@ -14,6 +14,22 @@ func NewSafeSet[T comparable]() *SafeSet[T] {
 	return &SafeSet[T]{m: make(map[T]struct{})}
 }
 
+// NewSafeSetFromSlice creates a SafeSet holding the elements of items, with
+// duplicates removed. It is O(n) in the length of items.
+//
+// Parameters:
+//   - items: The elements to add; may be nil
+//
+// Returns:
+//   - A new SafeSet containing each distinct element of items
+func NewSafeSetFromSlice[T comparable](items []T) *SafeSet[T] {
+	s := &SafeSet[T]{m: make(map[T]struct{}, len(items))}
+	for _, item := range items {
+		s.m[item] = struct{}{}
+	}
+	return s
+}
+
 // Add adds an element to the set.
 //
 // Parameters:
@@ -24,6 +40,25 @@ func (s *SafeSet[T]) Add(value T) {
 	s.m[value] = struct{}{}
 }
 
+// AddIfAbsent adds an element to the set if it is not already present. The
+// check and the insert happen under one write lock, so exactly one of several
+// concurrent callers adding the same element sees true.
+//
+// Parameters:
+//   - value: The element to add
+//
+// Returns:
+//   - true if the element was newly added, false if it was already present
+func (s *SafeSet[T]) AddIfAbsent(value T) bool {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.m[value]; ok {
+		return false
+	}
+	s.m[value] = struct{}{}
+	return true
+}
+
 // Remove removes an element from the set.
 //
 // Parameters:
@@ -34,6 +69,70 @@ func (s *SafeSet[T]) Remove(value T) {
 	delete(s.m, value)
 }
 
+// AddAll adds every given element to the set, taking the write lock once for
+// the whole batch.
+//
+// Parameters:
+//   - values: The elements to add
+func (s *SafeSet[T]) AddAll(values ...T) {
+	s.Lock()
+	defer s.Unlock()
+	for _, v := range values {
+		s.m[v] = struct{}{}
+	}
+}
+
+// RemoveAll removes every given element from the set, taking the write lock
+// once for the whole batch. Elements not in the set are ignored.
+//
+// Parameters:
+//   - values: The elements to remove
+func (s *SafeSet[T]) RemoveAll(values ...T) {
+	s.Lock()
+	defer s.Unlock()
+	for _, v := range values {
+		delete(s.m, v)
+	}
+}
+
+// ContainsAll reports whether the set contains every given element. It is
+// true when no values are given.
+//
+// Parameters:
+//   - values: The elements to look up
+//
+// Returns:
+//   - true if all values are in the set, false otherwise
+func (s *SafeSet[T]) ContainsAll(values ...T) bool {
+	s.RLock()
+	defer s.RUnlock()
+	for _, v := range values {
+		if _, ok := s.m[v]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny reports whether the set contains at least one of the given
+// elements. It is false when no values are given.
+//
+// Parameters:
+//   - values: The elements to look up
+//
+// Returns:
+//   - true if any value is in the set, false otherwise
+func (s *SafeSet[T]) ContainsAny(values ...T) bool {
+	s.RLock()
+	defer s.RUnlock()
+	for _, v := range values {
+		if _, ok := s.m[v]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Contains reports whether the set contains the given element.
 //
 // Parameters:
@@ -101,6 +200,74 @@ func (s *SafeSet[T]) Union(other *SafeSet[T]) *SafeSet[T] {
 	return result
 }
 
+// IsSubset reports whether every element of this set is also in the other set.
+// An empty set is a subset of every set.
+//
+// Parameters:
+//   - other: The set to compare against
+//
+// Returns:
+//   - true if this set is a subset of other, false otherwise
+func (s *SafeSet[T]) IsSubset(other *SafeSet[T]) bool {
+	if s == other {
+		return true
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+	other.RLock()
+	defer other.RUnlock()
+	return isSubset(s.m, other.m)
+}
+
+// IsSuperset reports whether every element of the other set is also in this set.
+// Every set is a superset of an empty set.
+//
+// Parameters:
+//   - other: The set to compare against
+//
+// Returns:
+//   - true if this set is a superset of other, false otherwise
+func (s *SafeSet[T]) IsSuperset(other *SafeSet[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equals reports whether this set and the other set contain exactly the same
+// elements. Sets of different sizes are rejected without comparing elements.
+//
+// Parameters:
+//   - other: The set to compare against
+//
+// Returns:
+//   - true if both sets contain the same elements, false otherwise
+func (s *SafeSet[T]) Equals(other *SafeSet[T]) bool {
+	if s == other {
+		return true
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+	other.RLock()
+	defer other.RUnlock()
+	if len(s.m) != len(other.m) {
+		return false
+	}
+	return isSubset(s.m, other.m)
+}
+
+// isSubset reports whether every key of a is in b. Callers must hold read locks on both sets.
+func isSubset[T comparable](a, b map[T]struct{}) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // Reset removes all elements from the set, leaving it empty.
 func (s *SafeSet[T]) Reset() {
 	s.Lock()
@@ -108,6 +275,36 @@ func (s *SafeSet[T]) Reset() {
 	s.m = make(map[T]struct{})
 }
 
+// Clone returns a new set holding the same elements. The copy is independent:
+// later changes to either set do not affect the other.
+//
+// Returns:
+//   - A new SafeSet with the elements of this set
+func (s *SafeSet[T]) Clone() *SafeSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	m := make(map[T]struct{}, len(s.m))
+	for k := range s.m {
+		m[k] = struct{}{}
+	}
+	return &SafeSet[T]{m: m}
+}
+
+// ToSlice returns the elements of the set in unspecified order. It is O(n) and
+// returns a point-in-time view; later changes to the set are not reflected.
+//
+// Returns:
+//   - A new slice holding every element
+func (s *SafeSet[T]) ToSlice() []T {
+	s.RLock()
+	defer s.RUnlock()
+	result := make([]T, 0, len(s.m))
+	for k := range s.m {
+		result = append(result, k)
+	}
+	return result
+}
+
 // Range calls the function f for each element in the set. Iteration stops if f
 // returns false. The behavior is undefined if f modifies the set.
 //
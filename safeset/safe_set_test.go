@@ -2,6 +2,7 @@ package safeset
 
 import (
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,6 +16,23 @@ func TestNewSafeSet(t *testing.T) {
 	assert.False(t, s.Contains("x"))
 }
 
+func TestNewSafeSetFromSlice(t *testing.T) {
+	t.Run("de-duplicates items", func(t *testing.T) {
+		s := NewSafeSetFromSlice([]string{"a", "b", "a", "c", "b"})
+		assert.Equal(t, 3, s.Size())
+		assert.True(t, s.Contains("a"))
+		assert.True(t, s.Contains("b"))
+		assert.True(t, s.Contains("c"))
+	})
+
+	t.Run("nil slice gives empty usable set", func(t *testing.T) {
+		s := NewSafeSetFromSlice[int](nil)
+		assert.Equal(t, 0, s.Size())
+		s.Add(1)
+		assert.True(t, s.Contains(1))
+	})
+}
+
 func TestSafeSet_Add_Contains(t *testing.T) {
 	s := NewSafeSet[string]()
 
@@ -36,6 +54,30 @@ func TestSafeSet_Add_Contains(t *testing.T) {
 	})
 }
 
+func TestSafeSet_AddIfAbsent(t *testing.T) {
+	s := NewSafeSet[string]()
+	assert.True(t, s.AddIfAbsent("a"))
+	assert.False(t, s.AddIfAbsent("a"))
+	assert.Equal(t, 1, s.Size())
+
+	t.Run("exactly one concurrent caller wins", func(t *testing.T) {
+		s := NewSafeSet[int]()
+		var wg sync.WaitGroup
+		var wins atomic.Int32
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if s.AddIfAbsent(42) {
+					wins.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), wins.Load())
+	})
+}
+
 func TestSafeSet_Remove(t *testing.T) {
 	s := NewSafeSet[string]()
 	s.Add("a")
@@ -54,6 +96,36 @@ func TestSafeSet_Remove(t *testing.T) {
 	})
 }
 
+func TestSafeSet_BulkOperations(t *testing.T) {
+	t.Run("add all and remove all", func(t *testing.T) {
+		s := NewSafeSet[int]()
+		s.AddAll(1, 2, 3, 2)
+		assert.Equal(t, 3, s.Size())
+
+		s.RemoveAll(1, 3, 99)
+		assert.Equal(t, 1, s.Size())
+		assert.True(t, s.Contains(2))
+
+		s.AddAll()
+		s.RemoveAll()
+		assert.Equal(t, 1, s.Size())
+	})
+
+	t.Run("contains all", func(t *testing.T) {
+		s := NewSafeSetFromSlice([]string{"a", "b", "c"})
+		assert.True(t, s.ContainsAll("a", "c"))
+		assert.False(t, s.ContainsAll("a", "z"))
+		assert.True(t, s.ContainsAll())
+	})
+
+	t.Run("contains any", func(t *testing.T) {
+		s := NewSafeSetFromSlice([]string{"a", "b", "c"})
+		assert.True(t, s.ContainsAny("z", "b"))
+		assert.False(t, s.ContainsAny("x", "y"))
+		assert.False(t, s.ContainsAny())
+	})
+}
+
 func TestSafeSet_Size(t *testing.T) {
 	s := NewSafeSet[int]()
 
@@ -164,6 +236,67 @@ func TestSafeSet_Union(t *testing.T) {
 	})
 }
 
+func TestSafeSet_SubsetSupersetEquals(t *testing.T) {
+	newSet := func(values ...int) *SafeSet[int] {
+		s := NewSafeSet[int]()
+		for _, v := range values {
+			s.Add(v)
+		}
+		return s
+	}
+
+	t.Run("proper subset", func(t *testing.T) {
+		a := newSet(1, 2)
+		b := newSet(1, 2, 3)
+		assert.True(t, a.IsSubset(b))
+		assert.False(t, b.IsSubset(a))
+		assert.True(t, b.IsSuperset(a))
+		assert.False(t, a.IsSuperset(b))
+		assert.False(t, a.Equals(b))
+	})
+
+	t.Run("equal sets", func(t *testing.T) {
+		a := newSet(1, 2, 3)
+		b := newSet(3, 2, 1)
+		assert.True(t, a.IsSubset(b))
+		assert.True(t, a.IsSuperset(b))
+		assert.True(t, a.Equals(b))
+		assert.True(t, a.Equals(a))
+	})
+
+	t.Run("same size but different elements", func(t *testing.T) {
+		a := newSet(1, 2)
+		b := newSet(1, 3)
+		assert.False(t, a.IsSubset(b))
+		assert.False(t, a.IsSuperset(b))
+		assert.False(t, a.Equals(b))
+	})
+
+	t.Run("empty receiver", func(t *testing.T) {
+		empty := newSet()
+		b := newSet(1)
+		assert.True(t, empty.IsSubset(b))
+		assert.False(t, empty.IsSuperset(b))
+		assert.False(t, empty.Equals(b))
+	})
+
+	t.Run("empty other", func(t *testing.T) {
+		a := newSet(1)
+		empty := newSet()
+		assert.False(t, a.IsSubset(empty))
+		assert.True(t, a.IsSuperset(empty))
+		assert.False(t, a.Equals(empty))
+	})
+
+	t.Run("both empty", func(t *testing.T) {
+		a := newSet()
+		b := newSet()
+		assert.True(t, a.IsSubset(b))
+		assert.True(t, a.IsSuperset(b))
+		assert.True(t, a.Equals(b))
+	})
+}
+
 func TestSafeSet_Reset(t *testing.T) {
 	s := NewSafeSet[int]()
 	s.Add(1)
@@ -181,6 +314,30 @@ func TestSafeSet_Reset(t *testing.T) {
 	assert.True(t, s.Contains(3))
 }
 
+func TestSafeSet_Clone(t *testing.T) {
+	s := NewSafeSetFromSlice([]int{1, 2, 3})
+	c := s.Clone()
+	assert.True(t, c.Equals(s))
+
+	c.Add(4)
+	s.Remove(1)
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, c.ToSlice())
+	assert.ElementsMatch(t, []int{2, 3}, s.ToSlice())
+
+	assert.Equal(t, 0, NewSafeSet[int]().Clone().Size())
+}
+
+func TestSafeSet_ToSlice(t *testing.T) {
+	s := NewSafeSetFromSlice([]int{3, 1, 2})
+	got := s.ToSlice()
+	assert.ElementsMatch(t, []int{1, 2, 3}, got)
+
+	// The slice is a snapshot independent of the set
+	s.Add(4)
+	assert.Len(t, got, 3)
+	assert.Empty(t, NewSafeSet[int]().ToSlice())
+}
+
 func TestSafeSet_Range(t *testing.T) {
 	s := NewSafeSet[string]()
 	s.Add("a")
@@ -0,0 +1,67 @@
+package tcpserver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxMessageSize is the frame size limit used when TCPServer.MaxMessageSize is 0.
+// It matches the limit enforced by the event-driven TCP client.
+const DefaultMaxMessageSize = 16 * 1024 * 1024
+
+// lengthPrefixSize is the size of the little-endian length prefix in front of each frame.
+const lengthPrefixSize = 4
+
+// ReadLengthPrefixed reads one length-prefixed frame from r. The frame starts with a
+// 4-byte little-endian length that counts the whole frame, prefix included, which is
+// the same framing the event-driven TCP client uses. The advertised length is checked
+// against maxSize before the payload buffer is allocated, so a peer cannot force a huge
+// allocation by sending a large prefix.
+//
+// Parameters:
+//   - r: The reader to read from (typically the session's net.Conn)
+//   - maxSize: The largest accepted frame length in bytes, prefix included
+//
+// Returns:
+//   - The payload without the length prefix
+//   - An error if reading fails, the length is smaller than the prefix, or it exceeds maxSize
+func ReadLengthPrefixed(r io.Reader, maxSize uint32) ([]byte, error) {
+	var header [lengthPrefixSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	length := binary.LittleEndian.Uint32(header[:])
+	if length < lengthPrefixSize {
+		return nil, fmt.Errorf("invalid message length %d", length)
+	}
+	if length > maxSize {
+		return nil, fmt.Errorf("message size %d exceeds maximum %d", length, maxSize)
+	}
+
+	payload := make([]byte, length-lengthPrefixSize)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// ReadMessage reads one length-prefixed frame from r using ReadLengthPrefixed,
+// enforcing the server's MaxMessageSize. Sessions should use it instead of decoding
+// frames themselves so the limit is applied consistently.
+//
+// Parameters:
+//   - r: The reader to read from (typically the session's net.Conn)
+//
+// Returns:
+//   - The payload without the length prefix, or an error as described for ReadLengthPrefixed
+func (s *TCPServer) ReadMessage(r io.Reader) ([]byte, error) {
+	maxSize := s.MaxMessageSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+
+	return ReadLengthPrefixed(r, maxSize)
+}
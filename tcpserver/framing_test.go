@@ -0,0 +1,85 @@
+package tcpserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func frame(payload []byte) []byte {
+	buf := make([]byte, lengthPrefixSize+len(payload))
+	binary.LittleEndian.PutUint32(buf, uint32(len(buf)))
+	copy(buf[lengthPrefixSize:], payload)
+	return buf
+}
+
+func TestReadLengthPrefixed(t *testing.T) {
+	t.Run("reads consecutive frames", func(t *testing.T) {
+		r := bytes.NewReader(append(frame([]byte("hello")), frame([]byte("world"))...))
+
+		payload, err := ReadLengthPrefixed(r, DefaultMaxMessageSize)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(payload))
+
+		payload, err = ReadLengthPrefixed(r, DefaultMaxMessageSize)
+		require.NoError(t, err)
+		assert.Equal(t, "world", string(payload))
+
+		_, err = ReadLengthPrefixed(r, DefaultMaxMessageSize)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("rejects an oversized length before allocating", func(t *testing.T) {
+		header := make([]byte, lengthPrefixSize)
+		binary.LittleEndian.PutUint32(header, 0xFFFFFFFF)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, err := ReadLengthPrefixed(bytes.NewReader(header), DefaultMaxMessageSize)
+		runtime.ReadMemStats(&after)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum")
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+	})
+
+	t.Run("rejects a length smaller than the prefix", func(t *testing.T) {
+		header := make([]byte, lengthPrefixSize)
+		binary.LittleEndian.PutUint32(header, 2)
+
+		_, err := ReadLengthPrefixed(bytes.NewReader(header), DefaultMaxMessageSize)
+		assert.Error(t, err)
+	})
+
+	t.Run("truncated payload returns an error", func(t *testing.T) {
+		data := frame([]byte("hello"))
+		_, err := ReadLengthPrefixed(bytes.NewReader(data[:len(data)-1]), DefaultMaxMessageSize)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
+
+func TestTCPServer_ReadMessage(t *testing.T) {
+	t.Run("enforces MaxMessageSize", func(t *testing.T) {
+		s := &TCPServer{MaxMessageSize: 8}
+
+		payload, err := s.ReadMessage(bytes.NewReader(frame([]byte("ok"))))
+		require.NoError(t, err)
+		assert.Equal(t, "ok", string(payload))
+
+		_, err = s.ReadMessage(bytes.NewReader(frame([]byte("too long"))))
+		assert.ErrorContains(t, err, "exceeds maximum 8")
+	})
+
+	t.Run("zero uses the default limit", func(t *testing.T) {
+		header := make([]byte, lengthPrefixSize)
+		binary.LittleEndian.PutUint32(header, DefaultMaxMessageSize+1)
+
+		_, err := (&TCPServer{}).ReadMessage(bytes.NewReader(header))
+		assert.ErrorContains(t, err, "exceeds maximum")
+	})
+}
@@ -0,0 +1,115 @@
+package tcpserver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+
+	"github.com/cyberinferno/go-utils/idgenerator"
+	"github.com/cyberinferno/go-utils/logger"
+	"github.com/cyberinferno/go-utils/safemap"
+)
+
+// HandlerFunc handles one request payload and returns the response payload.
+// Returning an error closes the connection without sending a response.
+type HandlerFunc func(req []byte) (resp []byte, err error)
+
+// NewEchoStyleServer creates a TCPServer for simple request/response protocols,
+// without implementing TCPServerSession. Each connection reads length-prefixed
+// requests (the framing of ReadLengthPrefixed), calls handle for each one, and
+// writes its response as a length-prefixed frame before reading the next request.
+// Requests on one connection are handled in order; connections are handled
+// concurrently, so handle must be safe for concurrent use.
+//
+// The server logs nothing until Logger is replaced; Name, MaxMessageSize, and the
+// other fields may also be adjusted before calling Start.
+//
+// Parameters:
+//   - addr: The address to listen on (e.g. ":9000")
+//   - handle: The function that turns each request into a response
+//
+// Returns:
+//   - A TCPServer ready for Start or Serve
+func NewEchoStyleServer(addr string, handle HandlerFunc) *TCPServer {
+	s := &TCPServer{
+		Logger:      logger.NewNoopLogger(),
+		Name:        "handler",
+		Addr:        addr,
+		Sessions:    safemap.NewSafeMap[uint32, TCPServerSession](),
+		IdGenerator: idgenerator.NewIdGenerator(0),
+	}
+
+	s.NewSession = func(id uint32, conn net.Conn) TCPServerSession {
+		return &handlerSession{id: id, conn: conn, server: s, handle: handle}
+	}
+
+	return s
+}
+
+// handlerSession is the TCPServerSession used by NewEchoStyleServer.
+type handlerSession struct {
+	id     uint32
+	conn   net.Conn
+	server *TCPServer
+	handle HandlerFunc
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+func (h *handlerSession) ID() uint32 { return h.id }
+
+// Handle reads requests until the connection fails or a handler returns an error.
+func (h *handlerSession) Handle() {
+	defer h.server.RemoveSession(h.id)
+	defer func() { _ = h.Close() }()
+
+	for {
+		req, err := h.server.ReadMessage(h.conn)
+		if err != nil {
+			if err != io.EOF {
+				h.server.log().Debug(fmt.Sprintf("%s server read error", h.server.Name),
+					logger.Field{Key: "session_id", Value: h.id},
+					logger.Field{Key: "error", Value: err})
+			}
+			return
+		}
+
+		resp, err := h.handle(req)
+		if err != nil {
+			h.server.log().Error(fmt.Sprintf("%s server handler error", h.server.Name),
+				logger.Field{Key: "session_id", Value: h.id},
+				logger.Field{Key: "error", Value: err})
+			return
+		}
+
+		if err := h.Send(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (h *handlerSession) Close() error {
+	var err error
+	h.closeOnce.Do(func() { err = h.conn.Close() })
+	return err
+}
+
+// Send writes data as one length-prefixed frame.
+func (h *handlerSession) Send(data []byte) error {
+	if uint64(len(data)) > math.MaxUint32-lengthPrefixSize {
+		return fmt.Errorf("message size %d too large for a length prefix", len(data))
+	}
+
+	frame := make([]byte, lengthPrefixSize+len(data))
+	binary.LittleEndian.PutUint32(frame, uint32(len(frame)))
+	copy(frame[lengthPrefixSize:], data)
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	_, err := h.conn.Write(frame)
+	return err
+}
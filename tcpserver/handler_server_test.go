@@ -0,0 +1,59 @@
+package tcpserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFrame writes payload with the 4-byte little-endian length prefix used by ReadLengthPrefixed.
+func writeFrame(t *testing.T, w io.Writer, payload []byte) {
+	t.Helper()
+	frame := make([]byte, lengthPrefixSize+len(payload))
+	binary.LittleEndian.PutUint32(frame, uint32(len(frame)))
+	copy(frame[lengthPrefixSize:], payload)
+	_, err := w.Write(frame)
+	require.NoError(t, err)
+}
+
+func TestNewEchoStyleServer(t *testing.T) {
+	s := NewEchoStyleServer("127.0.0.1:0", func(req []byte) ([]byte, error) {
+		if string(req) == "fail" {
+			return nil, errors.New("bad request")
+		}
+		return bytes.ToUpper(req), nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = s.Serve(ln) }()
+	require.Eventually(t, s.Running.Load, 2*time.Second, 10*time.Millisecond)
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	for _, req := range []string{"hello", "", "round trip"} {
+		writeFrame(t, conn, []byte(req))
+
+		resp, err := ReadLengthPrefixed(conn, DefaultMaxMessageSize)
+		require.NoError(t, err)
+		assert.Equal(t, bytes.ToUpper([]byte(req)), resp)
+	}
+	assert.Equal(t, 1, s.Sessions.Len())
+
+	// A handler error closes the connection and removes the session
+	writeFrame(t, conn, []byte("fail"))
+	_, err = ReadLengthPrefixed(conn, DefaultMaxMessageSize)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Eventually(t, func() bool { return s.Sessions.Len() == 0 }, 2*time.Second, 10*time.Millisecond)
+}
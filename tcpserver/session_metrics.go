@@ -0,0 +1,67 @@
+package tcpserver
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// SessionInfo holds the traffic counters and start time of a session, as
+// reported by TCPServer.SessionInfo.
+type SessionInfo struct {
+	BytesIn     uint64    // Bytes read from the connection
+	BytesOut    uint64    // Bytes written to the connection
+	ConnectedAt time.Time // When the session was created
+}
+
+// countingConn is a net.Conn that counts the bytes read from and written to
+// the wrapped connection.
+type countingConn struct {
+	net.Conn
+	connectedAt time.Time
+	bytesIn     atomic.Uint64
+	bytesOut    atomic.Uint64
+}
+
+func newCountingConn(conn net.Conn) *countingConn {
+	return &countingConn{Conn: conn, connectedAt: time.Now()}
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.bytesIn.Add(uint64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.bytesOut.Add(uint64(n))
+	return n, err
+}
+
+// info returns a snapshot of the connection's counters.
+func (c *countingConn) info() SessionInfo {
+	return SessionInfo{
+		BytesIn:     c.bytesIn.Load(),
+		BytesOut:    c.bytesOut.Load(),
+		ConnectedAt: c.connectedAt,
+	}
+}
+
+// SessionInfo returns the traffic counters and start time of the session with
+// the given id. Metrics are only collected when TrackSessionMetrics is set, and
+// are dropped when the session is removed with RemoveSession.
+//
+// Parameters:
+//   - id: The session ID to look up
+//
+// Returns:
+//   - The session's metrics and true if found, or a zero value and false otherwise
+func (s *TCPServer) SessionInfo(id uint32) (SessionInfo, bool) {
+	conn, ok := s.metrics.Load(id)
+	if !ok {
+		return SessionInfo{}, false
+	}
+
+	return conn.(*countingConn).info(), true
+}
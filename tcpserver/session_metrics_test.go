@@ -0,0 +1,86 @@
+package tcpserver
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCPServer_SessionInfo(t *testing.T) {
+	ids := make(chan uint32, 1)
+	s, _ := newTestServer(func(s *TCPServer) NewSessionFunc {
+		return func(id uint32, conn net.Conn) TCPServerSession {
+			ids <- id
+			return &echoSession{id: id, conn: conn, server: s}
+		}
+	})
+	s.TrackSessionMetrics = true
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	before := time.Now()
+	conn, err := net.Dial("tcp", s.Listener.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	exchange := func(payload string) {
+		t.Helper()
+		_, err := conn.Write([]byte(payload))
+		require.NoError(t, err)
+
+		buf := make([]byte, len(payload))
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+	}
+
+	exchange("0123456789")
+	id := <-ids
+
+	info, ok := s.SessionInfo(id)
+	require.True(t, ok)
+	assert.Equal(t, uint64(10), info.BytesIn)
+	assert.Equal(t, uint64(10), info.BytesOut)
+	assert.False(t, info.ConnectedAt.Before(before.Truncate(time.Second)))
+	assert.False(t, info.ConnectedAt.After(time.Now()))
+
+	exchange("hello")
+	info, ok = s.SessionInfo(id)
+	require.True(t, ok)
+	assert.Equal(t, uint64(15), info.BytesIn)
+	assert.Equal(t, uint64(15), info.BytesOut)
+
+	// Metrics go away with the session
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		_, ok := s.SessionInfo(id)
+		return !ok
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, ok = s.SessionInfo(id + 100)
+	assert.False(t, ok)
+}
+
+func TestTCPServer_SessionInfo_Disabled(t *testing.T) {
+	ids := make(chan uint32, 1)
+	s, _ := newTestServer(func(s *TCPServer) NewSessionFunc {
+		return func(id uint32, conn net.Conn) TCPServerSession {
+			ids <- id
+			return &echoSession{id: id, conn: conn, server: s}
+		}
+	})
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", s.Listener.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	id := <-ids
+	_, ok := s.SessionInfo(id)
+	assert.False(t, ok)
+}
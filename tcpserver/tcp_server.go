@@ -1,8 +1,12 @@
 package tcpserver
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"net"
+	"slices"
+	"sync"
 	"sync/atomic"
 
 	"github.com/cyberinferno/go-utils/idgenerator"
@@ -13,13 +17,26 @@ import (
 // NewSessionFunc is a function that creates a new TCPServerSession for a given
 // connection. It receives the assigned session ID and the accepted net.Conn,
 // and returns an implementation of TCPServerSession that will handle the connection.
+// Returning nil rejects the connection; the server then closes it.
 type NewSessionFunc func(id uint32, conn net.Conn) TCPServerSession
 
+// NewSessionContextFunc is like NewSessionFunc but also receives the context
+// returned by Authenticate, for example to read the authenticated identity.
+// Returning nil rejects the connection; the server then closes it.
+type NewSessionContextFunc func(ctx context.Context, id uint32, conn net.Conn) TCPServerSession
+
+// AuthenticateFunc performs a handshake on a newly accepted connection before a
+// session is created, for example reading and validating a token. It returns a
+// context carrying the connection's identity, or an error to reject the connection.
+// It should set a deadline on conn so a silent client cannot hold it open.
+type AuthenticateFunc func(conn net.Conn) (context.Context, error)
+
 // TCPServer is a TCP server that accepts connections and delegates each one to a
 // session created by NewSession. Sessions are stored by ID and can be looked up,
 // added, or removed. The server runs its accept loop in a goroutine and supports
 // graceful stop.
 type TCPServer struct {
+	// Logger receives server lifecycle and connection events; nil disables logging.
 	Logger      logger.Logger
 	Name        string
 	Addr        string
@@ -28,39 +45,113 @@ type TCPServer struct {
 	Running     atomic.Bool
 	NewSession  NewSessionFunc
 	IdGenerator *idgenerator.IdGenerator
+
+	// Authenticate, if set, runs on every accepted connection before a session
+	// is created. Connections it rejects are closed without creating a session.
+	Authenticate AuthenticateFunc
+
+	// NewSessionContext, if set, is used instead of NewSession and receives the
+	// context returned by Authenticate (context.Background() without one).
+	NewSessionContext NewSessionContextFunc
+
+	// MaxMessageSize is the largest frame, in bytes, that ReadMessage accepts;
+	// 0 means DefaultMaxMessageSize.
+	MaxMessageSize uint32
+
+	// TrackSessionMetrics, if set, wraps each connection before it is passed to
+	// the session factory so that bytes read and written are counted; see
+	// SessionInfo. Bytes exchanged during Authenticate are not counted.
+	TrackSessionMetrics bool
+
+	metrics sync.Map // session ID -> *countingConn, when TrackSessionMetrics is set
+
+	// lifecycleMu makes setting Running and Listener atomic for startOn and
+	// Stop, so Stop never sees a running server without its listener.
+	lifecycleMu sync.Mutex
+}
+
+// log returns Logger, or a no-op logger when Logger is nil.
+func (s *TCPServer) log() logger.Logger {
+	if s.Logger == nil {
+		return logger.NewNoopLogger()
+	}
+
+	return s.Logger
 }
 
 // Start starts the TCP server by binding to Addr and beginning the accept loop
 // in a goroutine. It is safe to call only when the server is not already running.
+// Use Serve instead to run on a listener created elsewhere.
 //
 // Returns:
 //   - An error if the server is already running or if listening on Addr fails
 func (s *TCPServer) Start() error {
 	if s.Running.Load() {
-		s.Logger.Error("server already running")
+		s.log().Error("server already running")
 		return fmt.Errorf("server %s already running", s.Name)
 	}
 
 	ln, err := net.Listen("tcp", s.Addr)
 	if err != nil {
-		s.Logger.Error("server failed to start", logger.Field{Key: "error", Value: err})
+		s.log().Error("server failed to start", logger.Field{Key: "error", Value: err})
 		return fmt.Errorf("server %s failed to start: %w", s.Name, err)
 	}
 
-	s.Listener = ln
-	s.Running.Store(true)
+	if err := s.startOn(ln); err != nil {
+		_ = ln.Close()
+		return err
+	}
 
-	s.Logger.Info(fmt.Sprintf("%s server started", s.Name), logger.Field{Key: "addr", Value: s.Addr})
 	go s.AcceptLoop()
 
 	return nil
 }
 
+// Serve runs the server on an existing listener, for example one passed in by
+// systemd socket activation or created by a test. Unlike Start it blocks,
+// running the accept loop until Stop is called, like http.Server.Serve.
+// The server takes ownership of ln and closes it on Stop. Addr is ignored.
+//
+// Parameters:
+//   - ln: The listener to accept connections from
+//
+// Returns:
+//   - An error if the server is already running; nil once the server has been stopped
+func (s *TCPServer) Serve(ln net.Listener) error {
+	if err := s.startOn(ln); err != nil {
+		return err
+	}
+
+	s.AcceptLoop()
+
+	return nil
+}
+
+// startOn marks the server as running on ln. It fails if the server is
+// already running.
+func (s *TCPServer) startOn(ln net.Listener) error {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+
+	if s.Running.Load() {
+		s.log().Error("server already running")
+		return fmt.Errorf("server %s already running", s.Name)
+	}
+
+	s.Listener = ln
+	s.Running.Store(true)
+	s.log().Info(fmt.Sprintf("%s server started", s.Name), logger.Field{Key: "addr", Value: ln.Addr().String()})
+
+	return nil
+}
+
 // Stop stops the TCP server: it sets Running to false, closes the listener, and
 // closes all active sessions. Safe to call when the server is not running.
 func (s *TCPServer) Stop() {
+	s.lifecycleMu.Lock()
 	if !s.Running.Load() {
-		s.Logger.Info(fmt.Sprintf("%s server not running", s.Name))
+		s.lifecycleMu.Unlock()
+		s.log().Info(fmt.Sprintf("%s server not running", s.Name))
 		return
 	}
 
@@ -68,6 +159,7 @@ func (s *TCPServer) Stop() {
 	if s.Listener != nil {
 		_ = s.Listener.Close()
 	}
+	s.lifecycleMu.Unlock()
 
 	s.Sessions.Range(func(key uint32, session TCPServerSession) bool {
 		if closer, ok := any(session).(interface{ Close() error }); ok {
@@ -77,7 +169,7 @@ func (s *TCPServer) Stop() {
 		return true
 	})
 
-	s.Logger.Info(fmt.Sprintf("%s server stopped", s.Name))
+	s.log().Info(fmt.Sprintf("%s server stopped", s.Name))
 }
 
 // AddSession stores a session under the given id. It is safe for concurrent use.
@@ -89,13 +181,14 @@ func (s *TCPServer) AddSession(id uint32, session TCPServerSession) {
 	s.Sessions.Store(id, session)
 }
 
-// RemoveSession removes the session with the given id from the server. It is
-// safe for concurrent use.
+// RemoveSession removes the session with the given id from the server, along
+// with its metrics. It is safe for concurrent use.
 //
 // Parameters:
 //   - id: The session ID to remove
 func (s *TCPServer) RemoveSession(id uint32) {
 	s.Sessions.Delete(id)
+	s.metrics.Delete(id)
 }
 
 // GetSession returns the session for the given id, if present.
@@ -109,10 +202,61 @@ func (s *TCPServer) GetSession(id uint32) (TCPServerSession, bool) {
 	return s.Sessions.Get(id)
 }
 
+// SessionsSnapshot returns a copy of the current sessions keyed by ID. The copy
+// is independent of the server: sessions added or removed afterwards are not
+// reflected, so callers can iterate it without holding anything. Sessions that
+// are added or removed while the snapshot is taken may or may not be included.
+//
+// Returns:
+//   - A new map of session ID to session
+func (s *TCPServer) SessionsSnapshot() map[uint32]TCPServerSession {
+	snapshot := make(map[uint32]TCPServerSession)
+	s.Sessions.Range(func(id uint32, session TCPServerSession) bool {
+		snapshot[id] = session
+		return true
+	})
+
+	return snapshot
+}
+
+// BroadcastFunc sends data to every session for which filter returns true, for
+// example sessions subscribed to a topic. Sessions can expose such state through
+// an interface of your own that filter checks with a type assertion. A nil filter
+// sends to every session. Sessions are taken from a snapshot and sent to one at a
+// time in ID order; a failed send does not stop the others.
+//
+// Parameters:
+//   - data: The bytes to send to each matching session
+//   - filter: Reports whether a session should receive data; nil matches all
+//
+// Returns:
+//   - One error per failed send, wrapped with the session ID, or nil if all succeeded
+func (s *TCPServer) BroadcastFunc(data []byte, filter func(TCPServerSession) bool) []error {
+	snapshot := s.SessionsSnapshot()
+
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(snapshot)) {
+		session := snapshot[id]
+		if filter != nil && !filter(session) {
+			continue
+		}
+
+		if err := session.Send(data); err != nil {
+			errs = append(errs, fmt.Errorf("session %d: %w", id, err))
+		}
+	}
+
+	return errs
+}
+
 // AcceptLoop runs in a goroutine and accepts incoming connections. For each
-// connection it assigns an ID via IdGenerator, creates a session with NewSession,
-// stores it with AddSession, and runs session.Handle in a new goroutine. It
-// exits when the server is stopped (Running is false).
+// connection it runs Authenticate if set, assigns an ID via IdGenerator, creates
+// a session with NewSessionContext or NewSession, stores it with AddSession, and
+// runs session.Handle in a new goroutine. If authentication fails or the session
+// factory returns nil, the connection is rejected and closed. When Authenticate
+// is set, each connection is admitted in its own goroutine so a slow handshake
+// does not block accepting others. It exits when the server is stopped
+// (Running is false).
 func (s *TCPServer) AcceptLoop() {
 	for s.Running.Load() {
 		conn, err := s.Listener.Accept()
@@ -121,13 +265,62 @@ func (s *TCPServer) AcceptLoop() {
 				return
 			}
 
-			s.Logger.Error(fmt.Sprintf("%s server accept error", s.Name), logger.Field{Key: "error", Value: err})
+			s.log().Error(fmt.Sprintf("%s server accept error", s.Name), logger.Field{Key: "error", Value: err})
+			continue
+		}
+
+		if s.Authenticate != nil {
+			go s.admit(conn)
 			continue
 		}
 
-		id := s.IdGenerator.Id()
-		session := s.NewSession(id, conn)
-		s.AddSession(id, session)
-		go session.Handle()
+		s.admit(conn)
+	}
+}
+
+// admit authenticates conn, creates its session, and starts handling it, closing
+// conn if it is rejected.
+func (s *TCPServer) admit(conn net.Conn) {
+	ctx := context.Background()
+	if s.Authenticate != nil {
+		authCtx, err := s.Authenticate(conn)
+		if err != nil {
+			s.log().Info(fmt.Sprintf("%s server authentication failed", s.Name),
+				logger.Field{Key: "remote_addr", Value: conn.RemoteAddr().String()},
+				logger.Field{Key: "error", Value: err})
+			_ = conn.Close()
+			return
+		}
+
+		if authCtx != nil {
+			ctx = authCtx
+		}
 	}
+
+	var counted *countingConn
+	if s.TrackSessionMetrics {
+		counted = newCountingConn(conn)
+		conn = counted
+	}
+
+	id := s.IdGenerator.Id()
+	var session TCPServerSession
+	if s.NewSessionContext != nil {
+		session = s.NewSessionContext(ctx, id, conn)
+	} else {
+		session = s.NewSession(id, conn)
+	}
+
+	if session == nil {
+		s.log().Info(fmt.Sprintf("%s server rejected connection", s.Name), logger.Field{Key: "remote_addr", Value: conn.RemoteAddr().String()})
+		_ = conn.Close()
+		return
+	}
+
+	if counted != nil {
+		s.metrics.Store(id, counted)
+	}
+
+	s.AddSession(id, session)
+	go session.Handle()
 }
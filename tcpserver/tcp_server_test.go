@@ -0,0 +1,375 @@
+package tcpserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyberinferno/go-utils/idgenerator"
+	"github.com/cyberinferno/go-utils/logger"
+	"github.com/cyberinferno/go-utils/safemap"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// echoSession is a minimal TCPServerSession that echoes everything it reads.
+type echoSession struct {
+	id     uint32
+	conn   net.Conn
+	server *TCPServer
+	once   sync.Once
+}
+
+func (e *echoSession) ID() uint32 { return e.id }
+
+func (e *echoSession) Handle() {
+	defer e.server.RemoveSession(e.id)
+	_, _ = io.Copy(e.conn, e.conn)
+}
+
+func (e *echoSession) Close() error {
+	var err error
+	e.once.Do(func() { err = e.conn.Close() })
+	return err
+}
+
+func (e *echoSession) Send(data []byte) error {
+	_, err := e.conn.Write(data)
+	return err
+}
+
+// newTestServer builds a TCPServer on a loopback port that logs into the returned buffer.
+func newTestServer(newSession func(s *TCPServer) NewSessionFunc) (*TCPServer, *syncBuffer) {
+	logs := &syncBuffer{}
+	s := &TCPServer{
+		Logger:      logger.NewZerologLogger(zerolog.New(logs), "test", zerolog.DebugLevel),
+		Name:        "test",
+		Addr:        "127.0.0.1:0",
+		Sessions:    safemap.NewSafeMap[uint32, TCPServerSession](),
+		IdGenerator: idgenerator.NewIdGenerator(0),
+	}
+	s.NewSession = newSession(s)
+	return s, logs
+}
+
+func TestTCPServer_AcceptLoop_NilSessionRejectsConnection(t *testing.T) {
+	s, logs := newTestServer(func(s *TCPServer) NewSessionFunc {
+		return func(id uint32, conn net.Conn) TCPServerSession {
+			// Reject odd session IDs
+			if id%2 == 1 {
+				return nil
+			}
+			return &echoSession{id: id, conn: conn, server: s}
+		}
+	})
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	rejected, err := net.Dial("tcp", s.Listener.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = rejected.Close() }()
+
+	_ = rejected.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = rejected.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+
+	accepted, err := net.Dial("tcp", s.Listener.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = accepted.Close() }()
+
+	_, err = accepted.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_ = accepted.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = io.ReadFull(accepted, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	assert.False(t, s.Sessions.Has(1))
+	assert.True(t, s.Sessions.Has(2))
+	assert.Contains(t, logs.String(), "test server rejected connection")
+}
+
+func TestTCPServer_NilLogger(t *testing.T) {
+	s, _ := newTestServer(func(s *TCPServer) NewSessionFunc {
+		return func(id uint32, conn net.Conn) TCPServerSession { return nil }
+	})
+	s.Logger = nil
+
+	require.NoError(t, s.Start())
+	assert.Error(t, s.Start(), "already running")
+
+	conn, err := net.Dial("tcp", s.Listener.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	// The rejected connection is logged and closed without a logger
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+
+	assert.NotPanics(t, s.Stop)
+	assert.NotPanics(t, s.Stop)
+}
+
+func TestTCPServer_SessionsSnapshot(t *testing.T) {
+	s, _ := newTestServer(func(s *TCPServer) NewSessionFunc { return nil })
+	assert.Empty(t, s.SessionsSnapshot())
+
+	first := &echoSession{id: 1}
+	second := &echoSession{id: 2}
+	s.AddSession(1, first)
+	s.AddSession(2, second)
+
+	snapshot := s.SessionsSnapshot()
+	assert.Equal(t, map[uint32]TCPServerSession{1: first, 2: second}, snapshot)
+
+	// Later changes on either side do not affect the other
+	s.RemoveSession(1)
+	s.AddSession(3, &echoSession{id: 3})
+	assert.Len(t, snapshot, 2)
+	assert.Same(t, first, snapshot[1])
+
+	delete(snapshot, 2)
+	_, ok := s.GetSession(2)
+	assert.True(t, ok)
+}
+
+// topicSession is a TCPServerSession that records what it is sent and is
+// subscribed to a fixed set of topics.
+type topicSession struct {
+	id      uint32
+	topics  []string
+	sendErr error
+	sent    [][]byte
+}
+
+func (t *topicSession) ID() uint32   { return t.id }
+func (t *topicSession) Handle()      {}
+func (t *topicSession) Close() error { return nil }
+
+func (t *topicSession) Send(data []byte) error {
+	if t.sendErr != nil {
+		return t.sendErr
+	}
+	t.sent = append(t.sent, data)
+	return nil
+}
+
+func (t *topicSession) Subscribed(topic string) bool {
+	return slices.Contains(t.topics, topic)
+}
+
+func TestTCPServer_BroadcastFunc(t *testing.T) {
+	type subscriber interface{ Subscribed(topic string) bool }
+	subscribedTo := func(topic string) func(TCPServerSession) bool {
+		return func(session TCPServerSession) bool {
+			sub, ok := session.(subscriber)
+			return ok && sub.Subscribed(topic)
+		}
+	}
+
+	s, _ := newTestServer(func(s *TCPServer) NewSessionFunc { return nil })
+	news := &topicSession{id: 1, topics: []string{"news"}}
+	sports := &topicSession{id: 2, topics: []string{"sports"}}
+	both := &topicSession{id: 3, topics: []string{"news", "sports"}}
+	plain := &echoSession{id: 4}
+	for _, session := range []TCPServerSession{news, sports, both, plain} {
+		s.AddSession(session.ID(), session)
+	}
+
+	errs := s.BroadcastFunc([]byte("headline"), subscribedTo("news"))
+	assert.Empty(t, errs)
+	assert.Equal(t, [][]byte{[]byte("headline")}, news.sent)
+	assert.Equal(t, [][]byte{[]byte("headline")}, both.sent)
+	assert.Empty(t, sports.sent, "sessions not matching the filter receive nothing")
+
+	t.Run("collects send errors", func(t *testing.T) {
+		sendErr := errors.New("connection reset")
+		sports.sendErr = sendErr
+
+		errs := s.BroadcastFunc([]byte("score"), subscribedTo("sports"))
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], sendErr)
+		assert.ErrorContains(t, errs[0], "session 2")
+		assert.Equal(t, []byte("score"), both.sent[len(both.sent)-1], "a failed send does not stop the others")
+	})
+
+	t.Run("nil filter matches every session", func(t *testing.T) {
+		s, _ := newTestServer(func(s *TCPServer) NewSessionFunc { return nil })
+		first := &topicSession{id: 1}
+		second := &topicSession{id: 2}
+		s.AddSession(1, first)
+		s.AddSession(2, second)
+
+		assert.Empty(t, s.BroadcastFunc([]byte("all"), nil))
+		assert.Len(t, first.sent, 1)
+		assert.Len(t, second.sent, 1)
+	})
+}
+
+func TestTCPServer_Serve(t *testing.T) {
+	s, _ := newTestServer(func(s *TCPServer) NewSessionFunc {
+		return func(id uint32, conn net.Conn) TCPServerSession {
+			return &echoSession{id: id, conn: conn, server: s}
+		}
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(ln) }()
+	require.Eventually(t, s.Running.Load, 2*time.Second, 10*time.Millisecond)
+
+	assert.Error(t, s.Serve(ln), "second Serve on a running server")
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	s.Stop()
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return after Stop")
+	}
+}
+
+func TestTCPServer_StopRacingServe(t *testing.T) {
+	for range 50 {
+		s, _ := newTestServer(func(s *TCPServer) NewSessionFunc {
+			return func(id uint32, conn net.Conn) TCPServerSession { return nil }
+		})
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		served := make(chan error, 1)
+		go func() { served <- s.Serve(ln) }()
+		s.Stop()
+
+		// Stop may have run before Serve started; stop again until Serve returns
+		require.Eventually(t, func() bool {
+			s.Stop()
+			select {
+			case err := <-served:
+				return assert.NoError(t, err)
+			default:
+				return false
+			}
+		}, 2*time.Second, time.Millisecond)
+
+		_, err = ln.Accept()
+		assert.ErrorIs(t, err, net.ErrClosed, "Stop closes the listener")
+	}
+}
+
+type identityKey struct{}
+
+func TestTCPServer_Authenticate(t *testing.T) {
+	identities := make(chan string, 1)
+	s, logs := newTestServer(func(s *TCPServer) NewSessionFunc {
+		return func(id uint32, conn net.Conn) TCPServerSession {
+			t.Error("NewSession called although NewSessionContext is set")
+			return nil
+		}
+	})
+	s.Authenticate = func(conn net.Conn) (context.Context, error) {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+
+		token, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if token != "good-token\n" {
+			return nil, errors.New("invalid token")
+		}
+		return context.WithValue(context.Background(), identityKey{}, "alice"), nil
+	}
+	s.NewSessionContext = func(ctx context.Context, id uint32, conn net.Conn) TCPServerSession {
+		identities <- ctx.Value(identityKey{}).(string)
+		return &echoSession{id: id, conn: conn, server: s}
+	}
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	t.Run("bad token is rejected", func(t *testing.T) {
+		conn, err := net.Dial("tcp", s.Listener.Addr().String())
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		_, err = conn.Write([]byte("bad-token\n"))
+		require.NoError(t, err)
+
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, err = conn.Read(make([]byte, 1))
+		assert.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, 0, s.Sessions.Len())
+		assert.Contains(t, logs.String(), "test server authentication failed")
+	})
+
+	t.Run("good token creates a session with the identity", func(t *testing.T) {
+		conn, err := net.Dial("tcp", s.Listener.Addr().String())
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		_, err = conn.Write([]byte("good-token\n"))
+		require.NoError(t, err)
+
+		select {
+		case identity := <-identities:
+			assert.Equal(t, "alice", identity)
+		case <-time.After(2 * time.Second):
+			t.Fatal("session was not created")
+		}
+
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+
+		buf := make([]byte, 4)
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+		assert.Equal(t, 1, s.Sessions.Len())
+	})
+}
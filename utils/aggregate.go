@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Numeric is satisfied by every integer and floating-point type, including
+// named types derived from them.
+type Numeric interface {
+	Integer | ~float32 | ~float64
+}
+
+// Sum returns the sum of the elements of s, or 0 for an empty slice. Integer
+// sums wrap on overflow like ordinary Go addition.
+//
+// Parameters:
+//   - s: The values to add
+//
+// Returns:
+//   - The sum of s
+func Sum[T Numeric](s []T) T {
+	var total T
+	for _, v := range s {
+		total += v
+	}
+
+	return total
+}
+
+// Average returns the arithmetic mean of the elements of s, or 0 for an empty
+// slice. Values are summed as float64, so large integer slices do not overflow.
+//
+// Parameters:
+//   - s: The values to average
+//
+// Returns:
+//   - The mean of s as a float64
+func Average[T Numeric](s []T) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, v := range s {
+		total += float64(v)
+	}
+
+	return total / float64(len(s))
+}
+
+// MaxOf returns the largest element of s.
+//
+// Parameters:
+//   - s: The values to search
+//
+// Returns:
+//   - The largest element, and true; or the zero value and false if s is empty
+func MaxOf[T cmp.Ordered](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return slices.Max(s), true
+}
+
+// MinOf returns the smallest element of s.
+//
+// Parameters:
+//   - s: The values to search
+//
+// Returns:
+//   - The smallest element, and true; or the zero value and false if s is empty
+func MinOf[T cmp.Ordered](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return slices.Min(s), true
+}
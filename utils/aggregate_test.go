@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSum(t *testing.T) {
+	assert.Equal(t, 6, Sum([]int{1, 2, 3}))
+	assert.Equal(t, int64(-2), Sum([]int64{5, -10, 3}))
+	assert.InDelta(t, 0.5, Sum([]float64{1.5, -1}), 1e-9)
+	assert.Equal(t, 0, Sum([]int(nil)))
+
+	type Cents int
+	assert.Equal(t, Cents(150), Sum([]Cents{100, 50}))
+}
+
+func TestAverage(t *testing.T) {
+	assert.Equal(t, 2.0, Average([]int{1, 2, 3}))
+	assert.Equal(t, 0.5, Average([]int{-1, 2}))
+	assert.Equal(t, 0.0, Average([]float32{}))
+
+	// Summed as float64, so this does not wrap
+	assert.Equal(t, 200.0, Average([]uint8{200, 200}))
+}
+
+func TestMaxOfMinOf(t *testing.T) {
+	t.Run("mixed-sign values", func(t *testing.T) {
+		values := []int{3, -7, 12, 0, -1}
+
+		maxVal, ok := MaxOf(values)
+		assert.True(t, ok)
+		assert.Equal(t, 12, maxVal)
+
+		minVal, ok := MinOf(values)
+		assert.True(t, ok)
+		assert.Equal(t, -7, minVal)
+	})
+
+	t.Run("strings", func(t *testing.T) {
+		maxVal, _ := MaxOf([]string{"pear", "apple", "zucchini"})
+		assert.Equal(t, "zucchini", maxVal)
+	})
+
+	t.Run("empty slice", func(t *testing.T) {
+		maxVal, ok := MaxOf([]float64{})
+		assert.False(t, ok)
+		assert.Equal(t, 0.0, maxVal)
+
+		minVal, ok := MinOf([]string(nil))
+		assert.False(t, ok)
+		assert.Equal(t, "", minVal)
+	})
+}
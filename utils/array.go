@@ -1,5 +1,6 @@
 // Package utils provides common helper functions for arrays, bytes, strings,
-// JSON validation, time conversion, Discord notifications, and boolean formatting.
+// JSON validation, time conversion, Discord notifications, boolean formatting,
+// and small generic helpers and data structures.
 package utils
 
 import "math/rand"
@@ -15,3 +16,32 @@ import "math/rand"
 func GetRandomElement[T any](arr []T) T {
 	return arr[rand.Intn(len(arr))]
 }
+
+// ElementsMatch reports whether a and b contain the same elements with the same
+// multiplicity, regardless of order. Nil and empty slices are considered equal.
+//
+// Parameters:
+//   - a: The first slice
+//   - b: The second slice
+//
+// Returns:
+//   - true if every element occurs the same number of times in both slices, false otherwise
+func ElementsMatch[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[T]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+
+	return true
+}
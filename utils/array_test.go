@@ -33,3 +33,22 @@ func TestGetRandomElement(t *testing.T) {
 		require.Contains(t, arr, got)
 	})
 }
+
+func TestElementsMatch(t *testing.T) {
+	t.Run("equal multisets in different order", func(t *testing.T) {
+		assert.True(t, ElementsMatch([]int{1, 2, 2, 3}, []int{2, 3, 1, 2}))
+		assert.True(t, ElementsMatch([]string{"a", "b"}, []string{"b", "a"}))
+	})
+
+	t.Run("different multiplicities", func(t *testing.T) {
+		assert.False(t, ElementsMatch([]int{1, 1, 2}, []int{1, 2, 2}))
+	})
+
+	t.Run("different lengths", func(t *testing.T) {
+		assert.False(t, ElementsMatch([]int{1, 2}, []int{1, 2, 2}))
+	})
+
+	t.Run("nil and empty slices match", func(t *testing.T) {
+		assert.True(t, ElementsMatch[int](nil, []int{}))
+	})
+}
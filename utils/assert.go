@@ -0,0 +1,31 @@
+package utils
+
+// Assert performs a type assertion of v to T without panicking.
+//
+// Parameters:
+//   - v: The value to assert; may be nil
+//
+// Returns:
+//   - v as type T, or the zero value of T if v does not hold a T
+//   - true if v holds a T, false otherwise (including when v is nil)
+func Assert[T any](v any) (T, bool) {
+	t, ok := v.(T)
+	return t, ok
+}
+
+// AssertOr performs a type assertion of v to T, returning fallback when v
+// does not hold a T.
+//
+// Parameters:
+//   - v: The value to assert; may be nil
+//   - fallback: The value to return if the assertion fails
+//
+// Returns:
+//   - v as type T, or fallback if v does not hold a T
+func AssertOr[T any](v any, fallback T) T {
+	if t, ok := v.(T); ok {
+		return t
+	}
+
+	return fallback
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssert(t *testing.T) {
+	t.Run("matching type", func(t *testing.T) {
+		v, ok := Assert[int](42)
+		assert.True(t, ok)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("non-matching type returns zero value", func(t *testing.T) {
+		v, ok := Assert[int]("42")
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+	})
+
+	t.Run("nil interface", func(t *testing.T) {
+		v, ok := Assert[*int](nil)
+		assert.False(t, ok)
+		assert.Nil(t, v)
+	})
+
+	t.Run("interface target", func(t *testing.T) {
+		v, ok := Assert[error](errors.New("boom"))
+		assert.True(t, ok)
+		assert.EqualError(t, v, "boom")
+	})
+}
+
+func TestAssertOr(t *testing.T) {
+	t.Run("matching type", func(t *testing.T) {
+		assert.Equal(t, "value", AssertOr[string]("value", "fallback"))
+	})
+
+	t.Run("non-matching type returns fallback", func(t *testing.T) {
+		assert.Equal(t, "fallback", AssertOr(123, "fallback"))
+	})
+
+	t.Run("nil interface returns fallback", func(t *testing.T) {
+		assert.Equal(t, 7, AssertOr(nil, 7))
+	})
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"sync/atomic"
+)
+
+// BloomFilter is a probabilistic set of byte strings. Contains never reports
+// false for an added item, but may report true for an item that was never
+// added, at roughly the false-positive rate the filter was sized for. Items
+// cannot be removed.
+//
+// Add and Contains are lock-free and safe for concurrent use by any number of
+// goroutines. A Contains that runs concurrently with the Add of the same item
+// may report either result; once Add has returned, Contains reports true.
+type BloomFilter struct {
+	bits  []atomic.Uint64
+	m     uint64
+	k     uint64
+	seed1 maphash.Seed
+	seed2 maphash.Seed
+}
+
+// NewBloomFilter creates a BloomFilter sized to hold expectedItems items with
+// the given false-positive rate. Adding more items than expected raises the
+// false-positive rate.
+//
+// Parameters:
+//   - expectedItems: The number of items the filter is sized for; values below 1 are treated as 1
+//   - falsePositiveRate: The target false-positive rate in the range (0, 1); other values panic
+//
+// Returns:
+//   - A pointer to a new, empty BloomFilter
+func NewBloomFilter(expectedItems int, falsePositiveRate float64) *BloomFilter {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic(fmt.Sprintf("utils: bloom filter false-positive rate must be in (0, 1), got %v", falsePositiveRate))
+	}
+
+	n := float64(max(expectedItems, 1))
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := max(math.Round(m/n*math.Ln2), 1)
+
+	words := (uint64(m) + 63) / 64
+	return &BloomFilter{
+		bits:  make([]atomic.Uint64, words),
+		m:     words * 64,
+		k:     uint64(k),
+		seed1: maphash.MakeSeed(),
+		seed2: maphash.MakeSeed(),
+	}
+}
+
+// Add inserts item into the filter.
+//
+// Parameters:
+//   - item: The item to add
+func (b *BloomFilter) Add(item []byte) {
+	h1, h2 := b.hashes(item)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		b.bits[bit/64].Or(1 << (bit % 64))
+	}
+}
+
+// Contains reports whether item may have been added to the filter.
+//
+// Parameters:
+//   - item: The item to look up
+//
+// Returns:
+//   - false if item was definitely never added; true if it probably was
+func (b *BloomFilter) Contains(item []byte) bool {
+	h1, h2 := b.hashes(item)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		if b.bits[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hashes returns the two base hashes combined into the k bit positions
+// (double hashing). h2 is forced odd so successive positions differ.
+func (b *BloomFilter) hashes(item []byte) (uint64, uint64) {
+	return maphash.Bytes(b.seed1, item), maphash.Bytes(b.seed2, item) | 1
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	const n = 10000
+	const rate = 0.01
+
+	t.Run("no false negatives", func(t *testing.T) {
+		b := NewBloomFilter(n, rate)
+		for i := 0; i < n; i++ {
+			b.Add([]byte(fmt.Sprintf("id-%d", i)))
+		}
+		for i := 0; i < n; i++ {
+			assert.True(t, b.Contains([]byte(fmt.Sprintf("id-%d", i))), "id-%d missing", i)
+		}
+	})
+
+	t.Run("false-positive rate is close to the target", func(t *testing.T) {
+		b := NewBloomFilter(n, rate)
+		for i := 0; i < n; i++ {
+			b.Add([]byte(fmt.Sprintf("id-%d", i)))
+		}
+
+		falsePositives := 0
+		const probes = 100000
+		for i := 0; i < probes; i++ {
+			if b.Contains([]byte(fmt.Sprintf("other-%d", i))) {
+				falsePositives++
+			}
+		}
+
+		observed := float64(falsePositives) / probes
+		assert.Less(t, observed, 2*rate, "observed false-positive rate %v", observed)
+	})
+
+	t.Run("empty filter contains nothing", func(t *testing.T) {
+		b := NewBloomFilter(0, rate)
+		assert.False(t, b.Contains([]byte("x")))
+		assert.False(t, b.Contains(nil))
+	})
+
+	t.Run("concurrent adds and lookups", func(t *testing.T) {
+		b := NewBloomFilter(n, rate)
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := w; i < n; i += 8 {
+					item := []byte(fmt.Sprintf("id-%d", i))
+					b.Add(item)
+					assert.True(t, b.Contains(item))
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("invalid rate panics", func(t *testing.T) {
+		assert.Panics(t, func() { NewBloomFilter(n, 0) })
+		assert.Panics(t, func() { NewBloomFilter(n, 1) })
+	})
+}
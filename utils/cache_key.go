@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CacheKeySeparator is the separator placed between parts by CacheKey.
+const CacheKeySeparator = ":"
+
+// cacheKeyEscaper escapes the separator and the escape character inside parts, so that
+// different part lists never produce the same key.
+var cacheKeyEscaper = strings.NewReplacer(`\`, `\\`, CacheKeySeparator, `\`+CacheKeySeparator)
+
+// CacheKey builds a cache key by stringifying each part and joining them with
+// CacheKeySeparator. Strings, integers, floats, booleans, byte slices, and fmt.Stringer
+// values are formatted deterministically; other values use fmt's %v formatting and nil
+// becomes an empty part. Occurrences of the separator (and backslash) inside a part are
+// escaped with a backslash, so that ("a:b", "c") and ("a", "b:c") yield different keys.
+//
+// Parameters:
+//   - parts: The key components, in order (e.g. "user", 42)
+//
+// Returns:
+//   - The joined key, e.g. "user:42"
+func CacheKey(parts ...any) string {
+	var sb strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			sb.WriteString(CacheKeySeparator)
+		}
+		sb.WriteString(cacheKeyEscaper.Replace(cacheKeyPart(part)))
+	}
+
+	return sb.String()
+}
+
+// CacheKeyHashed builds the same key as CacheKey and returns a short, fixed-length
+// hash of it: the first 16 bytes of its SHA-256 digest, hex-encoded. Use it for keys that
+// would otherwise be very long (e.g. built from query parameters).
+//
+// Parameters:
+//   - parts: The key components, in order
+//
+// Returns:
+//   - A 32-character lowercase hex string
+func CacheKeyHashed(parts ...any) string {
+	sum := sha256.Sum256([]byte(CacheKey(parts...)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// cacheKeyPart formats a single key part.
+func cacheKeyPart(part any) string {
+	switch v := part.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
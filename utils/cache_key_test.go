@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheKey(t *testing.T) {
+	t.Run("joins parts of different types", func(t *testing.T) {
+		assert.Equal(t, "user:42:true:1.5:7", CacheKey("user", 42, true, 1.5, uint8(7)))
+	})
+
+	t.Run("uses fmt.Stringer", func(t *testing.T) {
+		assert.Equal(t, "ttl:1m0s", CacheKey("ttl", time.Minute))
+	})
+
+	t.Run("stable for the same inputs", func(t *testing.T) {
+		assert.Equal(t, CacheKey("order", int64(9), "items"), CacheKey("order", int64(9), "items"))
+	})
+
+	t.Run("distinct for different inputs", func(t *testing.T) {
+		assert.NotEqual(t, CacheKey("user", 1), CacheKey("user", 2))
+		assert.NotEqual(t, CacheKey("a:b", "c"), CacheKey("a", "b:c"))
+		assert.NotEqual(t, CacheKey(`a\`, "b"), CacheKey(`a\:b`))
+	})
+
+	t.Run("no parts yields empty key", func(t *testing.T) {
+		assert.Equal(t, "", CacheKey())
+	})
+}
+
+func TestCacheKeyHashed(t *testing.T) {
+	t.Run("stable and fixed length", func(t *testing.T) {
+		key := CacheKeyHashed("search", "a very long query string", 1, 100)
+		assert.Len(t, key, 32)
+		assert.Equal(t, key, CacheKeyHashed("search", "a very long query string", 1, 100))
+	})
+
+	t.Run("distinct for different inputs", func(t *testing.T) {
+		assert.NotEqual(t, CacheKeyHashed("search", 1), CacheKeyHashed("search", 2))
+		assert.NotEqual(t, CacheKeyHashed("a:b", "c"), CacheKeyHashed("a", "b:c"))
+	})
+}
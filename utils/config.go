@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadConfig returns a T populated from environment variables. Each exported
+// field tagged `env:"NAME"` is read from the variable prefix+NAME; when that
+// variable is not set, the field's `default:"..."` tag is used instead, and a
+// field tagged `required:"true"` without either is an error. Untagged fields
+// are left at their zero value. Supported field types are strings, bools,
+// integers, floats, time.Duration (as accepted by time.ParseDuration), and
+// slices of those, written as comma-separated lists.
+//
+// Parameters:
+//   - prefix: Prepended verbatim to every variable name (e.g. "APP_"); may be empty
+//
+// Returns:
+//   - The populated config
+//   - An error naming the variable if T is not a struct, a required variable is
+//     missing, a value cannot be converted, or a field type is unsupported
+func LoadConfig[T any](prefix string) (T, error) {
+	var cfg T
+
+	value := reflect.ValueOf(&cfg).Elem()
+	if value.Kind() != reflect.Struct {
+		return cfg, fmt.Errorf("expected a struct, got %T", cfg)
+	}
+
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, ok := field.Tag.Lookup("env")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		name := prefix + tag
+		raw, set := os.LookupEnv(name)
+		if !set {
+			raw, set = field.Tag.Lookup("default")
+		}
+		if !set {
+			if field.Tag.Get("required") == "true" {
+				return cfg, fmt.Errorf("required environment variable %s is not set", name)
+			}
+			continue
+		}
+
+		if err := setFromString(value.Field(i), raw); err != nil {
+			return cfg, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// setFromString parses raw into v according to v's type.
+func setFromString(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(raw), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if strings.TrimSpace(raw) == "" {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+			return nil
+		}
+
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setFromString(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
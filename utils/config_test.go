@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Host     string        `env:"HOST" default:"localhost"`
+	Port     int           `env:"PORT" default:"8080"`
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+	Ratio    float64       `env:"RATIO" default:"0.5"`
+	Peers    []string      `env:"PEERS"`
+	Retries  []int         `env:"RETRIES" default:"1,2,3"`
+	APIKey   string        `env:"API_KEY" required:"true"`
+	Internal string
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("APP_API_KEY", "secret")
+
+		cfg, err := LoadConfig[testConfig]("APP_")
+		require.NoError(t, err)
+		assert.Equal(t, testConfig{
+			Host:    "localhost",
+			Port:    8080,
+			Timeout: 5 * time.Second,
+			Ratio:   0.5,
+			Retries: []int{1, 2, 3},
+			APIKey:  "secret",
+		}, cfg)
+	})
+
+	t.Run("environment overrides defaults", func(t *testing.T) {
+		t.Setenv("APP_API_KEY", "secret")
+		t.Setenv("APP_HOST", "db.internal")
+		t.Setenv("APP_PORT", "5432")
+		t.Setenv("APP_DEBUG", "true")
+		t.Setenv("APP_TIMEOUT", "1m30s")
+		t.Setenv("APP_PEERS", "a:1, b:2 ,c:3")
+		t.Setenv("APP_RETRIES", "")
+		t.Setenv("INTERNAL", "ignored")
+
+		cfg, err := LoadConfig[testConfig]("APP_")
+		require.NoError(t, err)
+		assert.Equal(t, "db.internal", cfg.Host)
+		assert.Equal(t, 5432, cfg.Port)
+		assert.True(t, cfg.Debug)
+		assert.Equal(t, 90*time.Second, cfg.Timeout)
+		assert.Equal(t, []string{"a:1", "b:2", "c:3"}, cfg.Peers)
+		assert.Empty(t, cfg.Retries, "a set but empty variable overrides the default")
+		assert.Empty(t, cfg.Internal, "untagged fields are not loaded")
+	})
+
+	t.Run("missing required variable", func(t *testing.T) {
+		_, err := LoadConfig[testConfig]("APP_")
+		assert.EqualError(t, err, "required environment variable APP_API_KEY is not set")
+	})
+
+	t.Run("conversion errors name the variable", func(t *testing.T) {
+		t.Setenv("APP_API_KEY", "secret")
+		t.Setenv("APP_PORT", "eighty")
+
+		_, err := LoadConfig[testConfig]("APP_")
+		assert.ErrorContains(t, err, "APP_PORT")
+	})
+
+	t.Run("integer overflow", func(t *testing.T) {
+		type small struct {
+			Level int8 `env:"LEVEL"`
+		}
+		t.Setenv("LEVEL", "300")
+
+		_, err := LoadConfig[small]("")
+		assert.ErrorContains(t, err, "LEVEL")
+	})
+
+	t.Run("unsupported field type", func(t *testing.T) {
+		type withMap struct {
+			Labels map[string]string `env:"LABELS" default:"a=b"`
+		}
+
+		_, err := LoadConfig[withMap]("")
+		assert.ErrorContains(t, err, "unsupported field type")
+	})
+
+	t.Run("non-struct type", func(t *testing.T) {
+		_, err := LoadConfig[int]("")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,41 @@
+package utils
+
+import "fmt"
+
+// Integer is satisfied by every signed and unsigned integer type, including
+// named types derived from them.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// SafeConvert converts v to the integer type To, returning an error instead
+// of silently truncating or wrapping when v does not fit in To's range.
+//
+// Parameters:
+//   - v: The value to convert
+//
+// Returns:
+//   - v as type To
+//   - An error if v is out of range for To; the returned value is then zero
+func SafeConvert[To, From Integer](v From) (To, error) {
+	converted := To(v)
+	if From(converted) != v || (v < 0) != (converted < 0) {
+		return 0, fmt.Errorf("value %d overflows %T", v, converted)
+	}
+
+	return converted, nil
+}
+
+// Convert converts v to the integer type To without range checking. Values
+// that do not fit are truncated or wrapped as with a plain Go conversion; use
+// SafeConvert when v may be out of range.
+//
+// Parameters:
+//   - v: The value to convert
+//
+// Returns:
+//   - v as type To
+func Convert[To, From Integer](v From) To {
+	return To(v)
+}
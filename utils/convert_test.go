@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeConvert(t *testing.T) {
+	t.Run("in range conversions", func(t *testing.T) {
+		v32, err := SafeConvert[int32](int64(math.MaxInt32))
+		require.NoError(t, err)
+		assert.Equal(t, int32(math.MaxInt32), v32)
+
+		v8, err := SafeConvert[int8](int64(-128))
+		require.NoError(t, err)
+		assert.Equal(t, int8(-128), v8)
+
+		u16, err := SafeConvert[uint16](int(65535))
+		require.NoError(t, err)
+		assert.Equal(t, uint16(65535), u16)
+
+		i64, err := SafeConvert[int64](uint32(math.MaxUint32))
+		require.NoError(t, err)
+		assert.Equal(t, int64(math.MaxUint32), i64)
+	})
+
+	t.Run("signed overflow", func(t *testing.T) {
+		_, err := SafeConvert[int32](int64(math.MaxInt32) + 1)
+		assert.Error(t, err)
+
+		_, err = SafeConvert[int8](int64(-129))
+		assert.Error(t, err)
+	})
+
+	t.Run("unsigned overflow", func(t *testing.T) {
+		_, err := SafeConvert[uint8](uint32(256))
+		assert.Error(t, err)
+
+		_, err = SafeConvert[uint32](int64(math.MaxUint32) + 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("sign changes are rejected", func(t *testing.T) {
+		_, err := SafeConvert[uint64](int64(-1))
+		assert.Error(t, err)
+
+		_, err = SafeConvert[int64](uint64(math.MaxUint64))
+		assert.Error(t, err)
+
+		_, err = SafeConvert[int32](uint32(math.MaxUint32))
+		assert.Error(t, err)
+	})
+
+	t.Run("named types", func(t *testing.T) {
+		type port uint16
+		p, err := SafeConvert[port](8080)
+		require.NoError(t, err)
+		assert.Equal(t, port(8080), p)
+	})
+}
+
+func TestConvert(t *testing.T) {
+	assert.Equal(t, int32(42), Convert[int32](int64(42)))
+	assert.Equal(t, uint8(0), Convert[uint8](256))
+	assert.Equal(t, int8(-1), Convert[int8](uint8(255)))
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"slices"
+	"sync"
+)
+
+// CountedItem is an item and the number of times it was added to a Counter.
+type CountedItem[T comparable] struct {
+	Item  T
+	Count int
+}
+
+// Counter counts occurrences of items, like Python's collections.Counter. A
+// Counter is safe for concurrent use.
+type Counter[T comparable] struct {
+	mu     sync.Mutex
+	counts map[T]int
+	order  map[T]int // position of each item's first Add, for breaking ties
+	total  int
+}
+
+// NewCounter creates an empty Counter.
+//
+// Returns:
+//   - A pointer to a new, empty Counter
+func NewCounter[T comparable]() *Counter[T] {
+	return &Counter[T]{
+		counts: make(map[T]int),
+		order:  make(map[T]int),
+	}
+}
+
+// Add records one occurrence of item.
+//
+// Parameters:
+//   - item: The item to count
+func (c *Counter[T]) Add(item T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.order[item]; !ok {
+		c.order[item] = len(c.order)
+	}
+	c.counts[item]++
+	c.total++
+}
+
+// Count returns the number of times item has been added.
+//
+// Parameters:
+//   - item: The item to look up
+//
+// Returns:
+//   - The item's count, or 0 if it was never added
+func (c *Counter[T]) Count(item T) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[item]
+}
+
+// Most returns the n most frequent items, most frequent first. Items with the
+// same count are ordered by when they were first added.
+//
+// Parameters:
+//   - n: Maximum number of items to return; if it exceeds the number of
+//     distinct items, all of them are returned
+//
+// Returns:
+//   - Up to n items with their counts; empty if n is 0 or less
+func (c *Counter[T]) Most(n int) []CountedItem[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n <= 0 {
+		return []CountedItem[T]{}
+	}
+
+	items := make([]CountedItem[T], 0, len(c.counts))
+	for item, count := range c.counts {
+		items = append(items, CountedItem[T]{Item: item, Count: count})
+	}
+
+	slices.SortFunc(items, func(a, b CountedItem[T]) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return c.order[a.Item] - c.order[b.Item]
+	})
+
+	return items[:min(n, len(items))]
+}
+
+// Total returns the number of Add calls, summed over all items.
+//
+// Returns:
+//   - The total count
+func (c *Counter[T]) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
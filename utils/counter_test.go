@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	t.Run("counts occurrences", func(t *testing.T) {
+		c := NewCounter[string]()
+		for _, word := range strings.Fields("the cat and the hat and the bat") {
+			c.Add(word)
+		}
+
+		assert.Equal(t, 3, c.Count("the"))
+		assert.Equal(t, 2, c.Count("and"))
+		assert.Equal(t, 1, c.Count("cat"))
+		assert.Equal(t, 0, c.Count("dog"))
+		assert.Equal(t, 8, c.Total())
+	})
+
+	t.Run("most frequent first, ties in first-seen order", func(t *testing.T) {
+		c := NewCounter[string]()
+		for _, item := range []string{"b", "a", "c", "a", "b", "d", "c", "a"} {
+			c.Add(item)
+		}
+
+		// a:3, then b and c with 2 each (b seen first), then d:1
+		assert.Equal(t, []CountedItem[string]{
+			{Item: "a", Count: 3},
+			{Item: "b", Count: 2},
+			{Item: "c", Count: 2},
+		}, c.Most(3))
+		assert.Len(t, c.Most(10), 4, "n beyond the distinct items returns all")
+		assert.Equal(t, CountedItem[string]{Item: "d", Count: 1}, c.Most(4)[3])
+		assert.Empty(t, c.Most(0))
+	})
+
+	t.Run("empty counter", func(t *testing.T) {
+		c := NewCounter[int]()
+		assert.Empty(t, c.Most(5))
+		assert.Equal(t, 0, c.Total())
+	})
+
+	t.Run("concurrent adds", func(t *testing.T) {
+		c := NewCounter[int]()
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					c.Add(i % 2)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1000, c.Total())
+		assert.Equal(t, 500, c.Count(0))
+		assert.Equal(t, 500, c.Count(1))
+	})
+}
@@ -0,0 +1,80 @@
+package utils
+
+import "time"
+
+// Dedup returns a channel that forwards the items received from in, skipping
+// any item equal to the item immediately before it. The returned channel is
+// closed after in is closed. The caller must keep receiving from the returned
+// channel until it is closed, or the forwarding goroutine blocks forever.
+//
+// Parameters:
+//   - in: The source channel
+//
+// Returns:
+//   - A channel of the items from in without consecutive duplicates
+func Dedup[T comparable](in <-chan T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		var prev T
+		first := true
+		for v := range in {
+			if !first && v == prev {
+				continue
+			}
+			first = false
+			prev = v
+			out <- v
+		}
+	}()
+
+	return out
+}
+
+// DedupWindow returns a channel that forwards the items received from in,
+// skipping any item equal to one forwarded less than window ago, even if other
+// items arrived in between. Once window has passed the item is forwarded again,
+// so a value repeated continuously is forwarded about once per window. The
+// returned channel is closed after in is closed. The caller must keep receiving
+// from the returned channel until it is closed, or the forwarding goroutine
+// blocks forever.
+//
+// Parameters:
+//   - in: The source channel
+//   - window: How long a forwarded item suppresses its duplicates
+//
+// Returns:
+//   - A channel of the items from in without duplicates inside the window
+func DedupWindow[T comparable](in <-chan T, window time.Duration) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		seen := make(map[T]time.Time)
+		lastSweep := time.Now()
+		for v := range in {
+			now := time.Now()
+
+			// Forget expired items once per window so the map stays bounded
+			if now.Sub(lastSweep) >= window {
+				for k, t := range seen {
+					if now.Sub(t) >= window {
+						delete(seen, k)
+					}
+				}
+				lastSweep = now
+			}
+
+			if t, ok := seen[v]; ok && now.Sub(t) < window {
+				continue
+			}
+			seen[v] = now
+			out <- v
+		}
+	}()
+
+	return out
+}
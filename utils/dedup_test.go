@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// feed sends items on a new channel and closes it.
+func feed[T any](items ...T) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for _, v := range items {
+			ch <- v
+		}
+	}()
+	return ch
+}
+
+// collect receives from ch until it is closed.
+func collect[T any](ch <-chan T) []T {
+	var out []T
+	for v := range ch {
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestDedup(t *testing.T) {
+	t.Run("drops consecutive duplicates", func(t *testing.T) {
+		got := collect(Dedup(feed(1, 1, 2, 2, 2, 3, 1, 1)))
+		assert.Equal(t, []int{1, 2, 3, 1}, got)
+	})
+
+	t.Run("zero value as first item is forwarded", func(t *testing.T) {
+		got := collect(Dedup(feed("", "", "a")))
+		assert.Equal(t, []string{"", "a"}, got)
+	})
+
+	t.Run("closed input closes output", func(t *testing.T) {
+		assert.Empty(t, collect(Dedup(feed[int]())))
+	})
+}
+
+func TestDedupWindow(t *testing.T) {
+	t.Run("drops duplicates within the window", func(t *testing.T) {
+		got := collect(DedupWindow(feed("a", "b", "a", "c", "b", "a"), time.Minute))
+		assert.Equal(t, []string{"a", "b", "c"}, got)
+	})
+
+	t.Run("forwards again after the window", func(t *testing.T) {
+		in := make(chan int)
+		out := DedupWindow(in, 20*time.Millisecond)
+
+		go func() {
+			defer close(in)
+			in <- 1
+			in <- 1
+			time.Sleep(40 * time.Millisecond)
+			in <- 1
+			in <- 2
+		}()
+
+		assert.Equal(t, []int{1, 1, 2}, collect(out))
+	})
+}
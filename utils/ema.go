@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EMA tracks an exponential moving average of float64 samples. Each sample v
+// updates the average as alpha*v + (1-alpha)*average; the first sample becomes
+// the initial average. An EMA is safe for concurrent use.
+type EMA struct {
+	mu          sync.Mutex
+	alpha       float64
+	value       float64
+	initialized bool
+}
+
+// NewEMA creates an EMA with smoothing factor alpha. Larger alpha weights recent
+// samples more heavily; alpha of 1 simply tracks the latest sample.
+//
+// Parameters:
+//   - alpha: Smoothing factor in the range (0, 1]; other values panic
+//
+// Returns:
+//   - A pointer to a new EMA with no samples
+func NewEMA(alpha float64) *EMA {
+	if alpha <= 0 || alpha > 1 {
+		panic(fmt.Sprintf("utils: EMA alpha must be in (0, 1], got %v", alpha))
+	}
+
+	return &EMA{alpha: alpha}
+}
+
+// Add folds a sample into the average.
+//
+// Parameters:
+//   - v: The sample to add
+func (e *EMA) Add(v float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.initialized {
+		e.value = v
+		e.initialized = true
+		return
+	}
+
+	e.value = e.alpha*v + (1-e.alpha)*e.value
+}
+
+// Value returns the current average.
+//
+// Returns:
+//   - The moving average, or 0 if no sample has been added
+func (e *EMA) Value() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.value
+}
+
+// EMADuration is an EMA over time.Duration samples, such as latencies.
+// It is safe for concurrent use.
+type EMADuration struct {
+	ema *EMA
+}
+
+// NewEMADuration creates an EMADuration with smoothing factor alpha.
+//
+// Parameters:
+//   - alpha: Smoothing factor in the range (0, 1]; other values panic
+//
+// Returns:
+//   - A pointer to a new EMADuration with no samples
+func NewEMADuration(alpha float64) *EMADuration {
+	return &EMADuration{ema: NewEMA(alpha)}
+}
+
+// Add folds a duration sample into the average.
+//
+// Parameters:
+//   - d: The sample to add
+func (e *EMADuration) Add(d time.Duration) {
+	e.ema.Add(float64(d))
+}
+
+// Value returns the current average duration.
+//
+// Returns:
+//   - The moving average, or 0 if no sample has been added
+func (e *EMADuration) Value() time.Duration {
+	return time.Duration(e.ema.Value())
+}
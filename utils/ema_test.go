@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEMA(t *testing.T) {
+	t.Run("first sample initializes the average", func(t *testing.T) {
+		e := NewEMA(0.5)
+		assert.Equal(t, 0.0, e.Value())
+		e.Add(10)
+		assert.Equal(t, 10.0, e.Value())
+	})
+
+	t.Run("known sequence", func(t *testing.T) {
+		e := NewEMA(0.5)
+		for _, v := range []float64{10, 20, 30} {
+			e.Add(v)
+		}
+		// 10 -> 15 -> 22.5
+		assert.InDelta(t, 22.5, e.Value(), 1e-9)
+	})
+
+	t.Run("converges toward steady state", func(t *testing.T) {
+		e := NewEMA(0.2)
+		e.Add(0)
+		prevDistance := 100.0
+		for i := 0; i < 50; i++ {
+			e.Add(100)
+			distance := 100 - e.Value()
+			assert.Less(t, distance, prevDistance)
+			prevDistance = distance
+		}
+		assert.InDelta(t, 100, e.Value(), 0.01)
+	})
+
+	t.Run("alpha of one tracks the latest sample", func(t *testing.T) {
+		e := NewEMA(1)
+		e.Add(5)
+		e.Add(7)
+		assert.Equal(t, 7.0, e.Value())
+	})
+
+	t.Run("invalid alpha panics", func(t *testing.T) {
+		assert.Panics(t, func() { NewEMA(0) })
+		assert.Panics(t, func() { NewEMA(1.5) })
+	})
+}
+
+func TestEMADuration(t *testing.T) {
+	e := NewEMADuration(0.5)
+	e.Add(100 * time.Millisecond)
+	e.Add(200 * time.Millisecond)
+	assert.Equal(t, 150*time.Millisecond, e.Value())
+
+	for i := 0; i < 60; i++ {
+		e.Add(50 * time.Millisecond)
+	}
+	assert.InDelta(t, float64(50*time.Millisecond), float64(e.Value()), float64(time.Microsecond))
+}
@@ -0,0 +1,69 @@
+package utils
+
+// BFS performs a breadth-first traversal starting at start. Nodes reachable
+// through neighbors are visited level by level, in the order neighbors returns
+// them. Each node is visited at most once, so cycles are handled safely.
+// Traversal stops as soon as visit returns false.
+//
+// Parameters:
+//   - start: The node to start the traversal from
+//   - neighbors: Function returning the nodes adjacent to a node
+//   - visit: Function called for each node; return false to stop the traversal
+func BFS[T comparable](start T, neighbors func(T) []T, visit func(T) bool) {
+	visited := map[T]struct{}{start: {}}
+	queue := []T{start}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		if !visit(node) {
+			return
+		}
+
+		for _, next := range neighbors(node) {
+			if _, seen := visited[next]; seen {
+				continue
+			}
+
+			visited[next] = struct{}{}
+			queue = append(queue, next)
+		}
+	}
+}
+
+// DFS performs a depth-first (pre-order) traversal starting at start. Each
+// node's neighbors are explored in the order neighbors returns them, going as
+// deep as possible before backtracking. Each node is visited at most once, so
+// cycles are handled safely. Traversal stops as soon as visit returns false.
+//
+// Parameters:
+//   - start: The node to start the traversal from
+//   - neighbors: Function returning the nodes adjacent to a node
+//   - visit: Function called for each node; return false to stop the traversal
+func DFS[T comparable](start T, neighbors func(T) []T, visit func(T) bool) {
+	visited := make(map[T]struct{})
+	stack := []T{start}
+
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, seen := visited[node]; seen {
+			continue
+		}
+
+		visited[node] = struct{}{}
+		if !visit(node) {
+			return
+		}
+
+		// Push in reverse so the first neighbor is explored first
+		next := neighbors(node)
+		for i := len(next) - 1; i >= 0; i-- {
+			if _, seen := visited[next[i]]; !seen {
+				stack = append(stack, next[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testGraph is a small directed graph with a cycle (d -> a):
+//
+//	a -> b, c
+//	b -> d
+//	c -> d, e
+//	d -> a
+//	e
+var testGraph = map[string][]string{
+	"a": {"b", "c"},
+	"b": {"d"},
+	"c": {"d", "e"},
+	"d": {"a"},
+	"e": {},
+}
+
+func testNeighbors(node string) []string {
+	return testGraph[node]
+}
+
+func TestBFS(t *testing.T) {
+	t.Run("visits each node once in breadth-first order", func(t *testing.T) {
+		var order []string
+		BFS("a", testNeighbors, func(node string) bool {
+			order = append(order, node)
+			return true
+		})
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, order)
+	})
+
+	t.Run("stops when visit returns false", func(t *testing.T) {
+		var order []string
+		BFS("a", testNeighbors, func(node string) bool {
+			order = append(order, node)
+			return node != "b"
+		})
+		assert.Equal(t, []string{"a", "b"}, order)
+	})
+
+	t.Run("single node without neighbors", func(t *testing.T) {
+		var order []string
+		BFS("e", testNeighbors, func(node string) bool {
+			order = append(order, node)
+			return true
+		})
+		assert.Equal(t, []string{"e"}, order)
+	})
+}
+
+func TestDFS(t *testing.T) {
+	t.Run("visits each node once in depth-first order", func(t *testing.T) {
+		var order []string
+		DFS("a", testNeighbors, func(node string) bool {
+			order = append(order, node)
+			return true
+		})
+		assert.Equal(t, []string{"a", "b", "d", "c", "e"}, order)
+	})
+
+	t.Run("stops when visit returns false", func(t *testing.T) {
+		var order []string
+		DFS("a", testNeighbors, func(node string) bool {
+			order = append(order, node)
+			return node != "d"
+		})
+		assert.Equal(t, []string{"a", "b", "d"}, order)
+	})
+
+	t.Run("works with integer nodes", func(t *testing.T) {
+		// 1 -> 2 -> 3 -> 1 (cycle)
+		next := func(n int) []int { return []int{n%3 + 1} }
+		visits := make(map[int]int)
+		DFS(1, next, func(n int) bool {
+			visits[n]++
+			return true
+		})
+		assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1}, visits)
+	})
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// IdempotencyStore remembers keys for a fixed window so that retried requests
+// carrying the same idempotency key can be detected and skipped. Expired keys
+// are removed automatically in the background. It is safe for concurrent use.
+type IdempotencyStore struct {
+	seen *cache.Cache
+}
+
+// NewIdempotencyStore creates an IdempotencyStore that remembers each key for
+// ttl after it is first seen. Expired keys are cleaned up every ttl.
+//
+// Parameters:
+//   - ttl: How long a key is remembered; must be positive
+//
+// Returns:
+//   - A pointer to a new IdempotencyStore
+func NewIdempotencyStore(ttl time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		seen: cache.New(ttl, ttl),
+	}
+}
+
+// CheckAndSet records key and reports whether it is new. The check and the
+// insert are atomic, so when several goroutines present the same key at once
+// exactly one of them gets true.
+//
+// Parameters:
+//   - key: The idempotency key to check
+//
+// Returns:
+//   - true the first time key is seen within the TTL, false otherwise
+func (s *IdempotencyStore) CheckAndSet(key string) bool {
+	return s.seen.Add(key, struct{}{}, cache.DefaultExpiration) == nil
+}
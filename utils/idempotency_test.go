@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	t.Run("first call is new and repeats are not", func(t *testing.T) {
+		store := NewIdempotencyStore(time.Minute)
+		assert.True(t, store.CheckAndSet("req-1"))
+		assert.False(t, store.CheckAndSet("req-1"))
+		assert.False(t, store.CheckAndSet("req-1"))
+		assert.True(t, store.CheckAndSet("req-2"))
+	})
+
+	t.Run("key is new again after the ttl", func(t *testing.T) {
+		store := NewIdempotencyStore(50 * time.Millisecond)
+		assert.True(t, store.CheckAndSet("req"))
+		time.Sleep(100 * time.Millisecond)
+		assert.True(t, store.CheckAndSet("req"))
+	})
+
+	t.Run("only one concurrent caller sees a new key", func(t *testing.T) {
+		store := NewIdempotencyStore(time.Minute)
+		var wg sync.WaitGroup
+		var fresh atomic.Int32
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if store.CheckAndSet("same") {
+					fresh.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), fresh.Load())
+	})
+}
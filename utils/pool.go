@@ -0,0 +1,47 @@
+package utils
+
+import "sync"
+
+// Pool is a type-safe wrapper around sync.Pool. It removes the need for type
+// assertions at call sites and guarantees that Get always returns a value of T.
+// A Pool is safe for concurrent use and must not be copied after first use.
+type Pool[T any] struct {
+	pool sync.Pool
+}
+
+// NewPool creates a Pool that calls newFn to create a value whenever Get is
+// called and the pool has no cached values.
+//
+// Parameters:
+//   - newFn: Function used to create a new value when the pool is empty
+//
+// Returns:
+//   - A pointer to a new Pool[T]
+func NewPool[T any](newFn func() T) *Pool[T] {
+	return &Pool[T]{
+		pool: sync.Pool{
+			New: func() any {
+				return newFn()
+			},
+		},
+	}
+}
+
+// Get returns a value from the pool, creating one with the pool's new function
+// if none is available. Callers should not assume anything about the state of
+// a reused value and should reset it as needed.
+//
+// Returns:
+//   - A cached or newly created value of type T
+func (p *Pool[T]) Get() T {
+	return p.pool.Get().(T)
+}
+
+// Put returns a value to the pool for later reuse. The value may be discarded
+// at any time by the garbage collector, as with sync.Pool.
+//
+// Parameters:
+//   - value: The value to return to the pool
+func (p *Pool[T]) Put(value T) {
+	p.pool.Put(value)
+}
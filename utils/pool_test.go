@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"bytes"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	// Disable GC so pooled values are not collected between Put and Get
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	t.Run("calls new when pool is empty", func(t *testing.T) {
+		calls := 0
+		pool := NewPool(func() *bytes.Buffer {
+			calls++
+			return new(bytes.Buffer)
+		})
+
+		buf := pool.Get()
+		assert.NotNil(t, buf)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("reuses values returned with put", func(t *testing.T) {
+		calls := 0
+		pool := NewPool(func() *bytes.Buffer {
+			calls++
+			return new(bytes.Buffer)
+		})
+
+		// sync.Pool may drop values (e.g. under the race detector), so cycle
+		// several times and expect far fewer allocations than Gets
+		for i := 0; i < 20; i++ {
+			buf := pool.Get()
+			buf.Reset()
+			pool.Put(buf)
+		}
+
+		assert.Less(t, calls, 20)
+	})
+
+	t.Run("works with value types", func(t *testing.T) {
+		pool := NewPool(func() int { return 7 })
+		assert.Equal(t, 7, pool.Get())
+	})
+}
@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned by ResourcePool.Acquire after Close has been called.
+var ErrPoolClosed = errors.New("resource pool is closed")
+
+// PoolConfig configures a ResourcePool.
+type PoolConfig[T any] struct {
+	// New creates a resource when Acquire finds no usable idle one. Required.
+	New func(ctx context.Context) (T, error)
+
+	// Validate reports whether an idle resource is still usable. Acquire calls it before
+	// handing out an idle resource and discards the resource if it returns false.
+	// Nil treats every idle resource as usable.
+	Validate func(resource T) bool
+
+	// MaxSize is the maximum number of resources held by callers at once; Acquire blocks
+	// while it is reached. Zero or less means no limit.
+	MaxSize int
+
+	// MaxIdle is how long a released resource may stay idle before it is discarded.
+	// Zero or less means idle resources never expire.
+	MaxIdle time.Duration
+}
+
+// idleResource is a released resource and the time it was released.
+type idleResource[T any] struct {
+	resource T
+	since    time.Time
+}
+
+// ResourcePool keeps expensive resources such as network connections for reuse.
+// Unlike Pool, it bounds how many resources are in use, validates idle resources
+// before reusing them, and discards resources that stay idle too long. Resources
+// that implement io.Closer are closed when they are discarded. A ResourcePool is
+// safe for concurrent use.
+type ResourcePool[T any] struct {
+	cfg   PoolConfig[T]
+	slots chan struct{} // one entry per resource held by a caller; nil when unbounded
+
+	mu     sync.Mutex
+	idle   []idleResource[T] // oldest first
+	closed bool
+	done   chan struct{}
+}
+
+// NewResourcePool creates a ResourcePool from cfg. When cfg.MaxIdle is positive, a
+// background goroutine discards expired idle resources every MaxIdle until Close is called.
+//
+// Parameters:
+//   - cfg: Pool settings; cfg.New is required
+//
+// Returns:
+//   - A pointer to a new, empty ResourcePool[T]
+func NewResourcePool[T any](cfg PoolConfig[T]) *ResourcePool[T] {
+	p := &ResourcePool[T]{
+		cfg:  cfg,
+		done: make(chan struct{}),
+	}
+
+	if cfg.MaxSize > 0 {
+		p.slots = make(chan struct{}, cfg.MaxSize)
+	}
+
+	if cfg.MaxIdle > 0 {
+		go p.evictLoop()
+	}
+
+	return p
+}
+
+// Acquire returns an idle resource that passes Validate, most recently released
+// first, or creates a new one with cfg.New. It blocks while MaxSize resources are
+// held by callers. Every resource acquired must be passed to Release.
+//
+// Parameters:
+//   - ctx: Context bounding the wait for a free slot; also passed to cfg.New
+//
+// Returns:
+//   - A resource for the caller's exclusive use
+//   - ErrPoolClosed after Close, ctx.Err() if ctx is done first, or the error from cfg.New
+func (p *ResourcePool[T]) Acquire(ctx context.Context) (T, error) {
+	var zero T
+
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		case <-p.done:
+			return zero, ErrPoolClosed
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	for {
+		resource, ok, err := p.popIdle()
+		if err != nil {
+			p.freeSlot()
+			return zero, err
+		}
+		if !ok {
+			break
+		}
+		if p.cfg.Validate == nil || p.cfg.Validate(resource) {
+			return resource, nil
+		}
+		closeResource(resource)
+	}
+
+	resource, err := p.cfg.New(ctx)
+	if err != nil {
+		p.freeSlot()
+		return zero, err
+	}
+
+	return resource, nil
+}
+
+// Release returns a resource obtained from Acquire to the pool. After Close, the
+// resource is discarded instead.
+//
+// Parameters:
+//   - resource: The resource to return
+func (p *ResourcePool[T]) Release(resource T) {
+	p.mu.Lock()
+	closed := p.closed
+	if !closed {
+		p.idle = append(p.idle, idleResource[T]{resource: resource, since: time.Now()})
+	}
+	p.mu.Unlock()
+
+	if closed {
+		closeResource(resource)
+	}
+	p.freeSlot()
+}
+
+// Close discards all idle resources and stops the background eviction. Afterwards
+// Acquire returns ErrPoolClosed, including for callers waiting for a slot, and
+// resources still held by callers are discarded when released. Idempotent.
+func (p *ResourcePool[T]) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	close(p.done)
+	p.mu.Unlock()
+
+	for _, entry := range idle {
+		closeResource(entry.resource)
+	}
+}
+
+// popIdle removes and returns the most recently released idle resource, after
+// discarding expired ones. ok is false when there is no idle resource.
+func (p *ResourcePool[T]) popIdle() (resource T, ok bool, err error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return resource, false, ErrPoolClosed
+	}
+
+	expired := p.takeExpiredLocked(time.Now())
+	if n := len(p.idle); n > 0 {
+		resource, ok = p.idle[n-1].resource, true
+		p.idle[n-1] = idleResource[T]{}
+		p.idle = p.idle[:n-1]
+	}
+	p.mu.Unlock()
+
+	for _, entry := range expired {
+		closeResource(entry.resource)
+	}
+
+	return resource, ok, nil
+}
+
+// takeExpiredLocked removes and returns the idle resources released more than MaxIdle
+// before now. Callers must hold mu.
+func (p *ResourcePool[T]) takeExpiredLocked(now time.Time) []idleResource[T] {
+	if p.cfg.MaxIdle <= 0 {
+		return nil
+	}
+
+	n := 0
+	for n < len(p.idle) && now.Sub(p.idle[n].since) >= p.cfg.MaxIdle {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+
+	expired := make([]idleResource[T], n)
+	copy(expired, p.idle[:n])
+	p.idle = append(p.idle[:0], p.idle[n:]...)
+	return expired
+}
+
+// evictLoop discards expired idle resources every MaxIdle until Close is called.
+func (p *ResourcePool[T]) evictLoop() {
+	ticker := time.NewTicker(p.cfg.MaxIdle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case now := <-ticker.C:
+			p.mu.Lock()
+			expired := p.takeExpiredLocked(now)
+			p.mu.Unlock()
+
+			for _, entry := range expired {
+				closeResource(entry.resource)
+			}
+		}
+	}
+}
+
+// freeSlot gives back the slot taken by Acquire.
+func (p *ResourcePool[T]) freeSlot() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// closeResource closes resource if it implements io.Closer.
+func closeResource[T any](resource T) {
+	if c, ok := any(resource).(io.Closer); ok {
+		_ = c.Close()
+	}
+}
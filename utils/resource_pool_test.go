@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResource is a pooled resource that records whether it was closed.
+type testResource struct {
+	id     int
+	broken atomic.Bool
+	closed atomic.Bool
+}
+
+func (r *testResource) Close() error {
+	r.closed.Store(true)
+	return nil
+}
+
+// newTestPool creates a pool of testResources and a counter of resources created.
+func newTestPool(t *testing.T, maxSize int, maxIdle time.Duration) (*ResourcePool[*testResource], *atomic.Int32) {
+	created := &atomic.Int32{}
+	p := NewResourcePool(PoolConfig[*testResource]{
+		New: func(ctx context.Context) (*testResource, error) {
+			return &testResource{id: int(created.Add(1))}, nil
+		},
+		Validate: func(r *testResource) bool { return !r.broken.Load() },
+		MaxSize:  maxSize,
+		MaxIdle:  maxIdle,
+	})
+	t.Cleanup(p.Close)
+	return p, created
+}
+
+func TestResourcePool(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reuses released resources", func(t *testing.T) {
+		p, created := newTestPool(t, 0, 0)
+
+		r, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		p.Release(r)
+
+		again, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		assert.Same(t, r, again)
+		assert.Equal(t, int32(1), created.Load())
+	})
+
+	t.Run("respects MaxSize", func(t *testing.T) {
+		p, created := newTestPool(t, 2, 0)
+
+		first, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		_, err = p.Acquire(ctx)
+		require.NoError(t, err)
+
+		short, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+		defer cancel()
+		_, err = p.Acquire(short)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		acquired := make(chan *testResource, 1)
+		go func() {
+			r, err := p.Acquire(ctx)
+			if err == nil {
+				acquired <- r
+			}
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("Acquire returned while the pool was full")
+		case <-time.After(30 * time.Millisecond):
+		}
+
+		p.Release(first)
+		select {
+		case r := <-acquired:
+			assert.Same(t, first, r)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Acquire did not return after Release")
+		}
+		assert.Equal(t, int32(2), created.Load())
+	})
+
+	t.Run("validates on acquire", func(t *testing.T) {
+		p, created := newTestPool(t, 0, 0)
+
+		r, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		r.broken.Store(true)
+		p.Release(r)
+
+		fresh, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		assert.NotSame(t, r, fresh)
+		assert.True(t, r.closed.Load(), "invalid resource is closed")
+		assert.Equal(t, int32(2), created.Load())
+	})
+
+	t.Run("closes idle resources after MaxIdle", func(t *testing.T) {
+		p, _ := newTestPool(t, 0, 20*time.Millisecond)
+
+		r, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		p.Release(r)
+
+		assert.Eventually(t, r.closed.Load, 2*time.Second, 5*time.Millisecond)
+
+		fresh, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		assert.NotSame(t, r, fresh)
+	})
+
+	t.Run("New errors free the slot", func(t *testing.T) {
+		fail := errors.New("dial failed")
+		p := NewResourcePool(PoolConfig[*testResource]{
+			New:     func(ctx context.Context) (*testResource, error) { return nil, fail },
+			MaxSize: 1,
+		})
+		defer p.Close()
+
+		for i := 0; i < 3; i++ {
+			_, err := p.Acquire(ctx)
+			assert.ErrorIs(t, err, fail)
+		}
+	})
+
+	t.Run("Close discards idle resources", func(t *testing.T) {
+		p, _ := newTestPool(t, 0, 0)
+
+		r, err := p.Acquire(ctx)
+		require.NoError(t, err)
+		p.Release(r)
+
+		p.Close()
+		p.Close()
+		assert.True(t, r.closed.Load())
+
+		_, err = p.Acquire(ctx)
+		assert.ErrorIs(t, err, ErrPoolClosed)
+	})
+
+	t.Run("Close releases waiters and discards resources released later", func(t *testing.T) {
+		p, _ := newTestPool(t, 1, 0)
+
+		held, err := p.Acquire(ctx)
+		require.NoError(t, err)
+
+		waiting := make(chan error, 1)
+		go func() {
+			_, err := p.Acquire(ctx)
+			waiting <- err
+		}()
+
+		p.Close()
+		select {
+		case err := <-waiting:
+			assert.ErrorIs(t, err, ErrPoolClosed)
+		case <-time.After(2 * time.Second):
+			t.Fatal("waiting Acquire not released by Close")
+		}
+
+		assert.False(t, held.closed.Load())
+		p.Release(held)
+		assert.True(t, held.closed.Load())
+	})
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Backoff describes a geometrically growing delay between retry attempts. The
+// zero value waits 100ms, doubling after each attempt without a cap.
+type Backoff struct {
+	// Initial is the delay after the first failed attempt; <= 0 means 100ms.
+	Initial time.Duration
+
+	// Max caps the delay; <= 0 means no cap.
+	Max time.Duration
+
+	// Multiplier scales the delay after each attempt; values below 1 mean 2.
+	Multiplier float64
+}
+
+// Delay returns the wait after the given failed attempt, counting from 0.
+//
+// Parameters:
+//   - attempt: Zero-based index of the attempt that just failed
+//
+// Returns:
+//   - Initial * Multiplier^attempt, capped at Max
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	if delay <= 0 {
+		delay = 100 * time.Millisecond
+	}
+
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	for i := 0; i < attempt; i++ {
+		delay = time.Duration(float64(delay) * multiplier)
+		if b.Max > 0 && delay >= b.Max {
+			return b.Max
+		}
+	}
+
+	if b.Max > 0 && delay > b.Max {
+		return b.Max
+	}
+
+	return delay
+}
+
+// RetryUntil calls fn until it succeeds, the deadline passes, or ctx is done,
+// waiting between attempts as described by backoff. fn is always called at
+// least once. No attempt is started after the deadline: if the next wait
+// would end past it, RetryUntil gives up immediately.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops retrying
+//   - deadline: Time after which no further attempts are made
+//   - backoff: Delay schedule between attempts
+//   - fn: The operation to retry
+//
+// Returns:
+//   - nil if fn succeeded
+//   - The last error from fn once the deadline is reached
+//   - The last error from fn joined with ctx.Err() if ctx is done first
+func RetryUntil(ctx context.Context, deadline time.Time, backoff Backoff, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		delay := backoff.Delay(attempt)
+		if time.Now().Add(delay).After(deadline) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
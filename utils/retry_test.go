@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond, Multiplier: 2}
+	assert.Equal(t, 10*time.Millisecond, b.Delay(0))
+	assert.Equal(t, 20*time.Millisecond, b.Delay(1))
+	assert.Equal(t, 40*time.Millisecond, b.Delay(2))
+	assert.Equal(t, 50*time.Millisecond, b.Delay(3))
+	assert.Equal(t, 50*time.Millisecond, b.Delay(100))
+
+	var zero Backoff
+	assert.Equal(t, 100*time.Millisecond, zero.Delay(0))
+	assert.Equal(t, 200*time.Millisecond, zero.Delay(1))
+}
+
+func TestRetryUntil(t *testing.T) {
+	backoff := Backoff{Initial: 5 * time.Millisecond, Max: 20 * time.Millisecond}
+
+	t.Run("succeeds before the deadline", func(t *testing.T) {
+		calls := 0
+		err := RetryUntil(context.Background(), time.Now().Add(2*time.Second), backoff, func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("returns the last error once the deadline passes", func(t *testing.T) {
+		calls := 0
+		start := time.Now()
+		err := RetryUntil(context.Background(), start.Add(100*time.Millisecond), backoff, func() error {
+			calls++
+			return errors.New("attempt failed")
+		})
+		assert.EqualError(t, err, "attempt failed")
+		assert.Greater(t, calls, 1)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("calls fn once when the deadline has already passed", func(t *testing.T) {
+		calls := 0
+		err := RetryUntil(context.Background(), time.Now().Add(-time.Second), backoff, func() error {
+			calls++
+			return errors.New("too late")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops when ctx is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		fnErr := errors.New("attempt failed")
+		err := RetryUntil(ctx, time.Now().Add(time.Minute), backoff, func() error {
+			cancel()
+			return fnErr
+		})
+		assert.ErrorIs(t, err, fnErr)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
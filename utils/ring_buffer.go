@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RingBuffer holds the most recent items pushed to it, up to a fixed capacity.
+// Once full, each Push overwrites the oldest item. A RingBuffer is safe for
+// concurrent use.
+type RingBuffer[T any] struct {
+	mu    sync.Mutex
+	items []T
+	start int // index of the oldest item
+	count int
+}
+
+// NewRingBuffer creates an empty RingBuffer that holds up to capacity items.
+//
+// Parameters:
+//   - capacity: Maximum number of items kept; values below 1 panic
+//
+// Returns:
+//   - A pointer to a new, empty RingBuffer
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	if capacity < 1 {
+		panic(fmt.Sprintf("utils: ring buffer capacity must be at least 1, got %d", capacity))
+	}
+
+	return &RingBuffer[T]{items: make([]T, capacity)}
+}
+
+// Push adds v as the newest item, overwriting the oldest item if the buffer is full.
+//
+// Parameters:
+//   - v: The item to add
+func (r *RingBuffer[T]) Push(v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.count < len(r.items) {
+		r.items[(r.start+r.count)%len(r.items)] = v
+		r.count++
+		return
+	}
+
+	r.items[r.start] = v
+	r.start = (r.start + 1) % len(r.items)
+}
+
+// Slice returns a copy of the items, oldest first.
+//
+// Returns:
+//   - A new slice of Len items ordered from oldest to newest
+func (r *RingBuffer[T]) Slice() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]T, r.count)
+	for i := range out {
+		out[i] = r.items[(r.start+i)%len(r.items)]
+	}
+
+	return out
+}
+
+// Len returns the number of items in the buffer, at most its capacity.
+//
+// Returns:
+//   - The number of items held
+func (r *RingBuffer[T]) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer(t *testing.T) {
+	t.Run("keeps insertion order before filling", func(t *testing.T) {
+		r := NewRingBuffer[string](3)
+		assert.Equal(t, 0, r.Len())
+		assert.Empty(t, r.Slice())
+
+		r.Push("a")
+		r.Push("b")
+		assert.Equal(t, 2, r.Len())
+		assert.Equal(t, []string{"a", "b"}, r.Slice())
+	})
+
+	t.Run("wraparound overwrites the oldest", func(t *testing.T) {
+		r := NewRingBuffer[int](3)
+		for i := 1; i <= 7; i++ {
+			r.Push(i)
+		}
+
+		assert.Equal(t, 3, r.Len())
+		assert.Equal(t, []int{5, 6, 7}, r.Slice())
+
+		r.Push(8)
+		assert.Equal(t, []int{6, 7, 8}, r.Slice())
+	})
+
+	t.Run("slice is a copy", func(t *testing.T) {
+		r := NewRingBuffer[int](2)
+		r.Push(1)
+		s := r.Slice()
+		s[0] = 99
+		assert.Equal(t, []int{1}, r.Slice())
+	})
+
+	t.Run("capacity of one", func(t *testing.T) {
+		r := NewRingBuffer[int](1)
+		r.Push(1)
+		r.Push(2)
+		assert.Equal(t, []int{2}, r.Slice())
+	})
+
+	t.Run("concurrent pushes", func(t *testing.T) {
+		r := NewRingBuffer[int](10)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					r.Push(j)
+					_ = r.Slice()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 10, r.Len())
+	})
+
+	t.Run("invalid capacity panics", func(t *testing.T) {
+		assert.Panics(t, func() { NewRingBuffer[int](0) })
+	})
+}
@@ -0,0 +1,37 @@
+package utils
+
+// SafeDiv divides a by b without panicking on a zero divisor. Integer division
+// truncates toward zero like the / operator.
+//
+// Parameters:
+//   - a: The dividend
+//   - b: The divisor
+//
+// Returns:
+//   - a / b and true; or the zero value and false if b is zero
+func SafeDiv[T Numeric](a, b T) (T, bool) {
+	if b == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return a / b, true
+}
+
+// Percentage returns part as a percentage of total, e.g. 25 for (1, 4), for
+// ratios such as cache hit rates. It returns 0 rather than NaN or Inf when
+// total is zero.
+//
+// Parameters:
+//   - part: The portion to express as a percentage
+//   - total: The whole that part is measured against
+//
+// Returns:
+//   - part / total * 100, or 0 if total is zero
+func Percentage(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return part / total * 100
+}
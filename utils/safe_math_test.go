@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeDiv(t *testing.T) {
+	t.Run("integers truncate", func(t *testing.T) {
+		q, ok := SafeDiv(7, 2)
+		assert.True(t, ok)
+		assert.Equal(t, 3, q)
+
+		q, ok = SafeDiv(-7, 2)
+		assert.True(t, ok)
+		assert.Equal(t, -3, q)
+	})
+
+	t.Run("floats", func(t *testing.T) {
+		q, ok := SafeDiv(1.0, 4.0)
+		assert.True(t, ok)
+		assert.Equal(t, 0.25, q)
+	})
+
+	t.Run("zero divisor", func(t *testing.T) {
+		q, ok := SafeDiv(10, 0)
+		assert.False(t, ok)
+		assert.Equal(t, 0, q)
+
+		f, ok := SafeDiv(1.5, 0)
+		assert.False(t, ok)
+		assert.Equal(t, 0.0, f)
+
+		u, ok := SafeDiv[uint8](0, 0)
+		assert.False(t, ok)
+		assert.Equal(t, uint8(0), u)
+	})
+}
+
+func TestPercentage(t *testing.T) {
+	assert.Equal(t, 25.0, Percentage(1, 4))
+	assert.Equal(t, 100.0, Percentage(3, 3))
+	assert.InDelta(t, 66.667, Percentage(2, 3), 1e-3)
+
+	assert.Equal(t, 0.0, Percentage(5, 0))
+	assert.Equal(t, 0.0, Percentage(0, 0))
+	assert.False(t, math.IsNaN(Percentage(0, 0)))
+}
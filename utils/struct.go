@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StructDiff compares two values of the same struct type field by field and
+// returns the fields whose values differ. Keys use the field's json tag name,
+// falling back to the Go field name; fields tagged `json:"-"` and unexported
+// fields are skipped. Nested structs are compared recursively and reported with
+// dotted keys (e.g. "address.city"), while embedded structs without a json tag
+// are flattened into the parent. Structs without exported fields (such as
+// time.Time) and all other values are compared with reflect.DeepEqual.
+//
+// Parameters:
+//   - old: The previous version; a struct or a non-nil pointer to one
+//   - new: The current version; must have the same type as old
+//
+// Returns:
+//   - A map from field key to [old value, new value] for every changed field (empty if equal)
+//   - An error if either argument is not a struct or the types differ
+func StructDiff(old, new any) (map[string][2]any, error) {
+	oldValue, err := structValue(old)
+	if err != nil {
+		return nil, fmt.Errorf("old: %w", err)
+	}
+	newValue, err := structValue(new)
+	if err != nil {
+		return nil, fmt.Errorf("new: %w", err)
+	}
+	if oldValue.Type() != newValue.Type() {
+		return nil, fmt.Errorf("type mismatch: %s and %s", oldValue.Type(), newValue.Type())
+	}
+
+	diff := make(map[string][2]any)
+	diffStructFields(oldValue, newValue, "", diff)
+	return diff, nil
+}
+
+func structValue(v any) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil pointer")
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("expected a struct, got %T", v)
+	}
+	return value, nil
+}
+
+func diffStructFields(oldValue, newValue reflect.Value, prefix string, diff map[string][2]any) {
+	structType := oldValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		// Like encoding/json, promote fields of embedded structs even when the embedded type is unexported
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		name, tagged := field.Name, false
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name, tagged = tagName, true
+			}
+		}
+
+		// Unexported embedded structs are always recursed into, since their values
+		// cannot be read through Interface
+		oldField, newField := oldValue.Field(i), newValue.Field(i)
+		if field.Type.Kind() == reflect.Struct && (!field.IsExported() || hasExportedFields(field.Type)) {
+			nestedPrefix := prefix + name + "."
+			if field.Anonymous && !tagged {
+				nestedPrefix = prefix
+			}
+			diffStructFields(oldField, newField, nestedPrefix, diff)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			diff[prefix+name] = [2]any{oldField.Interface(), newField.Interface()}
+		}
+	}
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() || (field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			return true
+		}
+	}
+	return false
+}
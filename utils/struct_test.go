@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type diffAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type diffMeta struct {
+	Version int
+}
+
+type diffUser struct {
+	diffMeta
+	ID        int         `json:"id"`
+	Name      string      `json:"name,omitempty"`
+	Email     string      // no tag, keyed by field name
+	Password  string      `json:"-"`
+	Tags      []string    `json:"tags"`
+	Address   diffAddress `json:"address"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	secret    string
+}
+
+func TestStructDiff(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := diffUser{
+		diffMeta:  diffMeta{Version: 1},
+		ID:        1,
+		Name:      "alice",
+		Email:     "alice@example.com",
+		Password:  "old",
+		Tags:      []string{"a"},
+		Address:   diffAddress{City: "Paris", Zip: "75001"},
+		UpdatedAt: now,
+		secret:    "x",
+	}
+
+	t.Run("reports only changed fields", func(t *testing.T) {
+		updated := base
+		updated.diffMeta.Version = 2
+		updated.Name = "alicia"
+		updated.Password = "new"
+		updated.Tags = []string{"a", "b"}
+		updated.Address.City = "Lyon"
+		updated.UpdatedAt = now.Add(time.Hour)
+		updated.secret = "y"
+
+		diff, err := StructDiff(base, &updated)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][2]any{
+			"Version":      {1, 2},
+			"name":         {"alice", "alicia"},
+			"tags":         {[]string{"a"}, []string{"a", "b"}},
+			"address.city": {"Paris", "Lyon"},
+			"updated_at":   {now, now.Add(time.Hour)},
+		}, diff)
+	})
+
+	t.Run("identical values produce an empty diff", func(t *testing.T) {
+		diff, err := StructDiff(base, base)
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("non-struct and mismatched types return errors", func(t *testing.T) {
+		_, err := StructDiff(1, 2)
+		assert.Error(t, err)
+
+		_, err = StructDiff(base, diffAddress{})
+		assert.Error(t, err)
+
+		var nilUser *diffUser
+		_, err = StructDiff(nilUser, base)
+		assert.Error(t, err)
+	})
+
+	t.Run("unexported embedded struct without exported fields is skipped", func(t *testing.T) {
+		type hidden struct{ x int }
+		type wrapper struct {
+			hidden
+			Name string
+		}
+
+		diff, err := StructDiff(wrapper{hidden{1}, "a"}, wrapper{hidden{2}, "b"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][2]any{"Name": {"a", "b"}}, diff)
+	})
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+)
+
+// WaitGroup runs functions in goroutines, waits for them to finish and
+// collects the errors they return. Unlike sync.WaitGroup it can bound the
+// number of goroutines running at once. By default Wait returns the first
+// error; WithErrorAggregation makes it return all errors joined together.
+// A WaitGroup must not be copied after first use.
+type WaitGroup struct {
+	wg        sync.WaitGroup
+	sem       chan struct{}
+	aggregate bool
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// WaitGroupOption configures a WaitGroup created by NewWaitGroup.
+type WaitGroupOption func(*WaitGroup)
+
+// WithErrorAggregation makes Wait return every non-nil error, combined with
+// errors.Join in the order the functions returned, instead of only the first.
+func WithErrorAggregation() WaitGroupOption {
+	return func(g *WaitGroup) {
+		g.aggregate = true
+	}
+}
+
+// NewWaitGroup creates a WaitGroup that runs at most limit functions at once.
+// A limit of zero or less means no limit.
+//
+// Parameters:
+//   - limit: Maximum number of functions running concurrently; <= 0 for unbounded
+//   - opts: Optional settings such as WithErrorAggregation
+//
+// Returns:
+//   - A pointer to a new WaitGroup
+func NewWaitGroup(limit int, opts ...WaitGroupOption) *WaitGroup {
+	g := &WaitGroup{}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Go runs fn in a new goroutine. When the concurrency limit has been reached,
+// Go blocks until one of the running functions returns.
+//
+// Parameters:
+//   - fn: Function to run; its error is recorded for Wait
+func (g *WaitGroup) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+			g.wg.Done()
+		}()
+
+		if err := fn(); err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until every function started with Go has returned.
+//
+// Returns:
+//   - nil if no function failed; otherwise the first error, or all errors
+//     joined with errors.Join when WithErrorAggregation is set
+func (g *WaitGroup) Wait() error {
+	g.wg.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.errs) == 0 {
+		return nil
+	}
+	if g.aggregate {
+		return errors.Join(g.errs...)
+	}
+	return g.errs[0]
+}
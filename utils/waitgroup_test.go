@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitGroup(t *testing.T) {
+	t.Run("returns nil when all functions succeed", func(t *testing.T) {
+		g := NewWaitGroup(0)
+		var count atomic.Int32
+		for i := 0; i < 10; i++ {
+			g.Go(func() error {
+				count.Add(1)
+				return nil
+			})
+		}
+
+		assert.NoError(t, g.Wait())
+		assert.Equal(t, int32(10), count.Load())
+	})
+
+	t.Run("returns the first error by default", func(t *testing.T) {
+		errFirst := errors.New("first")
+		g := NewWaitGroup(1)
+		g.Go(func() error { return errFirst })
+		g.Go(func() error { return errors.New("second") })
+		g.Go(func() error { return nil })
+
+		assert.Equal(t, errFirst, g.Wait())
+	})
+
+	t.Run("aggregates errors when configured", func(t *testing.T) {
+		errA := errors.New("a")
+		errB := errors.New("b")
+		g := NewWaitGroup(0, WithErrorAggregation())
+		g.Go(func() error { return errA })
+		g.Go(func() error { return nil })
+		g.Go(func() error { return errB })
+
+		err := g.Wait()
+		assert.ErrorIs(t, err, errA)
+		assert.ErrorIs(t, err, errB)
+	})
+
+	t.Run("limits concurrency", func(t *testing.T) {
+		const limit = 3
+		g := NewWaitGroup(limit)
+		var mu sync.Mutex
+		running, peak := 0, 0
+		for i := 0; i < 20; i++ {
+			g.Go(func() error {
+				mu.Lock()
+				running++
+				peak = max(peak, running)
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return nil
+			})
+		}
+
+		assert.NoError(t, g.Wait())
+		assert.Equal(t, limit, peak)
+	})
+}